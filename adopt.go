@@ -0,0 +1,75 @@
+package launcher
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"os/exec"
+	"time"
+)
+
+var errNoSuchProcess = errors.New("no process exists with the given pid")
+var errAdopted = errors.New("operation is not supported by an adopted process")
+
+// adoptPollInterval is the pause between checks that
+// an adopted process is still running
+const adoptPollInterval = 100 * time.Millisecond
+
+// Adopt returns a restricted Launcher for an existing process which it
+// did not start, such as one launched by StartDetached before the caller
+// restarted.  The Launcher can Signal the process, report whether it
+// IsRunning and Wait for it to exit, but has no access to its stdio and
+// cannot be started.  As the process is not a child, its exit status is
+// unknown, so Wait returns nil and ExitCode returns -1.  The process is
+// killed when the context is cancelled
+func Adopt(ctx context.Context, pid int) (*Launcher, error) {
+	if ctx == nil {
+		return nil, ErrMissingContext
+	}
+	if pid <= 0 || !processAlive(pid) {
+		return nil, fmt.Errorf("%w: %d", errNoSuchProcess, pid)
+	}
+
+	proc, err := os.FindProcess(pid)
+	if err != nil {
+		return nil, err
+	}
+
+	myCtx, cancel := context.WithCancelCause(ctx)
+	l := &Launcher{
+		ctx:       myCtx,
+		cancel:    cancel,
+		cmd:       &exec.Cmd{Process: proc},
+		state:     StateRunning,
+		done:      make(chan struct{}),
+		adopted:   true,
+		startedAt: time.Now(),
+	}
+
+	go func() {
+		defer close(l.done)
+
+		awaitExit(l.ctx, pid)
+		if l.ctx.Err() != nil && proc.Kill() == nil {
+			awaitExit(context.Background(), pid)
+		}
+		l.exited(nil)
+	}()
+
+	return l, nil
+}
+
+// pollExit blocks until no process with the id exists, or the context ends
+func pollExit(ctx context.Context, pid int) {
+	ticker := time.NewTicker(adoptPollInterval)
+	defer ticker.Stop()
+
+	for processAlive(pid) {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
@@ -0,0 +1,35 @@
+//go:build linux
+
+package launcher
+
+import (
+	"context"
+
+	"golang.org/x/sys/unix"
+)
+
+// awaitExit blocks until the process exits, or the context ends, using a
+// pidfd where the kernel supports it and polling for the process otherwise
+func awaitExit(ctx context.Context, pid int) {
+	fd, err := unix.PidfdOpen(pid, 0)
+	if err != nil {
+		pollExit(ctx, pid)
+		return
+	}
+	defer unix.Close(fd)
+
+	fds := []unix.PollFd{{Fd: int32(fd), Events: unix.POLLIN}}
+	for ctx.Err() == nil {
+		n, err := unix.Poll(fds, int(adoptPollInterval.Milliseconds()))
+		if err == unix.EINTR {
+			continue
+		}
+		if err != nil {
+			pollExit(ctx, pid)
+			return
+		}
+		if n > 0 {
+			return
+		}
+	}
+}
@@ -0,0 +1,10 @@
+//go:build !linux
+
+package launcher
+
+import "context"
+
+// awaitExit blocks until the process exits, or the context ends
+func awaitExit(ctx context.Context, pid int) {
+	pollExit(ctx, pid)
+}
@@ -0,0 +1,98 @@
+package launcher
+
+import (
+	"context"
+	"os"
+	"testing"
+	"time"
+)
+
+func TestAdopt(t *testing.T) {
+
+	d, err := New(context.Background(), "sleep", nil, "10")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer d.Close()
+
+	pid, err := d.StartDetached()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	l, err := Adopt(context.Background(), pid)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer l.Close()
+
+	if !l.IsRunning() || l.Pid() != pid {
+		t.Fatalf("expected adopted process %v to be running\n", pid)
+	}
+	if err := l.Start(); err != errAdopted {
+		t.Fatalf("expected errAdopted, got %v\n", err)
+	}
+	if err := l.SendStdIn([]byte("x")); err != errAdopted {
+		t.Fatalf("expected errAdopted, got %v\n", err)
+	}
+
+	if err := l.Signal(os.Kill); err != nil {
+		t.Fatal(err)
+	}
+
+	waited := make(chan error, 1)
+	go func() { waited <- l.Wait() }()
+
+	select {
+	case err := <-waited:
+		if err != nil {
+			t.Fatal(err)
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatal("timed out waiting for adopted process")
+	}
+
+	if l.IsRunning() || l.ExitCode() != -1 {
+		t.Fatal("expected adopted process to have exited")
+	}
+}
+
+func TestAdoptCancel(t *testing.T) {
+
+	d, err := New(context.Background(), "sleep", nil, "10")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer d.Close()
+
+	pid, err := d.StartDetached()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	l, err := Adopt(ctx, pid)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer l.Close()
+
+	cancel()
+	l.Wait()
+
+	// The process may briefly remain until reaped by its parent
+	deadline := time.Now().Add(5 * time.Second)
+	for processAlive(pid) {
+		if time.Now().After(deadline) {
+			t.Fatal("expected adopted process to be killed on cancellation")
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+}
+
+func TestAdoptNoProcess(t *testing.T) {
+
+	if _, err := Adopt(context.Background(), 0); err == nil {
+		t.Fatal("expected error adopting invalid pid")
+	}
+}
@@ -0,0 +1,9 @@
+//go:build !unix && !windows
+
+package launcher
+
+// processAlive cannot determine whether a process exists on
+// this platform, so assumes it does not
+func processAlive(pid int) bool {
+	return false
+}
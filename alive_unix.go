@@ -0,0 +1,11 @@
+//go:build unix
+
+package launcher
+
+import "syscall"
+
+// processAlive returns true if a process with the id exists
+func processAlive(pid int) bool {
+	err := syscall.Kill(pid, 0)
+	return err == nil || err == syscall.EPERM
+}
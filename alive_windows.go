@@ -0,0 +1,29 @@
+//go:build windows
+
+package launcher
+
+import "syscall"
+
+const (
+	// processQueryLimitedInformation is the access right needed
+	// to query the exit code of a process
+	processQueryLimitedInformation = 0x1000
+	// stillActive is the exit code of a process which has not exited
+	stillActive = 259
+)
+
+// processAlive returns true if a process with the id exists
+// and has not exited
+func processAlive(pid int) bool {
+	h, err := syscall.OpenProcess(processQueryLimitedInformation, false, uint32(pid))
+	if err != nil {
+		return false
+	}
+	defer syscall.CloseHandle(h)
+
+	var code uint32
+	if err := syscall.GetExitCodeProcess(h, &code); err != nil {
+		return false
+	}
+	return code == stillActive
+}
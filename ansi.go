@@ -0,0 +1,89 @@
+package launcher
+
+import "io"
+
+// WithStripANSI removes ANSI escape sequences, such as those setting
+// colours or moving the cursor, from the stdout and stderr of the
+// process before they are seen by any reader of the output, since
+// many tools write them even when their output is not a terminal
+func WithStripANSI() Option {
+	return func(o *options) error {
+		o.stripANSI = true
+		return nil
+	}
+}
+
+// ansiState is the position of an ansiStripper within an escape sequence
+type ansiState int
+
+const (
+	ansiText ansiState = iota
+	ansiEscape
+	ansiCSI
+	ansiString
+	ansiStringEscape
+)
+
+// ansiStripper is an io.Writer which passes output to w with escape
+// sequences removed, including those split across writes
+type ansiStripper struct {
+	w     io.Writer
+	state ansiState
+	out   []byte
+}
+
+// stripANSI is an outputFilter removing escape sequences
+func stripANSI(w io.Writer) io.Writer {
+	return &ansiStripper{w: w}
+}
+
+func (a *ansiStripper) Write(b []byte) (int, error) {
+	out := a.out[:0]
+	for _, c := range b {
+		switch a.state {
+		case ansiText:
+			if c == 0x1b {
+				a.state = ansiEscape
+			} else {
+				out = append(out, c)
+			}
+		case ansiEscape:
+			switch {
+			case c == '[':
+				a.state = ansiCSI
+			case c == ']' || c == 'P' || c == '^' || c == '_' || c == 'X':
+				// OSC, DCS, PM, APC and SOS run until a terminator
+				a.state = ansiString
+			case c >= 0x20 && c <= 0x2f:
+				// Intermediate bytes precede the final byte
+			default:
+				a.state = ansiText
+			}
+		case ansiCSI:
+			if c >= 0x40 && c <= 0x7e {
+				a.state = ansiText
+			}
+		case ansiString:
+			switch c {
+			case 0x07:
+				a.state = ansiText
+			case 0x1b:
+				a.state = ansiStringEscape
+			}
+		case ansiStringEscape:
+			if c == '\\' {
+				a.state = ansiText
+			} else if c != 0x1b {
+				a.state = ansiString
+			}
+		}
+	}
+	a.out = out
+
+	if len(out) > 0 {
+		if _, err := a.w.Write(out); err != nil {
+			return 0, err
+		}
+	}
+	return len(b), nil
+}
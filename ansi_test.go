@@ -0,0 +1,59 @@
+package launcher
+
+import (
+	"bytes"
+	"context"
+	"testing"
+)
+
+func TestStripANSI(t *testing.T) {
+
+	tests := []struct {
+		input    []string
+		expected string
+	}{
+		{[]string{"\x1b[1;31mred\x1b[0m text"}, "red text"},
+		{[]string{"a\x1b[", "2", "Kb"}, "ab"},
+		{[]string{"\x1b]0;title\x07shown"}, "shown"},
+		{[]string{"\x1b]8;;http://x\x1b", "\\link\x1b]8;;\x1b\\"}, "link"},
+		{[]string{"\x1b(Bcharset\x1b="}, "charset"},
+		{[]string{"plain\n"}, "plain\n"},
+	}
+
+	for _, test := range tests {
+		var b bytes.Buffer
+		w := stripANSI(&b)
+		for _, s := range test.input {
+			w.Write([]byte(s))
+		}
+		if b.String() != test.expected {
+			t.Fatalf("expected %q for %q, got %q\n", test.expected, test.input, b.String())
+		}
+	}
+}
+
+func TestWithStripANSI(t *testing.T) {
+
+	script := `printf '\033[32mok\033[0m\n'; printf '\033[31merr\033[0m\n' >&2`
+
+	l, err := NewWithOptions(context.Background(), "sh", nil, []string{"-c", script}, WithStripANSI(), WithOutputRingBuffer(2))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer l.Close()
+
+	var stdout, stderr bytes.Buffer
+	if err := l.Start(); err != nil {
+		t.Fatal(err)
+	}
+	l.copyOutput(&stdout, &stderr)
+
+	if stdout.String() != "ok\n" || stderr.String() != "err\n" {
+		t.Fatalf("unexpected output %q and %q\n", stdout.String(), stderr.String())
+	}
+	for _, line := range l.LastOutput() {
+		if line != "ok" && line != "err" {
+			t.Fatalf("unexpected retained line %q\n", line)
+		}
+	}
+}
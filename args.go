@@ -0,0 +1,151 @@
+package launcher
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+)
+
+var errInvalidFlag = errors.New("invalid flag")
+
+var errInvalidKey = errors.New("invalid key")
+
+// ArgBuilder assembles the arguments of a command, validating flags
+// as they are added.  The first error is returned by Build
+type ArgBuilder struct {
+	args       []string
+	positional bool
+	err        error
+}
+
+// Args returns a new ArgBuilder
+func Args() *ArgBuilder {
+	return &ArgBuilder{}
+}
+
+// Flag adds the flags, such as -v or --verbose
+func (b *ArgBuilder) Flag(names ...string) *ArgBuilder {
+	for _, name := range names {
+		if b.checkFlag(name) {
+			b.args = append(b.args, name)
+		}
+	}
+	return b
+}
+
+// FlagIf adds the flag only if the condition is true
+func (b *ArgBuilder) FlagIf(cond bool, name string) *ArgBuilder {
+	if cond {
+		return b.Flag(name)
+	}
+	return b
+}
+
+// Repeat adds the flag n times, as used by flags such as -v
+// whose repetition increases their effect
+func (b *ArgBuilder) Repeat(name string, n int) *ArgBuilder {
+	for i := 0; i < n; i++ {
+		b.Flag(name)
+	}
+	return b
+}
+
+// Option adds the flag followed by its value as a separate argument,
+// once for each value, so that an option may be repeated
+func (b *ArgBuilder) Option(name string, values ...string) *ArgBuilder {
+	if !b.checkFlag(name) {
+		return b
+	}
+	for _, v := range values {
+		b.args = append(b.args, name, v)
+	}
+	return b
+}
+
+// OptionEq adds the flag and its value as the single argument name=value
+func (b *ArgBuilder) OptionEq(name, value string) *ArgBuilder {
+	if b.checkFlag(name) {
+		b.args = append(b.args, name+"="+value)
+	}
+	return b
+}
+
+// KeyValue adds the flag followed by key=value, as used by options
+// such as -e KEY=VALUE
+func (b *ArgBuilder) KeyValue(name, key, value string) *ArgBuilder {
+	if !b.checkFlag(name) || !b.checkKey(key) {
+		return b
+	}
+	b.args = append(b.args, name, key+"="+value)
+	return b
+}
+
+// KeyValues adds KeyValue for each entry of the map, ordered by key
+func (b *ArgBuilder) KeyValues(name string, kv map[string]string) *ArgBuilder {
+	keys := make([]string, 0, len(kv))
+	for k := range kv {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	for _, k := range keys {
+		b.KeyValue(name, k, kv[k])
+	}
+	return b
+}
+
+// Positional adds the positional arguments, which follow all flags.
+// If any begins with "-", the arguments are preceded by "--" so that
+// they cannot be mistaken for flags
+func (b *ArgBuilder) Positional(args ...string) *ArgBuilder {
+	if !b.positional {
+		for _, a := range args {
+			if strings.HasPrefix(a, "-") {
+				b.args = append(b.args, "--")
+				b.positional = true
+				break
+			}
+		}
+	}
+	b.args = append(b.args, args...)
+	return b
+}
+
+// Build returns the assembled arguments, or the first error encountered
+func (b *ArgBuilder) Build() ([]string, error) {
+	if b.err != nil {
+		return nil, b.err
+	}
+	return append([]string{}, b.args...), nil
+}
+
+// checkFlag records an error if the name is not a valid flag, or if
+// it follows a "--" separator, returning true if it is valid
+func (b *ArgBuilder) checkFlag(name string) bool {
+	switch {
+	case b.err != nil:
+		return false
+	case b.positional:
+		b.err = fmt.Errorf("%w: %q follows positional arguments", errInvalidFlag, name)
+	case len(strings.TrimLeft(name, "-")) == 0 || !strings.HasPrefix(name, "-"):
+		b.err = fmt.Errorf("%w: %q", errInvalidFlag, name)
+	case strings.ContainsAny(name, " \t\n="):
+		b.err = fmt.Errorf("%w: %q", errInvalidFlag, name)
+	default:
+		return true
+	}
+	return false
+}
+
+// checkKey records an error if the key is not valid in a
+// key=value pair, returning true if it is valid
+func (b *ArgBuilder) checkKey(key string) bool {
+	if b.err != nil {
+		return false
+	}
+	if key == "" || strings.Contains(key, "=") {
+		b.err = fmt.Errorf("%w: %q", errInvalidKey, key)
+		return false
+	}
+	return true
+}
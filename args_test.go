@@ -0,0 +1,80 @@
+package launcher
+
+import (
+	"context"
+	"errors"
+	"io"
+	"reflect"
+	"testing"
+)
+
+func TestArgBuilder(t *testing.T) {
+
+	args, err := Args().
+		Flag("-v").
+		Repeat("-d", 2).
+		FlagIf(false, "--quiet").
+		Option("--out", "out.txt").
+		Option("-I", "a", "b").
+		OptionEq("--level", "3").
+		KeyValues("-e", map[string]string{"B": "2", "A": "1"}).
+		Positional("x", "-y").
+		Positional("z").
+		Build()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	expected := []string{
+		"-v", "-d", "-d", "--out", "out.txt", "-I", "a", "-I", "b", "--level=3",
+		"-e", "A=1", "-e", "B=2", "--", "x", "-y", "z",
+	}
+	if !reflect.DeepEqual(args, expected) {
+		t.Fatalf("expected %q, got %q\n", expected, args)
+	}
+}
+
+func TestArgBuilderErrors(t *testing.T) {
+
+	tests := map[string]*ArgBuilder{
+		"empty flag":           Args().Flag(""),
+		"missing dash":         Args().Flag("v"),
+		"dashes only":          Args().Option("--", "x"),
+		"whitespace":           Args().Flag("-a b"),
+		"equals":               Args().OptionEq("--a=b", "c"),
+		"empty key":            Args().KeyValue("-e", "", "x"),
+		"key with equals":      Args().KeyValue("-e", "A=B", "x"),
+		"flag after separator": Args().Positional("-x").Flag("-v"),
+	}
+	for name, b := range tests {
+		if _, err := b.Build(); !errors.Is(err, errInvalidFlag) && !errors.Is(err, errInvalidKey) {
+			t.Fatalf("%s: expected invalid argument, got %v\n", name, err)
+		}
+	}
+}
+
+func TestArgBuilderLaunch(t *testing.T) {
+
+	args, err := Args().Flag("-n").Positional("foo", "bar").Build()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	l, err := New(context.Background(), "echo", nil, args...)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer l.Close()
+
+	if err := l.Run(); err != nil {
+		t.Fatal(err)
+	}
+	b, err := io.ReadAll(l.StdOutReader())
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if string(b) != "foo bar" {
+		t.Fatalf("unexpected output %q\n", b)
+	}
+}
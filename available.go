@@ -0,0 +1,56 @@
+package launcher
+
+import "io"
+
+// ReadAvailableStdOut returns up to max bytes of the stdout of the
+// process which have already been received, without blocking.  An
+// empty result with a nil error means no output is available yet,
+// whilst io.EOF is returned once all output has been read.  The first
+// call starts a goroutine pumping stdout into a buffer, which is then
+// also read by StdOutReader, so a reader obtained earlier must not
+// be read concurrently
+func (l *Launcher) ReadAvailableStdOut(max int) ([]byte, error) {
+	if max <= 0 {
+		return nil, errInvalidSize
+	}
+	buf := l.bufferOutput(&l.cmdStdOut, &l.stdOutSource)
+	if buf == nil {
+		return nil, io.EOF
+	}
+	return buf.readAvailable(max)
+}
+
+// ReadAvailableStdErr returns up to max bytes of the stderr of the
+// process without blocking, in the same way as ReadAvailableStdOut
+func (l *Launcher) ReadAvailableStdErr(max int) ([]byte, error) {
+	if max <= 0 {
+		return nil, errInvalidSize
+	}
+	buf := l.bufferOutput(&l.cmdStdErr, &l.stdErrSource)
+	if buf == nil {
+		return nil, io.EOF
+	}
+	return buf.readAvailable(max)
+}
+
+// bufferOutput returns the buffer of the output, replacing the pipe
+// read by the caller with a buffer pumped from it if required, or
+// nil if the output is not piped
+func (l *Launcher) bufferOutput(reader, source *io.ReadCloser) *pipeBuffer {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	if *reader == nil {
+		return nil
+	}
+	if buf, ok := (*reader).(*pipeBuffer); ok {
+		return buf
+	}
+
+	buf := newOutputBuffer(l.opts.lossyOutput)
+	*source, *reader = *reader, buf
+	if l.cmd.Process != nil {
+		l.startPumps()
+	}
+	return buf
+}
@@ -0,0 +1,77 @@
+package launcher
+
+import (
+	"context"
+	"io"
+	"testing"
+	"time"
+)
+
+func TestReadAvailableStdOut(t *testing.T) {
+
+	l, err := New(context.Background(), "sh", nil, "-c", "sleep 0.2; printf hello; sleep 0.2; printf world")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer l.Close()
+
+	if err := l.Start(); err != nil {
+		t.Fatal(err)
+	}
+
+	// Nothing has been written yet
+	b, err := l.ReadAvailableStdOut(100)
+	if err != nil || len(b) != 0 {
+		t.Fatalf("expected no output, got %q and %v\n", b, err)
+	}
+
+	var out []byte
+	deadline := time.Now().Add(5 * time.Second)
+	for time.Now().Before(deadline) {
+		b, err := l.ReadAvailableStdOut(3)
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			t.Fatal(err)
+		}
+		if len(b) > 3 {
+			t.Fatalf("expected at most 3 bytes, got %q\n", b)
+		}
+		out = append(out, b...)
+		time.Sleep(10 * time.Millisecond)
+	}
+
+	if string(out) != "helloworld" {
+		t.Fatalf("unexpected output %q\n", out)
+	}
+}
+
+func TestReadAvailableBeforeStart(t *testing.T) {
+
+	l, err := New(context.Background(), "sh", nil, "-c", "echo out; echo err >&2")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer l.Close()
+
+	if _, err := l.ReadAvailableStdErr(10); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := l.ReadAvailableStdErr(0); err != errInvalidSize {
+		t.Fatalf("expected errInvalidSize, got %v\n", err)
+	}
+	if err := l.Run(); err != nil {
+		t.Fatal(err)
+	}
+
+	// The buffered stream is also read by the reader
+	b, err := io.ReadAll(l.StdErrReader())
+	if err != nil || string(b) != "err\n" {
+		t.Fatalf("unexpected stderr %q and %v\n", b, err)
+	}
+	b, err = io.ReadAll(l.StdOutReader())
+	if err != nil || string(b) != "out\n" {
+		t.Fatalf("unexpected stdout %q and %v\n", b, err)
+	}
+}
@@ -0,0 +1,68 @@
+package launcher
+
+import (
+	"errors"
+	"os"
+	"time"
+)
+
+// ErrBinaryChanged is the cause recorded for a process terminated by
+// a Supervisor because its executable changed
+var ErrBinaryChanged = errors.New("executable of the process changed")
+
+// binaryPollInterval is the pause between checks of the executable
+const binaryPollInterval = 500 * time.Millisecond
+
+// WithRestartOnBinaryChange has a Supervisor of the process watch its
+// resolved executable, gracefully restarting the process once the
+// executable has changed, as when it has been rebuilt or redeployed,
+// regardless of the RestartPolicy.  A change is acted upon once the
+// executable has been stable for a check, so that a partially written
+// file is not run.  The option has no effect on an unsupervised process
+func WithRestartOnBinaryChange() Option {
+	return func(o *options) error {
+		o.binaryRestart = true
+		return nil
+	}
+}
+
+// watchBinary polls the executable of the process until it exits,
+// terminating it once the executable has changed from its initial state
+func (s *Supervisor) watchBinary(l *Launcher, initial os.FileInfo) {
+	path := l.GetPath()
+
+	ticker := time.NewTicker(binaryPollInterval)
+	defer ticker.Stop()
+
+	var previous os.FileInfo
+	for {
+		select {
+		case <-l.done:
+			return
+		case <-ticker.C:
+		}
+
+		current, err := os.Stat(path)
+		if err != nil || sameBinary(initial, current) {
+			previous = nil
+			continue
+		}
+		if previous == nil || !sameBinary(previous, current) {
+			previous = current
+			continue
+		}
+
+		s.mu.Lock()
+		s.binaryChanged = true
+		s.mu.Unlock()
+
+		s.emit(EventBinaryChanged, l.Pid(), nil)
+		l.terminate(l.done, defaultStopGrace, ErrBinaryChanged)
+		return
+	}
+}
+
+// sameBinary returns true if the file is unchanged
+func sameBinary(a, b os.FileInfo) bool {
+	return os.SameFile(a, b) && a.Size() == b.Size() && a.ModTime().Equal(b.ModTime())
+}
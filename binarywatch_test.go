@@ -0,0 +1,81 @@
+package launcher
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+func TestRestartOnBinaryChange(t *testing.T) {
+
+	dir := t.TempDir()
+	exe := filepath.Join(dir, "worker")
+	marker := filepath.Join(dir, "marker")
+
+	deploy := func(version string) {
+		script := fmt.Sprintf("#!/bin/sh\necho %s >> %s\nexec sleep 10\n", version, marker)
+		tmp := exe + ".tmp"
+		if err := os.WriteFile(tmp, []byte(script), 0o755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.Rename(tmp, exe); err != nil {
+			t.Fatal(err)
+		}
+	}
+	deploy("v1")
+
+	events := make(chan Event, 10)
+	s, err := NewSupervisor(context.Background(), Spec{File: exe, Options: []Option{WithRestartOnBinaryChange()}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	s.Events = events
+	if err := s.Start(); err != nil {
+		t.Fatal(err)
+	}
+	defer s.Stop(time.Second)
+
+	if e := <-events; e.Type != EventStarted {
+		t.Fatalf("expected started event, got %+v\n", e)
+	}
+	first := s.Pid()
+
+	// The script is read by path, so must be running before it is replaced
+	waitForMarker := func(expected string) {
+		deadline := time.Now().Add(5 * time.Second)
+		for {
+			b, _ := os.ReadFile(marker)
+			if string(b) == expected {
+				return
+			}
+			if time.Now().After(deadline) {
+				t.Fatalf("expected marker %q, got %q\n", expected, b)
+			}
+			time.Sleep(10 * time.Millisecond)
+		}
+	}
+	waitForMarker("v1\n")
+
+	deploy("v2")
+
+	expected := []EventType{EventBinaryChanged, EventExited, EventStarted}
+	for _, typ := range expected {
+		select {
+		case e := <-events:
+			if e.Type != typ {
+				t.Fatalf("expected %v event, got %+v\n", typ, e)
+			}
+		case <-time.After(5 * time.Second):
+			t.Fatalf("timed out waiting for %v event\n", typ)
+		}
+	}
+
+	if s.Pid() == first || s.Restarts() != 1 {
+		t.Fatalf("expected a new process, got pid %v and %v restarts\n", s.Pid(), s.Restarts())
+	}
+
+	waitForMarker("v1\nv2\n")
+}
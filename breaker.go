@@ -0,0 +1,114 @@
+package launcher
+
+import (
+	"errors"
+	"sync"
+	"time"
+)
+
+// ErrCircuitOpen is returned when starting a Launcher whose
+// Breaker has been tripped by repeated failures
+var ErrCircuitOpen = errors.New("circuit breaker is open after repeated failures")
+
+var errMissingBreaker = errors.New("breaker must be provided")
+
+// Breaker stops the Launchers sharing it, typically those created from
+// the same Spec, from starting processes after repeated failures, so
+// that a broken command is not launched in a hot loop.  Once tripped,
+// starts fail fast with ErrCircuitOpen until the cool-down has passed,
+// after which a single further failure trips the Breaker again
+type Breaker struct {
+	threshold int
+	window    time.Duration
+	cooldown  time.Duration
+
+	mu        sync.Mutex
+	failures  []time.Time
+	openUntil time.Time
+	tripped   bool
+}
+
+// NewBreaker creates a Breaker which trips after the threshold number
+// of consecutive failures within the window, where a window of 0 is
+// unlimited, and remains open for the cool-down
+func NewBreaker(threshold int, window, cooldown time.Duration) (*Breaker, error) {
+	if threshold <= 0 {
+		return nil, errInvalidLimit
+	}
+	if window < 0 || cooldown <= 0 {
+		return nil, errInvalidTimeout
+	}
+	return &Breaker{
+		threshold: threshold,
+		window:    window,
+		cooldown:  cooldown,
+	}, nil
+}
+
+// WithBreaker requires the Breaker to be closed when the Launcher is
+// started, and records the outcome of its process with the Breaker.
+// Processes ended by the Launcher being cancelled are not recorded
+func WithBreaker(b *Breaker) Option {
+	return func(o *options) error {
+		if b == nil {
+			return errMissingBreaker
+		}
+		o.breaker = b
+		return nil
+	}
+}
+
+// Open returns true if starts are currently being refused
+func (b *Breaker) Open() bool {
+	return b.allow() != nil
+}
+
+// Reset closes the Breaker, forgetting all recorded failures
+func (b *Breaker) Reset() {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	b.failures = nil
+	b.openUntil = time.Time{}
+	b.tripped = false
+}
+
+// allow returns ErrCircuitOpen if the cool-down has not passed
+func (b *Breaker) allow() error {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	if time.Now().Before(b.openUntil) {
+		return ErrCircuitOpen
+	}
+	return nil
+}
+
+// record notes the outcome of a process, tripping the Breaker
+// if the failure threshold has been reached
+func (b *Breaker) record(err error) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	now := time.Now()
+	if err == nil {
+		b.failures = nil
+		b.tripped = false
+		return
+	}
+
+	if b.window > 0 {
+		i := 0
+		for i < len(b.failures) && now.Sub(b.failures[i]) > b.window {
+			i++
+		}
+		b.failures = b.failures[i:]
+	}
+	b.failures = append(b.failures, now)
+
+	if b.tripped || len(b.failures) >= b.threshold {
+		b.failures = nil
+		b.tripped = true
+		b.openUntil = now.Add(b.cooldown)
+	}
+}
@@ -0,0 +1,94 @@
+package launcher
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+)
+
+func runWithBreaker(b *Breaker, args ...string) error {
+	l, err := NewWithOptions(context.Background(), "sh", nil, args, WithBreaker(b))
+	if err != nil {
+		return err
+	}
+	defer l.Close()
+	return l.Run()
+}
+
+func TestBreakerTrips(t *testing.T) {
+
+	b, err := NewBreaker(3, time.Minute, 200*time.Millisecond)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	for i := 0; i < 3; i++ {
+		if err := runWithBreaker(b, "-c", "exit 1"); err == nil || err == ErrCircuitOpen {
+			t.Fatalf("expected process failure, got %v\n", err)
+		}
+	}
+	if !b.Open() {
+		t.Fatal("expected breaker to be open")
+	}
+	if err := runWithBreaker(b, "-c", "exit 0"); !errors.Is(err, ErrCircuitOpen) {
+		t.Fatalf("expected ErrCircuitOpen, got %v\n", err)
+	}
+
+	time.Sleep(300 * time.Millisecond)
+
+	// A single failure after the cool-down trips the breaker again
+	if err := runWithBreaker(b, "-c", "exit 1"); err == nil || err == ErrCircuitOpen {
+		t.Fatalf("expected process failure, got %v\n", err)
+	}
+	if err := runWithBreaker(b, "-c", "exit 0"); !errors.Is(err, ErrCircuitOpen) {
+		t.Fatalf("expected ErrCircuitOpen, got %v\n", err)
+	}
+
+	b.Reset()
+	if err := runWithBreaker(b, "-c", "exit 0"); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestBreakerSuccessResets(t *testing.T) {
+
+	b, err := NewBreaker(2, 0, time.Minute)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	for i := 0; i < 3; i++ {
+		runWithBreaker(b, "-c", "exit 1")
+		if err := runWithBreaker(b, "-c", "exit 0"); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if b.Open() {
+		t.Fatal("expected breaker to be closed")
+	}
+}
+
+func TestBreakerIgnoresCancel(t *testing.T) {
+
+	b, err := NewBreaker(1, 0, time.Minute)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	l, err := NewWithOptions(context.Background(), "sleep", nil, []string{"10"}, WithBreaker(b))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer l.Close()
+
+	if err := l.Start(); err != nil {
+		t.Fatal(err)
+	}
+	l.Cancel()
+	l.Wait()
+
+	if b.Open() {
+		t.Fatal("expected cancelled process not to trip the breaker")
+	}
+}
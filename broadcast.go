@@ -0,0 +1,160 @@
+package launcher
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"sync"
+)
+
+// ErrSlowConsumer is reported for a launcher whose queue of pending
+// stdin payloads is full, in which case the payload is dropped for it
+var ErrSlowConsumer = errors.New("launcher is not keeping up with stdin")
+
+var errBroadcasterClosed = errors.New("broadcaster is closed")
+
+// defaultBroadcastQueue is the number of payloads queued for each
+// launcher if no queue length is specified
+const defaultBroadcastQueue = 64
+
+// BroadcastError reports the launchers for which a broadcast failed
+type BroadcastError struct {
+	Failures map[*Launcher]error
+}
+
+func (e *BroadcastError) Error() string {
+	var s []string
+	for l, err := range e.Failures {
+		s = append(s, fmt.Sprintf("%s (pid %d): %v", l.GetFile(), l.Pid(), err))
+	}
+	return "broadcast failed for " + strings.Join(s, "; ")
+}
+
+// Unwrap returns the individual failures
+func (e *BroadcastError) Unwrap() []error {
+	var errs []error
+	for _, err := range e.Failures {
+		errs = append(errs, err)
+	}
+	return errs
+}
+
+// broadcastTarget queues payloads for a single launcher
+type broadcastTarget struct {
+	l     *Launcher
+	queue chan []byte
+	mu    sync.Mutex
+	err   error
+}
+
+// Broadcaster duplicates each stdin payload to a set of launchers.
+// Each launcher is written to from its own queue, so that a slow
+// launcher does not hold up the others
+type Broadcaster struct {
+	mu      sync.Mutex
+	targets []*broadcastTarget
+	closed  bool
+	wg      sync.WaitGroup
+}
+
+// NewBroadcaster creates a new Broadcaster for the launchers, allowing
+// up to queue payloads to be pending for each launcher
+func NewBroadcaster(queue int, launchers ...*Launcher) *Broadcaster {
+	if queue <= 0 {
+		queue = defaultBroadcastQueue
+	}
+
+	b := &Broadcaster{}
+	for _, l := range launchers {
+		t := &broadcastTarget{
+			l:     l,
+			queue: make(chan []byte, queue),
+		}
+		b.targets = append(b.targets, t)
+
+		b.wg.Add(1)
+		go b.write(t)
+	}
+	return b
+}
+
+// SendStdIn queues the payload for every launcher, returning a
+// *BroadcastError identifying any launchers which failed to receive
+// it, or for which an earlier payload could not be written
+func (b *Broadcaster) SendStdIn(p []byte) error {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	if b.closed {
+		return errBroadcasterClosed
+	}
+
+	failures := map[*Launcher]error{}
+	for _, t := range b.targets {
+		t.mu.Lock()
+		err := t.err
+		t.mu.Unlock()
+
+		if err != nil {
+			failures[t.l] = err
+			continue
+		}
+
+		select {
+		case t.queue <- append([]byte{}, p...):
+		default:
+			failures[t.l] = ErrSlowConsumer
+		}
+	}
+
+	if len(failures) > 0 {
+		return &BroadcastError{Failures: failures}
+	}
+	return nil
+}
+
+// Write implements io.Writer, broadcasting p
+func (b *Broadcaster) Write(p []byte) (int, error) {
+	if err := b.SendStdIn(p); err != nil {
+		return 0, err
+	}
+	return len(p), nil
+}
+
+// Close stops accepting payloads, and waits for those
+// already queued to be written
+func (b *Broadcaster) Close() error {
+	b.mu.Lock()
+	if !b.closed {
+		b.closed = true
+		for _, t := range b.targets {
+			close(t.queue)
+		}
+	}
+	b.mu.Unlock()
+
+	b.wg.Wait()
+	return nil
+}
+
+// write sends queued payloads to the launcher, stopping
+// at the first failure
+func (b *Broadcaster) write(t *broadcastTarget) {
+	defer b.wg.Done()
+
+	for p := range t.queue {
+		t.mu.Lock()
+		failed := t.err != nil
+		t.mu.Unlock()
+
+		if failed {
+			continue
+		}
+
+		if err := t.l.SendStdIn(p); err != nil {
+			t.mu.Lock()
+			t.err = err
+			t.mu.Unlock()
+		}
+	}
+}
@@ -0,0 +1,88 @@
+package launcher
+
+import (
+	"context"
+	"errors"
+	"io"
+	"testing"
+)
+
+func TestBroadcaster(t *testing.T) {
+
+	var launchers []*Launcher
+	for i := 0; i < 3; i++ {
+		l, err := New(context.Background(), "head", []string{}, "-n", "2")
+		if err != nil {
+			t.Fatal(err)
+		}
+		defer l.Close()
+
+		if err := l.Start(); err != nil {
+			t.Fatal(err)
+		}
+		launchers = append(launchers, l)
+	}
+
+	b := NewBroadcaster(0, launchers...)
+	for _, s := range []string{"foo\n", "bar\n"} {
+		if err := b.SendStdIn([]byte(s)); err != nil {
+			t.Fatal(err)
+		}
+	}
+	b.Close()
+
+	for _, l := range launchers {
+		out, err := io.ReadAll(l.StdOutReader())
+		if err != nil {
+			t.Fatal(err)
+		}
+		if string(out) != "foo\nbar\n" {
+			t.Fatalf("unexpected output %q\n", out)
+		}
+	}
+
+	if err := b.SendStdIn([]byte("baz\n")); err != errBroadcasterClosed {
+		t.Fatal(err)
+	}
+}
+
+func TestBroadcasterFailure(t *testing.T) {
+
+	good, err := New(context.Background(), "cat", []string{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer good.Close()
+
+	bad, err := New(context.Background(), "true", []string{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer bad.Close()
+
+	for _, l := range []*Launcher{good, bad} {
+		if err := l.Start(); err != nil {
+			t.Fatal(err)
+		}
+	}
+	bad.Wait()
+
+	b := NewBroadcaster(1, good, bad)
+	defer b.Close()
+
+	// Writes to the exited process eventually fail, and once
+	// failed, the failure is reported on every send
+	var be *BroadcastError
+	for i := 0; i < 1000 && be == nil; i++ {
+		if err := b.SendStdIn([]byte("foo\n")); err != nil && !errors.As(err, &be) {
+			t.Fatal(err)
+		}
+	}
+	if be == nil {
+		t.Fatal("expected failure for exited process")
+	}
+
+	if _, ok := be.Failures[bad]; !ok {
+		t.Fatalf("expected failure for exited process, got %v\n", be)
+	}
+}
@@ -0,0 +1,183 @@
+package launcher
+
+import (
+	"bytes"
+	"compress/gzip"
+	"errors"
+	"fmt"
+	"io"
+	"time"
+)
+
+var errInvalidSize = errors.New("size must be positive")
+
+// RunResult is the outcome of a process run by RunCaptured
+type RunResult struct {
+	// Stdout and Stderr are gzipped if Compressed is true, and
+	// are most easily read using StdoutReader and StderrReader
+	Stdout []byte
+	Stderr []byte
+	// ExitCode is -1 if the process was terminated by a signal
+	ExitCode int
+	Duration time.Duration
+	// Truncated is true if either stream exceeded the limit set by
+	// WithMaxCapturedOutput, in which case only its start and end
+	// were retained, separated by a truncation marker
+	Truncated bool
+	// Compressed is true if WithCompressedCapture was set
+	Compressed bool
+}
+
+// StdoutReader returns a reader of the captured stdout,
+// decompressing it if necessary
+func (r *RunResult) StdoutReader() io.Reader {
+	return r.reader(r.Stdout)
+}
+
+// StderrReader returns a reader of the captured stderr,
+// decompressing it if necessary
+func (r *RunResult) StderrReader() io.Reader {
+	return r.reader(r.Stderr)
+}
+
+func (r *RunResult) reader(b []byte) io.Reader {
+	if !r.Compressed {
+		return bytes.NewReader(b)
+	}
+	zr, err := gzip.NewReader(bytes.NewReader(b))
+	if err != nil {
+		return errReader{err}
+	}
+	return zr
+}
+
+// errReader is an io.Reader which fails with err
+type errReader struct {
+	err error
+}
+
+func (e errReader) Read([]byte) (int, error) {
+	return 0, e.err
+}
+
+// WithMaxCapturedOutput limits the output of each stream retained by
+// RunCaptured to n bytes, split between its start and its end, so that
+// a runaway process cannot exhaust the memory of the parent
+func WithMaxCapturedOutput(n int) Option {
+	return func(o *options) error {
+		if n <= 0 {
+			return errInvalidSize
+		}
+		o.maxCaptured = n
+		return nil
+	}
+}
+
+// WithCompressedCapture gzips the output captured by RunCaptured as it
+// arrives, greatly reducing the memory needed to retain verbose output.
+// Any limit set by WithMaxCapturedOutput applies to the output before
+// it is compressed
+func WithCompressedCapture() Option {
+	return func(o *options) error {
+		o.compressCapture = true
+		return nil
+	}
+}
+
+// RunCaptured launches the underlying process, captures its stdout and
+// stderr, and waits until it completes.  A result is returned whenever
+// the process was started, along with any error from Wait
+func (l *Launcher) RunCaptured() (*RunResult, error) {
+	start := time.Now()
+	if err := l.Start(); err != nil {
+		return nil, err
+	}
+
+	newBuffer := func() *captureBuffer {
+		return newCaptureBuffer(l.opts.maxCaptured, l.opts.compressCapture)
+	}
+	stdout, stderr := newBuffer(), newBuffer()
+	l.copyOutput(stdout, stderr)
+	err := l.Wait()
+
+	return &RunResult{
+		Stdout:     stdout.Bytes(),
+		Stderr:     stderr.Bytes(),
+		ExitCode:   l.ExitCode(),
+		Duration:   time.Since(start),
+		Truncated:  stdout.dropped > 0 || stderr.dropped > 0,
+		Compressed: l.opts.compressCapture,
+	}, err
+}
+
+// captureBuffer retains the output written to it, keeping only its
+// start and end once it exceeds the limit, if set.  Compressed output
+// is gzipped as it is written, unless it is limited, when only the
+// retained output is compressed
+type captureBuffer struct {
+	limit    int
+	compress bool
+	head     []byte
+	tail     []byte
+	dropped  int64
+	zbuf     bytes.Buffer
+	zw       *gzip.Writer
+}
+
+func newCaptureBuffer(limit int, compress bool) *captureBuffer {
+	c := &captureBuffer{limit: limit, compress: compress}
+	if compress && limit == 0 {
+		c.zw = gzip.NewWriter(&c.zbuf)
+	}
+	return c
+}
+
+// Write retains b, up to the limit
+func (c *captureBuffer) Write(b []byte) (int, error) {
+	n := len(b)
+	if c.zw != nil {
+		return c.zw.Write(b)
+	}
+	if c.limit == 0 {
+		c.head = append(c.head, b...)
+		return n, nil
+	}
+
+	headSize := c.limit / 2
+	if room := headSize - len(c.head); room > 0 {
+		room = min(room, len(b))
+		c.head = append(c.head, b[:room]...)
+		b = b[room:]
+	}
+
+	tailSize := c.limit - headSize
+	c.tail = append(c.tail, b...)
+	if excess := len(c.tail) - tailSize; excess > 0 {
+		// The discarded prefix is released when append next reallocates
+		c.dropped += int64(excess)
+		c.tail = c.tail[excess:]
+	}
+	return n, nil
+}
+
+// Bytes returns the retained output, marking where any was discarded,
+// and must only be called once writing has finished
+func (c *captureBuffer) Bytes() []byte {
+	if c.zw != nil {
+		c.zw.Close()
+		return c.zbuf.Bytes()
+	}
+
+	out := append([]byte{}, c.head...)
+	if c.dropped > 0 {
+		out = append(out, fmt.Sprintf("\n[... %d bytes truncated ...]\n", c.dropped)...)
+	}
+	out = append(out, c.tail...)
+	if c.compress {
+		zw := gzip.NewWriter(&c.zbuf)
+		zw.Write(out)
+		zw.Close()
+		return c.zbuf.Bytes()
+	}
+	return out
+}
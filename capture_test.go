@@ -0,0 +1,124 @@
+package launcher
+
+import (
+	"bytes"
+	"context"
+	"io"
+	"strings"
+	"testing"
+)
+
+func TestRunCaptured(t *testing.T) {
+
+	l, err := New(context.Background(), "sh", nil, "-c", "echo out; echo err >&2; exit 3")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer l.Close()
+
+	res, err := l.RunCaptured()
+	if err == nil {
+		t.Fatal("expected exit status error")
+	}
+	if res == nil {
+		t.Fatal("expected a result")
+	}
+
+	if string(res.Stdout) != "out\n" || string(res.Stderr) != "err\n" || res.ExitCode != 3 || res.Truncated {
+		t.Fatalf("unexpected result %+v\n", res)
+	}
+}
+
+func TestRunCapturedTruncated(t *testing.T) {
+
+	// 10000 lines of 6 bytes, starting 00000 and ending 09999
+	script := "i=0; while [ $i -lt 10000 ]; do printf '%05d\\n' $i; i=$((i+1)); done"
+
+	l, err := NewWithOptions(context.Background(), "sh", nil, []string{"-c", script}, WithMaxCapturedOutput(60))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer l.Close()
+
+	res, err := l.RunCaptured()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if !res.Truncated {
+		t.Fatal("expected output to be truncated")
+	}
+	expected := "00000\n00001\n00002\n00003\n00004\n\n[... 59940 bytes truncated ...]\n09995\n09996\n09997\n09998\n09999\n"
+	if string(res.Stdout) != expected {
+		t.Fatalf("invalid response - expected %q, got %q\n", expected, res.Stdout)
+	}
+}
+
+func TestCaptureBuffer(t *testing.T) {
+
+	input := strings.Repeat("abcdefghij", 100)
+
+	for _, limit := range []int{0, 1, 7, 10, 1000, 2000} {
+		for _, chunk := range []int{1, 3, 64, 1000} {
+			c := newCaptureBuffer(limit, false)
+			for i := 0; i < len(input); i += chunk {
+				c.Write([]byte(input[i:min(i+chunk, len(input))]))
+			}
+
+			if limit == 0 || limit >= len(input) {
+				if !bytes.Equal(c.Bytes(), []byte(input)) || c.dropped != 0 {
+					t.Fatalf("limit %v chunk %v: expected all output retained\n", limit, chunk)
+				}
+				continue
+			}
+
+			head, tail := limit/2, limit-limit/2
+			if c.dropped != int64(len(input)-limit) {
+				t.Fatalf("limit %v chunk %v: expected %v bytes dropped, got %v\n", limit, chunk, len(input)-limit, c.dropped)
+			}
+			out := string(c.Bytes())
+			if !strings.HasPrefix(out, input[:head]) || !strings.HasSuffix(out, input[len(input)-tail:]) {
+				t.Fatalf("limit %v chunk %v: unexpected output %q\n", limit, chunk, out)
+			}
+		}
+	}
+}
+
+func TestRunCapturedCompressed(t *testing.T) {
+
+	// Highly repetitive output compresses well
+	script := "head -c 1048576 /dev/zero | tr '\\0' 'a'; echo err >&2"
+
+	for _, opts := range [][]Option{{WithCompressedCapture()}, {WithCompressedCapture(), WithMaxCapturedOutput(100)}} {
+		l, err := NewWithOptions(context.Background(), "sh", nil, []string{"-c", script}, opts...)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		res, err := l.RunCaptured()
+		l.Close()
+		if err != nil {
+			t.Fatal(err)
+		}
+		if !res.Compressed || len(res.Stdout) > 10000 {
+			t.Fatalf("expected compressed output, got %v bytes\n", len(res.Stdout))
+		}
+
+		stdout, err := io.ReadAll(res.StdoutReader())
+		if err != nil {
+			t.Fatal(err)
+		}
+		if res.Truncated {
+			if !bytes.HasPrefix(stdout, bytes.Repeat([]byte("a"), 50)) || !bytes.Contains(stdout, []byte("truncated")) {
+				t.Fatalf("unexpected truncated stdout %q\n", stdout)
+			}
+		} else if !bytes.Equal(stdout, bytes.Repeat([]byte("a"), 1048576)) {
+			t.Fatalf("unexpected stdout of %v bytes\n", len(stdout))
+		}
+
+		stderr, err := io.ReadAll(res.StderrReader())
+		if err != nil || string(stderr) != "err\n" {
+			t.Fatalf("unexpected stderr %q (%v)\n", stderr, err)
+		}
+	}
+}
@@ -0,0 +1,28 @@
+package launcher
+
+import (
+	"context"
+	"errors"
+)
+
+// ErrCancelled is the cause recorded when a Launcher is
+// cancelled by Cancel, or its process is terminated by Close
+var ErrCancelled = errors.New("launcher was cancelled")
+
+// Cause returns the reason the process was, or is being, terminated,
+// or nil if it has not been.  This is the cause given to CancelWithCause,
+// ErrCancelled if it was cancelled or closed, ErrStartupTimeout or
+// ErrLivenessFailed if it failed its probes, ErrHeartbeatMissed if it
+// missed a heartbeat, ErrIdleTimeout if it was idle for too long, or
+// the cause of the end of the context of the Launcher, such as
+// context.DeadlineExceeded
+func (l *Launcher) Cause() error {
+	l.mu.Lock()
+	cause := l.termCause
+	l.mu.Unlock()
+
+	if cause == nil && l.ctx.Err() != nil {
+		cause = context.Cause(l.ctx)
+	}
+	return cause
+}
@@ -0,0 +1,82 @@
+package launcher
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+)
+
+func TestCauseTimeout(t *testing.T) {
+
+	ctx, cancel := context.WithTimeout(context.Background(), 100*time.Millisecond)
+	defer cancel()
+
+	l, err := New(ctx, "sleep", nil, "10")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer l.Close()
+
+	err = l.Run()
+	if !errors.Is(err, context.DeadlineExceeded) {
+		t.Fatalf("expected a timeout, got %v\n", err)
+	}
+	var exitErr *ExitError
+	if !errors.As(err, &exitErr) || exitErr.Cause != context.DeadlineExceeded {
+		t.Fatalf("expected an ExitError with the cause, got %v\n", err)
+	}
+	if l.Cause() != context.DeadlineExceeded {
+		t.Fatalf("unexpected cause %v\n", l.Cause())
+	}
+}
+
+func TestCauseCancelled(t *testing.T) {
+
+	watchdog := errors.New("watchdog")
+
+	tests := []struct {
+		cancel   func(l *Launcher, parent context.CancelCauseFunc)
+		expected error
+	}{
+		{func(l *Launcher, _ context.CancelCauseFunc) { l.Cancel() }, ErrCancelled},
+		{func(l *Launcher, _ context.CancelCauseFunc) { l.CancelWithCause(watchdog) }, watchdog},
+		{func(_ *Launcher, parent context.CancelCauseFunc) { parent(watchdog) }, watchdog},
+	}
+
+	for i, test := range tests {
+		ctx, cancel := context.WithCancelCause(context.Background())
+		l, err := New(ctx, "sleep", nil, "10")
+		if err != nil {
+			t.Fatal(err)
+		}
+		if err := l.Start(); err != nil {
+			t.Fatal(err)
+		}
+		if l.Cause() != nil {
+			t.Fatalf("%v: expected no cause, got %v\n", i, l.Cause())
+		}
+
+		test.cancel(l, cancel)
+		if err := l.Wait(); !errors.Is(err, test.expected) {
+			t.Fatalf("%v: expected %v, got %v\n", i, test.expected, err)
+		}
+		l.Close()
+		cancel(nil)
+	}
+}
+
+func TestCauseNotCancelled(t *testing.T) {
+
+	l, err := New(context.Background(), "sh", nil, "-c", "exit 1")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer l.Close()
+
+	err = l.Run()
+	var exitErr *ExitError
+	if !errors.As(err, &exitErr) || exitErr.Cause != nil {
+		t.Fatalf("expected no cause, got %v\n", err)
+	}
+}
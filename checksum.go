@@ -0,0 +1,51 @@
+package launcher
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+)
+
+var errInvalidDigest = errors.New("digest must be a hex encoded SHA-256")
+
+// WithExpectedSHA256 requires the SHA-256 digest of the resolved
+// executable to match the hex encoded digest, both when the Launcher
+// is created and when its process is started.  A mismatch is returned
+// as a *PolicyError
+func WithExpectedSHA256(digest string) Option {
+	return func(o *options) error {
+		want, err := hex.DecodeString(strings.TrimSpace(digest))
+		if err != nil || len(want) != sha256.Size {
+			return errInvalidDigest
+		}
+		return WithPolicy(PolicyFunc(func(req PolicyRequest) error {
+			got, err := fileSHA256(req.Path)
+			if err != nil {
+				return err
+			}
+			if got != hex.EncodeToString(want) {
+				return fmt.Errorf("SHA-256 of %s is %s, expected %x", req.Path, got, want)
+			}
+			return nil
+		}))(o)
+	}
+}
+
+// fileSHA256 returns the hex encoded SHA-256 digest of the file
+func fileSHA256(path string) (string, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return "", err
+	}
+	defer f.Close()
+
+	h := sha256.New()
+	if _, err := io.Copy(h, f); err != nil {
+		return "", err
+	}
+	return hex.EncodeToString(h.Sum(nil)), nil
+}
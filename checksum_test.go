@@ -0,0 +1,51 @@
+package launcher
+
+import (
+	"context"
+	"errors"
+	"os/exec"
+	"strings"
+	"testing"
+)
+
+func TestExpectedSHA256(t *testing.T) {
+
+	path, err := exec.LookPath("echo")
+	if err != nil {
+		t.Fatal(err)
+	}
+	digest, err := fileSHA256(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	l, err := NewWithOptions(context.Background(), "echo", nil, nil, WithExpectedSHA256(strings.ToUpper(digest)))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer l.Close()
+
+	if err := l.Run(); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestExpectedSHA256Mismatch(t *testing.T) {
+
+	digest := strings.Repeat("00", 32)
+
+	_, err := NewWithOptions(context.Background(), "echo", nil, nil, WithExpectedSHA256(digest))
+	if !errors.Is(err, ErrPolicyDenied) {
+		t.Fatal(err)
+	}
+}
+
+func TestExpectedSHA256Invalid(t *testing.T) {
+
+	for _, digest := range []string{"", "xyz", "0000"} {
+		_, err := NewWithOptions(context.Background(), "echo", nil, nil, WithExpectedSHA256(digest))
+		if err != errInvalidDigest {
+			t.Fatalf("expected errInvalidDigest for %q, got %v\n", digest, err)
+		}
+	}
+}
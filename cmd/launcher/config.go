@@ -0,0 +1,69 @@
+package main
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+
+	"github.com/gford1000-go/launcher"
+)
+
+var errNoProcesses = errors.New("spec file defines no processes")
+
+// config is the content of a spec file
+type config struct {
+	Processes []processConfig `json:"processes"`
+}
+
+// processConfig defines a single supervised process by name, with
+// the settings of a launcher.Config
+type processConfig struct {
+	Name string `json:"name"`
+	launcher.Config
+}
+
+// UnmarshalJSON decodes the process, which inherits the environment
+// of this process unless inherit_env is false
+func (p *processConfig) UnmarshalJSON(b []byte) error {
+	type plain processConfig
+	v := plain{Config: launcher.Config{InheritEnv: true}}
+	if err := json.Unmarshal(b, &v); err != nil {
+		return err
+	}
+	*p = processConfig(v)
+	return nil
+}
+
+// loadConfig reads and validates the spec file
+func loadConfig(path string) (*config, error) {
+	b, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+
+	var c config
+	if err := json.Unmarshal(b, &c); err != nil {
+		return nil, fmt.Errorf("invalid spec file %s: %w", path, err)
+	}
+	if len(c.Processes) == 0 {
+		return nil, errNoProcesses
+	}
+
+	names := map[string]bool{}
+	for i, p := range c.Processes {
+		if p.Name == "" {
+			return nil, fmt.Errorf("process %d has no name", i)
+		}
+		if names[p.Name] {
+			return nil, fmt.Errorf("process %q is defined more than once", p.Name)
+		}
+		names[p.Name] = true
+
+		if _, err := p.Spec(); err != nil {
+			return nil, fmt.Errorf("process %q: %w", p.Name, err)
+		}
+	}
+
+	return &c, nil
+}
@@ -0,0 +1,190 @@
+// Command launcher runs the processes defined in a JSON spec file,
+// supervising each according to its restart policy and writing their
+// output to stdout and stderr, with every line prefixed by the name
+// of the process that produced it.
+//
+// Usage:
+//
+//	launcher [-grace duration] specfile
+//
+// The spec file has the form:
+//
+//	{
+//	  "processes": [
+//	    {
+//	      "name": "web",
+//	      "file": "python3",
+//	      "args": ["-m", "http.server", "8080"],
+//	      "env": ["PYTHONUNBUFFERED=1"],
+//	      "restart": "on-failure",
+//	      "restart_delay": "1s",
+//	      "max_restarts": 5
+//	    }
+//	  ]
+//	}
+//
+// Each process is a name together with the fields of a launcher.Config.
+// restart may be "never" (the default), "on-failure" or "always".  Env
+// entries are added to the environment of this command, unless
+// inherit_env is false.
+//
+// SIGHUP is forwarded to every process.  SIGINT and SIGTERM stop all
+// processes, which are sent SIGTERM and killed if still running after
+// the grace period.
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"os"
+	"os/signal"
+	"strings"
+	"sync"
+	"syscall"
+	"time"
+
+	"github.com/gford1000-go/launcher"
+)
+
+func main() {
+	grace := flag.Duration("grace", 10*time.Second, "time allowed for processes to exit when stopping")
+	flag.Usage = func() {
+		fmt.Fprintf(flag.CommandLine.Output(), "usage: %s [-grace duration] specfile\n", os.Args[0])
+		flag.PrintDefaults()
+	}
+	flag.Parse()
+
+	if flag.NArg() != 1 {
+		flag.Usage()
+		os.Exit(2)
+	}
+
+	os.Exit(run(flag.Arg(0), *grace))
+}
+
+// process is a running entry from the spec file
+type process struct {
+	name string
+	s    *launcher.Supervisor
+	err  error
+}
+
+// run starts the processes in the spec file and waits for them to
+// finish, returning the exit code for the command
+func run(path string, grace time.Duration) int {
+	cfg, err := loadConfig(path)
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		return 2
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	width := 0
+	for _, p := range cfg.Processes {
+		width = max(width, len(p.Name))
+	}
+
+	procs := []*process{}
+
+	stopAll := func() {
+		var wg sync.WaitGroup
+		for _, p := range procs {
+			wg.Add(1)
+			go func(p *process) {
+				defer wg.Done()
+				p.s.Stop(grace)
+			}(p)
+		}
+		wg.Wait()
+	}
+
+	for _, pc := range cfg.Processes {
+		prefix := pc.Name + strings.Repeat(" ", width-len(pc.Name)) + " | "
+
+		p := &process{name: pc.Name}
+
+		s, err := supervisor(ctx, pc, prefix)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "%s: %v\n", pc.Name, err)
+			stopAll()
+			return 1
+		}
+		s.Stdout = os.Stdout
+		s.Stderr = os.Stderr
+
+		if err := s.Start(); err != nil {
+			fmt.Fprintf(os.Stderr, "%s: %v\n", pc.Name, err)
+			stopAll()
+			return 1
+		}
+		p.s = s
+		procs = append(procs, p)
+	}
+
+	signals := make(chan os.Signal, 1)
+	signal.Notify(signals, syscall.SIGINT, syscall.SIGTERM, syscall.SIGHUP)
+	defer signal.Stop(signals)
+
+	finished := make(chan struct{})
+	go func() {
+		defer close(finished)
+		var wg sync.WaitGroup
+		for _, p := range procs {
+			wg.Add(1)
+			go func(p *process) {
+				defer wg.Done()
+				p.err = p.s.Wait()
+			}(p)
+		}
+		wg.Wait()
+	}()
+
+loop:
+	for {
+		select {
+		case sig := <-signals:
+			if sig == syscall.SIGHUP {
+				for _, p := range procs {
+					p.s.Signal(sig)
+				}
+				continue
+			}
+			stopAll()
+			<-finished
+			break loop
+		case <-finished:
+			break loop
+		}
+	}
+
+	code := 0
+	for _, p := range procs {
+		if p.err != nil {
+			fmt.Fprintf(os.Stderr, "%s: %v\n", p.name, p.err)
+			code = 1
+		}
+	}
+	return code
+}
+
+// supervisor creates the Supervisor for the process, which
+// writes the prefix at the start of each line of its output
+func supervisor(ctx context.Context, pc processConfig, prefix string) (*launcher.Supervisor, error) {
+	spec, err := pc.Spec()
+	if err != nil {
+		return nil, err
+	}
+	spec.Options = append(spec.Options, launcher.WithOutputPrefix(prefix))
+
+	s, err := launcher.NewSupervisor(ctx, spec)
+	if err != nil {
+		return nil, err
+	}
+	s.Restart = pc.Restart
+	s.RestartDelay = time.Duration(pc.RestartDelay)
+	s.MaxRestarts = pc.MaxRestarts
+	return s, nil
+}
@@ -0,0 +1,77 @@
+package main
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/gford1000-go/launcher"
+)
+
+func writeConfig(t *testing.T, s string) string {
+	path := filepath.Join(t.TempDir(), "spec.json")
+	if err := os.WriteFile(path, []byte(s), 0600); err != nil {
+		t.Fatal(err)
+	}
+	return path
+}
+
+func TestLoadConfig(t *testing.T) {
+
+	path := writeConfig(t, `{"processes": [{"name": "a", "file": "echo", "args": ["foo"], "restart": "on-failure", "restart_delay": "1s"}]}`)
+
+	c, err := loadConfig(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if len(c.Processes) != 1 || c.Processes[0].Name != "a" {
+		t.Fatalf("unexpected config: %+v\n", c)
+	}
+
+	if p := c.Processes[0].Restart; p != launcher.RestartOnFailure {
+		t.Fatalf("unexpected restart policy: %v\n", p)
+	}
+	if d := c.Processes[0].RestartDelay; d != launcher.Duration(time.Second) {
+		t.Fatalf("unexpected restart delay: %v\n", d)
+	}
+}
+
+func TestLoadConfigInheritEnv(t *testing.T) {
+
+	path := writeConfig(t, `{"processes": [{"name": "a", "file": "echo"}, {"name": "b", "file": "echo", "inherit_env": false}]}`)
+
+	c, err := loadConfig(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !c.Processes[0].InheritEnv || c.Processes[1].InheritEnv {
+		t.Fatalf("expected the environment to be inherited unless disabled: %+v\n", c.Processes)
+	}
+}
+
+func TestLoadConfigInvalid(t *testing.T) {
+
+	for _, s := range []string{
+		`{"processes": []}`,
+		`{"processes": [{"file": "echo"}]}`,
+		`{"processes": [{"name": "a"}]}`,
+		`{"processes": [{"name": "a", "file": "echo"}, {"name": "a", "file": "echo"}]}`,
+		`{"processes": [{"name": "a", "file": "echo", "restart": "sometimes"}]}`,
+		`{"processes": [{"name": "a", "file": "echo", "restart_delay": "soon"}]}`,
+	} {
+		if _, err := loadConfig(writeConfig(t, s)); err == nil {
+			t.Fatalf("expected error for %s\n", s)
+		}
+	}
+}
+
+func TestRun(t *testing.T) {
+
+	path := writeConfig(t, `{"processes": [{"name": "a", "file": "echo", "args": ["foo"]}, {"name": "b", "file": "sh", "args": ["-c", "exit 1"]}]}`)
+
+	if code := run(path, 0); code != 1 {
+		t.Fatalf("expected exit code 1, got %v\n", code)
+	}
+}
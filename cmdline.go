@@ -0,0 +1,84 @@
+package launcher
+
+import (
+	"errors"
+	"strings"
+)
+
+var errEmptyCommandLine = errors.New("command line is empty")
+
+var errUnterminatedQuote = errors.New("command line has an unterminated quote")
+
+var errTrailingEscape = errors.New("command line ends with an escape")
+
+// ParseCommandLine splits the command line into words as a POSIX shell
+// does, honouring single and double quotes and backslash escapes, and
+// returns the first word as the file and the rest as its arguments.
+// No expansion of variables, globs or other shell syntax is performed
+func ParseCommandLine(s string) (file string, args []string, err error) {
+	var words []string
+	var word strings.Builder
+	inWord := false
+
+	for i := 0; i < len(s); i++ {
+		c := s[i]
+		switch {
+		case c == ' ' || c == '\t' || c == '\n':
+			if inWord {
+				words = append(words, word.String())
+				word.Reset()
+				inWord = false
+			}
+
+		case c == '\\':
+			i++
+			if i == len(s) {
+				return "", nil, errTrailingEscape
+			}
+			// An escaped newline continues the line
+			if s[i] != '\n' {
+				word.WriteByte(s[i])
+				inWord = true
+			}
+
+		case c == '\'':
+			end := strings.IndexByte(s[i+1:], '\'')
+			if end < 0 {
+				return "", nil, errUnterminatedQuote
+			}
+			word.WriteString(s[i+1 : i+1+end])
+			i += end + 1
+			inWord = true
+
+		case c == '"':
+			i++
+			for ; i < len(s) && s[i] != '"'; i++ {
+				// Within double quotes, a backslash only escapes
+				// characters which are otherwise special
+				if s[i] == '\\' && i+1 < len(s) && strings.IndexByte("$`\"\\\n", s[i+1]) >= 0 {
+					i++
+					if s[i] == '\n' {
+						continue
+					}
+				}
+				word.WriteByte(s[i])
+			}
+			if i == len(s) {
+				return "", nil, errUnterminatedQuote
+			}
+			inWord = true
+
+		default:
+			word.WriteByte(c)
+			inWord = true
+		}
+	}
+	if inWord {
+		words = append(words, word.String())
+	}
+
+	if len(words) == 0 {
+		return "", nil, errEmptyCommandLine
+	}
+	return words[0], words[1:], nil
+}
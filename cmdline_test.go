@@ -0,0 +1,79 @@
+package launcher
+
+import (
+	"context"
+	"io"
+	"reflect"
+	"testing"
+)
+
+func TestParseCommandLine(t *testing.T) {
+
+	tests := []struct {
+		in   string
+		file string
+		args []string
+	}{
+		{"echo", "echo", []string{}},
+		{"  echo  foo\tbar \n", "echo", []string{"foo", "bar"}},
+		{`echo 'foo bar' "baz qux"`, "echo", []string{"foo bar", "baz qux"}},
+		{`echo 'it\'s`, "echo", []string{`it\s`}},
+		{`echo "it's" "\"q\"" "a\b" "\$HOME"`, "echo", []string{"it's", `"q"`, `a\b`, "$HOME"}},
+		{`echo foo\ bar \'x\'`, "echo", []string{"foo bar", "'x'"}},
+		{"echo a\\\nb", "echo", []string{"ab"}},
+		{`echo '' "" x""y`, "echo", []string{"", "", "xy"}},
+		{`"/path with/space" $HOME *`, "/path with/space", []string{"$HOME", "*"}},
+	}
+	for _, test := range tests {
+		file, args, err := ParseCommandLine(test.in)
+		if err != nil {
+			t.Fatalf("parsing %q: %v\n", test.in, err)
+		}
+		if file != test.file || !reflect.DeepEqual(args, test.args) {
+			t.Fatalf("parsing %q: expected %q %q, got %q %q\n", test.in, test.file, test.args, file, args)
+		}
+	}
+}
+
+func TestParseCommandLineErrors(t *testing.T) {
+
+	tests := map[string]error{
+		"":            errEmptyCommandLine,
+		"   ":         errEmptyCommandLine,
+		"echo 'foo":   errUnterminatedQuote,
+		`echo "foo`:   errUnterminatedQuote,
+		`echo "foo\"`: errUnterminatedQuote,
+		`echo foo\`:   errTrailingEscape,
+	}
+	for in, expected := range tests {
+		if _, _, err := ParseCommandLine(in); err != expected {
+			t.Fatalf("parsing %q: expected %v, got %v\n", in, expected, err)
+		}
+	}
+}
+
+func TestParseCommandLineLaunch(t *testing.T) {
+
+	file, args, err := ParseCommandLine(`echo "foo   bar" 'baz'`)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	l, err := New(context.Background(), file, nil, args...)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer l.Close()
+
+	if err := l.Run(); err != nil {
+		t.Fatal(err)
+	}
+	b, err := io.ReadAll(l.StdOutReader())
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if string(b) != "foo   bar baz\n" {
+		t.Fatalf("unexpected output %q\n", b)
+	}
+}
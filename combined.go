@@ -0,0 +1,66 @@
+package launcher
+
+import "time"
+
+// combinedBuffer is the number of chunks held by a combined stream
+// which have yet to be received
+const combinedBuffer = 64
+
+// OutputSource identifies the pipe from which output was read
+type OutputSource int
+
+const (
+	// SourceStdout identifies output written by the process to stdout
+	SourceStdout OutputSource = iota
+	// SourceStderr identifies output written by the process to stderr
+	SourceStderr
+)
+
+var sourceNames = map[OutputSource]string{
+	SourceStdout: "stdout",
+	SourceStderr: "stderr",
+}
+
+func (s OutputSource) String() string {
+	if name, ok := sourceNames[s]; ok {
+		return name
+	}
+	return "unknown"
+}
+
+// OutputChunk is output read from the process, in the
+// order in which it arrived across both pipes
+type OutputChunk struct {
+	Source OutputSource
+	Time   time.Time
+	Data   []byte
+}
+
+// WithCombinedStream makes the output of the process available from
+// CombinedStream, as well as from StdOutReader and StdErrReader
+func WithCombinedStream() Option {
+	return func(o *options) error {
+		o.combined = true
+		return nil
+	}
+}
+
+// CombinedStream returns the stdout and stderr of the process interleaved
+// in the order in which they arrived, or nil if WithCombinedStream is not
+// set.  The channel is closed once both pipes are exhausted, and must be
+// drained, since the output of the process is held up whilst the channel
+// is full.  Chunks still to be sent when the Launcher is cancelled or
+// closed are dropped if the channel is full
+func (l *Launcher) CombinedStream() <-chan OutputChunk {
+	return l.combined
+}
+
+// prepareCombined taps the output of the process into the combined
+// stream, if required
+func (l *Launcher) prepareCombined() {
+	if !l.opts.combined {
+		return
+	}
+	l.combined = make(chan OutputChunk, combinedBuffer)
+	l.tapSink(&channelSink{ch: l.combined, done: l.ctx.Done()})
+}
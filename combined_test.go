@@ -0,0 +1,74 @@
+package launcher
+
+import (
+	"context"
+	"io"
+	"testing"
+)
+
+func TestCombinedStream(t *testing.T) {
+
+	script := "echo one; sleep 0.1; echo two >&2; sleep 0.1; echo three"
+
+	l, err := NewWithOptions(context.Background(), "sh", nil, []string{"-c", script}, WithCombinedStream())
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer l.Close()
+
+	stream := l.CombinedStream()
+	if stream == nil {
+		t.Fatal("expected a combined stream\n")
+	}
+
+	if err := l.Start(); err != nil {
+		t.Fatal(err)
+	}
+
+	var chunks []OutputChunk
+	for c := range stream {
+		chunks = append(chunks, c)
+	}
+
+	expected := []struct {
+		source OutputSource
+		data   string
+	}{
+		{SourceStdout, "one\n"},
+		{SourceStderr, "two\n"},
+		{SourceStdout, "three\n"},
+	}
+	if len(chunks) != len(expected) {
+		t.Fatalf("expected %v chunks, got %v\n", len(expected), len(chunks))
+	}
+	for i, e := range expected {
+		if chunks[i].Source != e.source || string(chunks[i].Data) != e.data {
+			t.Fatalf("expected %v %q, got %v %q\n", e.source, e.data, chunks[i].Source, chunks[i].Data)
+		}
+		if i > 0 && chunks[i].Time.Before(chunks[i-1].Time) {
+			t.Fatalf("chunk %v arrived before chunk %v\n", i, i-1)
+		}
+	}
+
+	// The output remains available from the readers
+	b, err := io.ReadAll(l.StdOutReader())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if string(b) != "one\nthree\n" {
+		t.Fatalf("unexpected stdout %q\n", b)
+	}
+}
+
+func TestCombinedStreamUnset(t *testing.T) {
+
+	l, err := New(context.Background(), "echo", nil, "foo")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer l.Close()
+
+	if l.CombinedStream() != nil {
+		t.Fatal("expected no combined stream\n")
+	}
+}
@@ -0,0 +1,123 @@
+package launcher
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"time"
+)
+
+var errMissingFile = errors.New("file must be provided")
+
+// Duration is a time.Duration encoded in JSON as a
+// string accepted by time.ParseDuration, such as "1m30s"
+type Duration time.Duration
+
+// MarshalText encodes the duration as returned by time.Duration.String
+func (d Duration) MarshalText() ([]byte, error) {
+	return []byte(time.Duration(d).String()), nil
+}
+
+// UnmarshalText decodes a duration, with an empty value decoded as 0
+func (d *Duration) UnmarshalText(b []byte) error {
+	if len(b) == 0 {
+		*d = 0
+		return nil
+	}
+	v, err := time.ParseDuration(string(b))
+	if err != nil {
+		return err
+	}
+	*d = Duration(v)
+	return nil
+}
+
+// Config is a serialisable definition of a process, from which a
+// Launcher or Supervisor can be reconstructed
+type Config struct {
+	File string   `json:"file"`
+	Args []string `json:"args,omitempty"`
+	Env  []string `json:"env,omitempty"`
+	// InheritEnv adds Env to the environment of the current process
+	InheritEnv bool   `json:"inherit_env,omitempty"`
+	Dir        string `json:"dir,omitempty"`
+	// StartupTimeout and CloseGrace correspond to
+	// WithStartupTimeout and WithCloseGrace
+	StartupTimeout Duration `json:"startup_timeout,omitempty"`
+	CloseGrace     Duration `json:"close_grace,omitempty"`
+	// Restart, RestartDelay and MaxRestarts configure a Supervisor
+	Restart      RestartPolicy `json:"restart,omitempty"`
+	RestartDelay Duration      `json:"restart_delay,omitempty"`
+	MaxRestarts  int           `json:"max_restarts,omitempty"`
+}
+
+// ParseConfig decodes and validates a Config from JSON
+func ParseConfig(b []byte) (Config, error) {
+	var c Config
+	if err := json.Unmarshal(b, &c); err != nil {
+		return Config{}, err
+	}
+	if _, err := c.Spec(); err != nil {
+		return Config{}, err
+	}
+	return c, nil
+}
+
+// Spec returns the Spec defined by the Config, with Options
+// for the configured settings
+func (c Config) Spec() (Spec, error) {
+	if c.File == "" {
+		return Spec{}, errMissingFile
+	}
+
+	var opts []Option
+	if c.InheritEnv {
+		opts = append(opts, WithInheritedEnv())
+	}
+	if c.Dir != "" {
+		opts = append(opts, WithDir(c.Dir))
+	}
+	if c.StartupTimeout != 0 {
+		opts = append(opts, WithStartupTimeout(time.Duration(c.StartupTimeout)))
+	}
+	if c.CloseGrace != 0 {
+		opts = append(opts, WithCloseGrace(time.Duration(c.CloseGrace)))
+	}
+
+	spec := Spec{
+		File:    c.File,
+		Env:     append([]string{}, c.Env...),
+		Args:    append([]string{}, c.Args...),
+		Options: opts,
+	}
+	if _, err := spec.options(); err != nil {
+		return Spec{}, err
+	}
+	return spec, nil
+}
+
+// ToLauncher creates a new, unstarted Launcher from the Config
+func (c Config) ToLauncher(ctx context.Context) (*Launcher, error) {
+	spec, err := c.Spec()
+	if err != nil {
+		return nil, err
+	}
+	return spec.New(ctx)
+}
+
+// ToSupervisor creates a new, unstarted Supervisor from the Config,
+// applying its restart settings
+func (c Config) ToSupervisor(ctx context.Context) (*Supervisor, error) {
+	spec, err := c.Spec()
+	if err != nil {
+		return nil, err
+	}
+	s, err := NewSupervisor(ctx, spec)
+	if err != nil {
+		return nil, err
+	}
+	s.Restart = c.Restart
+	s.RestartDelay = time.Duration(c.RestartDelay)
+	s.MaxRestarts = c.MaxRestarts
+	return s, nil
+}
@@ -0,0 +1,112 @@
+package launcher
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"io"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestConfigRoundTrip(t *testing.T) {
+
+	c := Config{
+		File:           "sh",
+		Args:           []string{"-c", "pwd"},
+		Env:            []string{"XYZ=ABC"},
+		Dir:            "/",
+		StartupTimeout: Duration(5 * time.Second),
+		Restart:        RestartOnFailure,
+		RestartDelay:   Duration(1500 * time.Millisecond),
+		MaxRestarts:    3,
+	}
+
+	b, err := json.Marshal(c)
+	if err != nil {
+		t.Fatal(err)
+	}
+	expected := `{"file":"sh","args":["-c","pwd"],"env":["XYZ=ABC"],"dir":"/","startup_timeout":"5s","restart":"on-failure","restart_delay":"1.5s","max_restarts":3}`
+	if string(b) != expected {
+		t.Fatalf("expected %s, got %s\n", expected, b)
+	}
+
+	decoded, err := ParseConfig(b)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(decoded, c) {
+		t.Fatalf("expected %+v, got %+v\n", c, decoded)
+	}
+}
+
+func TestConfigToLauncher(t *testing.T) {
+
+	c, err := ParseConfig([]byte(`{"file": "sh", "args": ["-c", "pwd"], "dir": "/"}`))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	l, err := c.ToLauncher(context.Background())
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer l.Close()
+
+	if err := l.Run(); err != nil {
+		t.Fatal(err)
+	}
+	b, err := io.ReadAll(l.StdOutReader())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if string(b) != "/\n" {
+		t.Fatalf("expected process to run in /, got %q\n", b)
+	}
+}
+
+func TestConfigToSupervisor(t *testing.T) {
+
+	c, err := ParseConfig([]byte(`{"file": "sh", "args": ["-c", "exit 1"], "restart": "always", "max_restarts": 2}`))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	s, err := c.ToSupervisor(context.Background())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if s.Restart != RestartAlways || s.MaxRestarts != 2 {
+		t.Fatalf("unexpected supervisor settings %v %v\n", s.Restart, s.MaxRestarts)
+	}
+
+	if err := s.Start(); err != nil {
+		t.Fatal(err)
+	}
+	s.Wait()
+	if s.Restarts() != 2 {
+		t.Fatalf("expected 2 restarts, got %v\n", s.Restarts())
+	}
+}
+
+func TestConfigInvalid(t *testing.T) {
+
+	tests := map[string]string{
+		`{"args": ["x"]}`:                        "file must be provided",
+		`{"file": "sh", "restart": "sometimes"}`: "invalid restart policy",
+		`{"file": "sh", "close_grace": "soon"}`:  "invalid duration",
+		`{"file": "sh", "close_grace": "-1s"}`:   "timeout must be positive",
+	}
+	for in, expected := range tests {
+		_, err := ParseConfig([]byte(in))
+		if err == nil || !strings.Contains(err.Error(), expected) {
+			t.Fatalf("parsing %s: expected %q, got %v\n", in, expected, err)
+		}
+	}
+
+	if _, err := (Config{}).ToLauncher(context.Background()); !errors.Is(err, errMissingFile) {
+		t.Fatal(err)
+	}
+}
@@ -0,0 +1,120 @@
+package launcher
+
+import (
+	"io"
+	"net"
+	"os"
+	"sync"
+	"time"
+)
+
+// stdioNetwork is the network of the addresses of a stdio connection
+const stdioNetwork = "stdio"
+
+// stdioAddr is the net.Addr of an end of a stdio connection
+type stdioAddr string
+
+func (a stdioAddr) Network() string { return stdioNetwork }
+func (a stdioAddr) String() string  { return string(a) }
+
+// Conn returns a net.Conn reading from the stdout of the process and
+// writing to its stdin, so that protocols such as gRPC may be carried
+// over stdio rather than a network port.  Closing the connection closes
+// stdin, leaving the Launcher to be closed by the caller.  Deadlines
+// are not supported
+func (l *Launcher) Conn() net.Conn {
+	return &stdioConn{
+		r:      l.StdOutReader(),
+		w:      l.StdinWriter(),
+		local:  stdioAddr("parent"),
+		remote: stdioAddr(l.file),
+	}
+}
+
+// StdioConn returns a net.Conn reading from the stdin of the current
+// process and writing to its stdout, being the end of the connection
+// returned by Conn held by a child process
+func StdioConn() net.Conn {
+	return &stdioConn{
+		r:      os.Stdin,
+		w:      os.Stdout,
+		local:  stdioAddr("child"),
+		remote: stdioAddr("parent"),
+	}
+}
+
+// stdioConn is a net.Conn over a pair of streams
+type stdioConn struct {
+	r      io.Reader
+	w      io.WriteCloser
+	local  net.Addr
+	remote net.Addr
+}
+
+func (c *stdioConn) Read(p []byte) (int, error)  { return c.r.Read(p) }
+func (c *stdioConn) Write(p []byte) (int, error) { return c.w.Write(p) }
+func (c *stdioConn) Close() error                { return c.w.Close() }
+func (c *stdioConn) LocalAddr() net.Addr         { return c.local }
+func (c *stdioConn) RemoteAddr() net.Addr        { return c.remote }
+
+func (c *stdioConn) SetDeadline(t time.Time) error      { return os.ErrNoDeadline }
+func (c *stdioConn) SetReadDeadline(t time.Time) error  { return os.ErrNoDeadline }
+func (c *stdioConn) SetWriteDeadline(t time.Time) error { return os.ErrNoDeadline }
+
+// ConnListener returns a net.Listener whose Accept returns the
+// connection once, and then blocks until the listener is closed, so
+// that a server such as a grpc.Server may serve a StdioConn.  The
+// listener is closed once the connection is closed or a read from it
+// fails, as at the end of its input, so that the server returns when
+// the other end of the connection goes away
+func ConnListener(c net.Conn) net.Listener {
+	ln := &connListener{addr: c.LocalAddr(), conns: make(chan net.Conn, 1), done: make(chan struct{})}
+	ln.conns <- &listenedConn{Conn: c, ln: ln}
+	return ln
+}
+
+// connListener is the net.Listener returned by ConnListener
+type connListener struct {
+	addr  net.Addr
+	conns chan net.Conn
+	once  sync.Once
+	done  chan struct{}
+}
+
+func (ln *connListener) Accept() (net.Conn, error) {
+	select {
+	case c := <-ln.conns:
+		return c, nil
+	case <-ln.done:
+		return nil, net.ErrClosed
+	}
+}
+
+func (ln *connListener) Close() error {
+	ln.once.Do(func() { close(ln.done) })
+	return nil
+}
+
+func (ln *connListener) Addr() net.Addr {
+	return ln.addr
+}
+
+// listenedConn is the connection returned by a connListener,
+// which closes the listener once the connection has ended
+type listenedConn struct {
+	net.Conn
+	ln *connListener
+}
+
+func (c *listenedConn) Read(p []byte) (int, error) {
+	n, err := c.Conn.Read(p)
+	if err != nil {
+		c.ln.Close()
+	}
+	return n, err
+}
+
+func (c *listenedConn) Close() error {
+	c.ln.Close()
+	return c.Conn.Close()
+}
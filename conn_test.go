@@ -0,0 +1,87 @@
+package launcher
+
+import (
+	"context"
+	"io"
+	"net"
+	"testing"
+	"time"
+)
+
+func TestLauncherConn(t *testing.T) {
+
+	l, err := New(context.Background(), "cat", nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer l.Close()
+
+	if err := l.Start(); err != nil {
+		t.Fatal(err)
+	}
+
+	c := l.Conn()
+	if c.RemoteAddr().Network() != "stdio" {
+		t.Fatalf("unexpected network %q\n", c.RemoteAddr().Network())
+	}
+	if _, err := c.Write([]byte("foo")); err != nil {
+		t.Fatal(err)
+	}
+	if err := c.Close(); err != nil {
+		t.Fatal(err)
+	}
+	b, err := io.ReadAll(c)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if string(b) != "foo" {
+		t.Fatalf("expected %q, got %q\n", "foo", b)
+	}
+}
+
+func TestConnListener(t *testing.T) {
+
+	c, _ := net.Pipe()
+	ln := ConnListener(c)
+
+	got, err := ln.Accept()
+	if err != nil || got.LocalAddr() != c.LocalAddr() {
+		t.Fatalf("expected the connection, got %v, %v\n", got, err)
+	}
+
+	ln.Close()
+	if _, err := ln.Accept(); err != net.ErrClosed {
+		t.Fatalf("expected net.ErrClosed, got %v\n", err)
+	}
+}
+
+func TestConnListenerClosedByEOF(t *testing.T) {
+
+	c, other := net.Pipe()
+	ln := ConnListener(c)
+
+	got, err := ln.Accept()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	accepted := make(chan error, 1)
+	go func() {
+		_, err := ln.Accept()
+		accepted <- err
+	}()
+
+	// The other end going away ends the connection and the listener
+	other.Close()
+	if _, err := got.Read(make([]byte, 1)); err != io.EOF {
+		t.Fatalf("expected io.EOF, got %v\n", err)
+	}
+	select {
+	case err := <-accepted:
+		if err != net.ErrClosed {
+			t.Fatalf("expected net.ErrClosed, got %v\n", err)
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatal("listener not closed at the end of the connection")
+	}
+}
@@ -0,0 +1,123 @@
+package control
+
+import (
+	"bufio"
+	"encoding/json"
+	"errors"
+	"net"
+	"sync"
+	"syscall"
+	"time"
+
+	"github.com/gford1000-go/launcher"
+)
+
+// knownErrors are returned by the Client in place of
+// errors with matching messages received from the Server
+var knownErrors = []error{
+	launcher.ErrUnknownName,
+	launcher.ErrDuplicateName,
+	launcher.ErrAlreadyRunning,
+}
+
+// Client sends Requests to a Server.  It is safe for concurrent use
+type Client struct {
+	mu   sync.Mutex
+	conn net.Conn
+	r    *bufio.Reader
+	enc  *json.Encoder
+}
+
+// Dial connects to the Server listening on the socket at path
+func Dial(path string) (*Client, error) {
+	conn, err := net.Dial("unix", path)
+	if err != nil {
+		return nil, err
+	}
+	return &Client{
+		conn: conn,
+		r:    bufio.NewReader(conn),
+		enc:  json.NewEncoder(conn),
+	}, nil
+}
+
+// Close closes the connection to the Server
+func (c *Client) Close() error {
+	return c.conn.Close()
+}
+
+// List returns the status of all processes
+func (c *Client) List() ([]launcher.Status, error) {
+	resp, err := c.Do(Request{Command: CommandList})
+	if err != nil {
+		return nil, err
+	}
+	return resp.Processes, nil
+}
+
+// Inspect returns the status of the named process
+func (c *Client) Inspect(name string) (launcher.Status, error) {
+	return c.status(Request{Command: CommandInspect, Name: name})
+}
+
+// Start starts the named process
+func (c *Client) Start(name string) (launcher.Status, error) {
+	return c.status(Request{Command: CommandStart, Name: name})
+}
+
+// Stop terminates the named process, killing it if it
+// has not exited within the grace period
+func (c *Client) Stop(name string, grace time.Duration) (launcher.Status, error) {
+	return c.status(Request{Command: CommandStop, Name: name, Grace: grace.String()})
+}
+
+// Restart restarts the named process
+func (c *Client) Restart(name string) (launcher.Status, error) {
+	return c.status(Request{Command: CommandRestart, Name: name})
+}
+
+// Signal sends the signal to the named process
+func (c *Client) Signal(name string, sig syscall.Signal) (launcher.Status, error) {
+	return c.status(Request{Command: CommandSignal, Name: name, Signal: int(sig)})
+}
+
+// Do sends the Request and returns the Response, converting
+// any error reported in the Response to an error
+func (c *Client) Do(req Request) (Response, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if err := c.enc.Encode(req); err != nil {
+		return Response{}, err
+	}
+
+	b, err := c.r.ReadBytes('\n')
+	if err != nil {
+		return Response{}, err
+	}
+
+	var resp Response
+	if err := json.Unmarshal(b, &resp); err != nil {
+		return Response{}, err
+	}
+	if resp.Error != "" {
+		for _, e := range knownErrors {
+			if resp.Error == e.Error() {
+				return resp, e
+			}
+		}
+		return resp, errors.New(resp.Error)
+	}
+	return resp, nil
+}
+
+func (c *Client) status(req Request) (launcher.Status, error) {
+	resp, err := c.Do(req)
+	if err != nil {
+		return launcher.Status{}, err
+	}
+	if resp.Status == nil {
+		return launcher.Status{}, errors.New("response contained no status")
+	}
+	return *resp.Status, nil
+}
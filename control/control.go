@@ -0,0 +1,48 @@
+// Package control exposes a launcher.Manager on a Unix domain socket,
+// using a protocol of newline delimited JSON, so that sibling processes
+// and command line tools can query and control its processes.
+//
+// Each line sent to the socket is a Request, answered by a single line
+// containing a Response.  For example, using socat:
+//
+//	$ echo '{"command":"stop","name":"web","grace":"5s"}' | socat - UNIX-CONNECT:/run/app.sock
+//
+// Client provides a Go implementation of the protocol.
+package control
+
+import (
+	"time"
+
+	"github.com/gford1000-go/launcher"
+)
+
+// Commands supported by the protocol
+const (
+	CommandList    = "list"
+	CommandInspect = "inspect"
+	CommandStart   = "start"
+	CommandStop    = "stop"
+	CommandRestart = "restart"
+	CommandSignal  = "signal"
+)
+
+// defaultGrace is the time allowed for a process to exit on
+// stop, if not specified in the Request
+const defaultGrace = 10 * time.Second
+
+// Request is a command sent to the socket
+type Request struct {
+	Command string `json:"command"`
+	Name    string `json:"name,omitempty"`
+	// Grace is the duration allowed for stop, such as "5s"
+	Grace string `json:"grace,omitempty"`
+	// Signal is the signal number to send
+	Signal int `json:"signal,omitempty"`
+}
+
+// Response is the reply to a Request.  Error is set if it failed
+type Response struct {
+	Error     string            `json:"error,omitempty"`
+	Status    *launcher.Status  `json:"status,omitempty"`
+	Processes []launcher.Status `json:"processes,omitempty"`
+}
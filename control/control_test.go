@@ -0,0 +1,160 @@
+package control
+
+import (
+	"context"
+	"errors"
+	"net"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/gford1000-go/launcher"
+)
+
+func newClient(t *testing.T) (*launcher.Manager, *Client) {
+	m, err := launcher.NewManager(context.Background())
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// Socket paths are length limited, so avoid the long t.TempDir()
+	dir, err := os.MkdirTemp("", "ctl")
+	if err != nil {
+		t.Fatal(err)
+	}
+	path := filepath.Join(dir, "s.sock")
+
+	ln, err := Listen(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	s := NewServer(m)
+	go s.Serve(ln)
+
+	c, err := Dial(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	t.Cleanup(func() {
+		c.Close()
+		s.Close()
+		m.Close()
+		os.RemoveAll(dir)
+	})
+	return m, c
+}
+
+func TestClient(t *testing.T) {
+
+	m, c := newClient(t)
+
+	if err := m.Launch("sleeper", launcher.Spec{File: "sleep", Args: []string{"10"}}); err != nil {
+		t.Fatal(err)
+	}
+
+	l, err := c.List()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(l) != 1 || l[0].Name != "sleeper" || !l[0].Running {
+		t.Fatalf("unexpected list: %+v\n", l)
+	}
+
+	if _, err := c.Start("sleeper"); err != launcher.ErrAlreadyRunning {
+		t.Fatal(err)
+	}
+
+	s, err := c.Stop("sleeper", time.Second)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if s.Running {
+		t.Fatal("still running")
+	}
+
+	s, err = c.Start("sleeper")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !s.Running {
+		t.Fatal("not running")
+	}
+
+	if _, err := c.Inspect("zzz"); err != launcher.ErrUnknownName {
+		t.Fatal(err)
+	}
+}
+
+func TestClientUnknownCommand(t *testing.T) {
+
+	_, c := newClient(t)
+
+	if _, err := c.Do(Request{Command: "zzz"}); err == nil {
+		t.Fatal("expected error for unknown command")
+	}
+}
+
+func TestListen(t *testing.T) {
+
+	dir, err := os.MkdirTemp("", "ctl")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+	path := filepath.Join(dir, "s.sock")
+
+	// A file which is not a socket is never replaced
+	if err := os.WriteFile(path, []byte("data"), 0600); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := Listen(path); !errors.Is(err, errNotSocket) {
+		t.Fatalf("expected %v, got %v\n", errNotSocket, err)
+	}
+	if b, err := os.ReadFile(path); err != nil || string(b) != "data" {
+		t.Fatalf("expected file to be kept, got %q, %v\n", b, err)
+	}
+	os.Remove(path)
+
+	ln, err := Listen(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	fi, err := os.Lstat(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if fi.Mode()&os.ModeSocket == 0 || fi.Mode().Perm() != 0600 {
+		t.Fatalf("expected socket accessible only to the user, got %v\n", fi.Mode())
+	}
+
+	// A socket in use is never replaced
+	if _, err := Listen(path); !errors.Is(err, errSocketInUse) {
+		t.Fatalf("expected %v, got %v\n", errSocketInUse, err)
+	}
+	conn, err := net.Dial("unix", path)
+	if err != nil {
+		t.Fatalf("expected the socket to remain in use, got %v\n", err)
+	}
+	conn.Close()
+	ln.Close()
+
+	// A stale socket is replaced, leaving no other files behind
+	stale, err := net.Listen("unix", path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	stale.(*net.UnixListener).SetUnlinkOnClose(false)
+	stale.Close()
+
+	ln, err = Listen(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	ln.Close()
+	if entries, _ := os.ReadDir(dir); len(entries) != 0 {
+		t.Fatalf("expected socket to be removed, found %v\n", entries)
+	}
+}
@@ -0,0 +1,221 @@
+package control
+
+import (
+	"bufio"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net"
+	"os"
+	"path/filepath"
+	"sync"
+	"syscall"
+	"time"
+
+	"github.com/gford1000-go/launcher"
+)
+
+var errNotSocket = errors.New("file exists and is not a socket")
+var errSocketInUse = errors.New("socket is in use")
+
+// maxRequestSize limits the length of a single Request line
+const maxRequestSize = 64 * 1024
+
+// Listen creates a Unix domain socket at path, replacing any stale
+// socket, and restricts its access to the current user.  The socket is
+// created in a private directory beside path and then moved into place,
+// so that it is never accessible to others.  An error is returned if
+// a file other than a socket exists at path, or if the socket there
+// accepts connections.  The socket is removed when the listener is closed
+func Listen(path string) (net.Listener, error) {
+	if fi, err := os.Lstat(path); err == nil {
+		if fi.Mode()&os.ModeSocket == 0 {
+			return nil, fmt.Errorf("%w: %s", errNotSocket, path)
+		}
+		if err := checkStale(path); err != nil {
+			return nil, err
+		}
+	} else if !errors.Is(err, os.ErrNotExist) {
+		return nil, err
+	}
+
+	// The name of the socket is kept short, as its path is length limited
+	dir, err := os.MkdirTemp(filepath.Dir(path), "")
+	if err != nil {
+		return nil, err
+	}
+	defer os.RemoveAll(dir)
+
+	tmp := filepath.Join(dir, "s")
+	ln, err := net.Listen("unix", tmp)
+	if err != nil {
+		return nil, err
+	}
+	ln.(*net.UnixListener).SetUnlinkOnClose(false)
+
+	if err := os.Chmod(tmp, 0600); err != nil {
+		ln.Close()
+		return nil, err
+	}
+	if err := os.Rename(tmp, path); err != nil {
+		ln.Close()
+		return nil, err
+	}
+	fi, err := os.Lstat(path)
+	if err != nil {
+		ln.Close()
+		return nil, err
+	}
+	return &listener{Listener: ln, path: path, fi: fi}, nil
+}
+
+// checkStale returns an error unless the socket at path is stale,
+// having no listener to accept connections
+func checkStale(path string) error {
+	conn, err := net.Dial("unix", path)
+	if err == nil {
+		conn.Close()
+		return fmt.Errorf("%w: %s", errSocketInUse, path)
+	}
+	if errors.Is(err, syscall.ECONNREFUSED) {
+		return nil
+	}
+	return err
+}
+
+// listener removes its socket, which was moved after it was created,
+// when it is closed, unless the socket has since been replaced
+type listener struct {
+	net.Listener
+	path string
+	fi   os.FileInfo
+	once sync.Once
+}
+
+func (l *listener) Close() error {
+	err := l.Listener.Close()
+	l.once.Do(func() {
+		if fi, err := os.Lstat(l.path); err == nil && os.SameFile(fi, l.fi) {
+			os.Remove(l.path)
+		}
+	})
+	return err
+}
+
+// Server answers Requests for the processes of a Manager
+type Server struct {
+	m     *launcher.Manager
+	mu    sync.Mutex
+	ln    net.Listener
+	conns map[net.Conn]struct{}
+}
+
+// NewServer creates a new Server for the Manager
+func NewServer(m *launcher.Manager) *Server {
+	return &Server{
+		m:     m,
+		conns: map[net.Conn]struct{}{},
+	}
+}
+
+// Serve accepts connections on the listener until it is closed
+func (s *Server) Serve(ln net.Listener) error {
+	s.mu.Lock()
+	s.ln = ln
+	s.mu.Unlock()
+
+	for {
+		conn, err := ln.Accept()
+		if err != nil {
+			if errors.Is(err, net.ErrClosed) {
+				return nil
+			}
+			return err
+		}
+
+		s.mu.Lock()
+		s.conns[conn] = struct{}{}
+		s.mu.Unlock()
+
+		go s.handle(conn)
+	}
+}
+
+// Close stops the Server, closing its listener and all connections
+func (s *Server) Close() error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	var err error
+	if s.ln != nil {
+		err = s.ln.Close()
+	}
+	for conn := range s.conns {
+		conn.Close()
+		delete(s.conns, conn)
+	}
+	return err
+}
+
+// handle answers each Request received on the connection
+func (s *Server) handle(conn net.Conn) {
+	defer func() {
+		s.mu.Lock()
+		delete(s.conns, conn)
+		s.mu.Unlock()
+		conn.Close()
+	}()
+
+	scanner := bufio.NewScanner(conn)
+	scanner.Buffer(make([]byte, 4096), maxRequestSize)
+	enc := json.NewEncoder(conn)
+
+	for scanner.Scan() {
+		var req Request
+		var resp Response
+		if err := json.Unmarshal(scanner.Bytes(), &req); err != nil {
+			resp.Error = fmt.Sprintf("invalid request: %v", err)
+		} else {
+			resp = s.do(req)
+		}
+		if err := enc.Encode(resp); err != nil {
+			return
+		}
+	}
+}
+
+// do carries out the Request
+func (s *Server) do(req Request) Response {
+	var err error
+
+	switch req.Command {
+	case CommandList:
+		return Response{Processes: s.m.List()}
+	case CommandInspect:
+	case CommandStart:
+		err = s.m.Start(req.Name)
+	case CommandStop:
+		grace := defaultGrace
+		if req.Grace != "" {
+			if grace, err = time.ParseDuration(req.Grace); err != nil {
+				return Response{Error: fmt.Sprintf("invalid grace: %v", err)}
+			}
+		}
+		err = s.m.Stop(req.Name, grace)
+	case CommandRestart:
+		err = s.m.Restart(req.Name)
+	case CommandSignal:
+		err = s.m.Signal(req.Name, syscall.Signal(req.Signal))
+	default:
+		return Response{Error: fmt.Sprintf("unknown command %q", req.Command)}
+	}
+	if err != nil {
+		return Response{Error: err.Error()}
+	}
+
+	st, err := s.m.Inspect(req.Name)
+	if err != nil {
+		return Response{Error: err.Error()}
+	}
+	return Response{Status: &st}
+}
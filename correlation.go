@@ -0,0 +1,84 @@
+package launcher
+
+import (
+	"context"
+	"errors"
+)
+
+// DefaultCorrelationEnv is the environment variable through which
+// the correlation ID of a launch is passed to the process
+const DefaultCorrelationEnv = "LAUNCHER_RUN_ID"
+
+var errMissingCorrelationID = errors.New("correlation id must be provided")
+
+var errMissingCorrelationEnv = errors.New("correlation environment variable must be named")
+
+// correlationKey is the context key of a correlation ID
+type correlationKey struct{}
+
+// ContextWithCorrelationID returns a context carrying the correlation ID,
+// which is used by Launchers created with the context, or one derived
+// from it, unless they set WithCorrelationID
+func ContextWithCorrelationID(ctx context.Context, id string) context.Context {
+	return context.WithValue(ctx, correlationKey{}, id)
+}
+
+// CorrelationIDFromContext returns the correlation ID carried by the
+// context, if any
+func CorrelationIDFromContext(ctx context.Context) (string, bool) {
+	id, ok := ctx.Value(correlationKey{}).(string)
+	return id, ok && id != ""
+}
+
+// WithCorrelationID passes the ID to the process in the environment
+// variable DefaultCorrelationEnv, or that set by WithCorrelationEnv,
+// and tags the logs and events describing the launch with it, so that
+// the launch can be traced end to end
+func WithCorrelationID(id string) Option {
+	return func(o *options) error {
+		if id == "" {
+			return errMissingCorrelationID
+		}
+		o.correlationID = id
+		return nil
+	}
+}
+
+// WithCorrelationEnv sets the environment variable through which
+// the correlation ID is passed to the process
+func WithCorrelationEnv(name string) Option {
+	return func(o *options) error {
+		if name == "" {
+			return errMissingCorrelationEnv
+		}
+		o.correlationEnv = name
+		return nil
+	}
+}
+
+// CorrelationID returns the correlation ID of the launch,
+// which is empty if none was set
+func (l *Launcher) CorrelationID() string {
+	return l.opts.correlationID
+}
+
+// correlate sets the correlation ID from the context if it has not
+// been set by WithCorrelationID
+func (o *options) correlate(ctx context.Context) {
+	if o.correlationID == "" {
+		o.correlationID, _ = CorrelationIDFromContext(ctx)
+	}
+}
+
+// correlationEnv passes any correlation ID to the process,
+// and must be called during initialise
+func (l *Launcher) correlationEnv() {
+	if l.opts.correlationID == "" {
+		return
+	}
+	name := l.opts.correlationEnv
+	if name == "" {
+		name = DefaultCorrelationEnv
+	}
+	l.cmd.Env = setEnv(l.cmd.Env, []string{name}, l.opts.correlationID)
+}
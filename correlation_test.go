@@ -0,0 +1,77 @@
+package launcher
+
+import (
+	"bytes"
+	"context"
+	"log/slog"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestWithCorrelationID(t *testing.T) {
+
+	tests := []struct {
+		ctx      context.Context
+		opts     []Option
+		expected string
+	}{
+		{context.Background(), []Option{WithCorrelationID("abc")}, "LAUNCHER_RUN_ID=abc"},
+		{context.Background(), []Option{WithCorrelationID("abc"), WithCorrelationEnv("TRACE_ID")}, "TRACE_ID=abc"},
+		{ContextWithCorrelationID(context.Background(), "xyz"), nil, "LAUNCHER_RUN_ID=xyz"},
+		{ContextWithCorrelationID(context.Background(), "xyz"), []Option{WithCorrelationID("abc")}, "LAUNCHER_RUN_ID=abc"},
+		{context.Background(), nil, ""},
+	}
+
+	for _, test := range tests {
+		l, err := NewWithOptions(test.ctx, "sh", nil, []string{"-c", "env | grep -E '^(LAUNCHER_RUN_ID|TRACE_ID)=' || true"}, test.opts...)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		res, err := l.RunCaptured()
+		l.Close()
+		if err != nil {
+			t.Fatal(err)
+		}
+		if got := strings.TrimSpace(string(res.Stdout)); got != test.expected {
+			t.Fatalf("expected %q, got %q\n", test.expected, got)
+		}
+	}
+
+	if _, err := NewWithOptions(context.Background(), "true", nil, nil, WithCorrelationID("")); err == nil {
+		t.Fatal("expected error for empty correlation id")
+	}
+}
+
+func TestCorrelationIDTagging(t *testing.T) {
+
+	ctx := ContextWithCorrelationID(context.Background(), "run-42")
+
+	l, err := New(ctx, "true", nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer l.Close()
+
+	var buf bytes.Buffer
+	slog.New(slog.NewTextHandler(&buf, nil)).Info("launch", "cmd", l)
+	if !strings.Contains(buf.String(), "cmd.correlation_id=run-42") {
+		t.Fatalf("expected correlation id in log, got %q\n", buf.String())
+	}
+
+	events := make(chan Event, 10)
+	s, err := NewSupervisor(ctx, Spec{File: "true"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	s.Events = events
+	if err := s.Start(); err != nil {
+		t.Fatal(err)
+	}
+	defer s.Stop(time.Second)
+
+	if e := <-events; e.CorrelationID != "run-42" {
+		t.Fatalf("expected correlation id in event, got %+v\n", e)
+	}
+}
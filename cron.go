@@ -0,0 +1,191 @@
+package launcher
+
+import (
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
+var errInvalidCron = errors.New("invalid cron expression")
+
+// cronSearchLimit bounds the search for the next matching time, so
+// that expressions which can never match do not search forever
+const cronSearchLimit = 5
+
+// Schedule determines the times at which a scheduled job runs
+type Schedule interface {
+	// Next returns the first time after t at which the job should
+	// run, or the zero time if it should not run again
+	Next(t time.Time) time.Time
+}
+
+// Every returns a Schedule which runs at fixed intervals
+func Every(d time.Duration) Schedule {
+	return interval(d)
+}
+
+type interval time.Duration
+
+func (i interval) Next(t time.Time) time.Time {
+	if i <= 0 {
+		return time.Time{}
+	}
+	return t.Add(time.Duration(i))
+}
+
+// cronField is the set of values matched by a field of a
+// cron expression, as a bit mask
+type cronField uint64
+
+func (f cronField) has(v int) bool {
+	return f&(1<<uint(v)) != 0
+}
+
+// cronSchedule is a Schedule parsed from a cron expression
+type cronSchedule struct {
+	minute, hour, dom, month, dow cronField
+	// domAll and dowAll record whether the day fields are unrestricted,
+	// as a day matches if either restricted day field matches
+	domAll, dowAll bool
+}
+
+// cronMacros are the shorthand expressions accepted by ParseCron
+var cronMacros = map[string]string{
+	"@yearly":   "0 0 1 1 *",
+	"@annually": "0 0 1 1 *",
+	"@monthly":  "0 0 1 * *",
+	"@weekly":   "0 0 * * 0",
+	"@daily":    "0 0 * * *",
+	"@midnight": "0 0 * * *",
+	"@hourly":   "0 * * * *",
+}
+
+// ParseCron parses a standard five field cron expression (minute, hour,
+// day of month, month and day of week), in which each field may be *,
+// a value, a range or a comma separated list of these, each optionally
+// followed by a /step.  The macros @yearly, @monthly, @weekly, @daily
+// and @hourly are accepted, as is "@every <duration>".  Times are
+// matched in the location of the time passed to Next
+func ParseCron(expr string) (Schedule, error) {
+	expr = strings.TrimSpace(expr)
+	if d, ok := strings.CutPrefix(expr, "@every "); ok {
+		dur, err := time.ParseDuration(strings.TrimSpace(d))
+		if err != nil || dur <= 0 {
+			return nil, fmt.Errorf("%w: %q", errInvalidCron, expr)
+		}
+		return Every(dur), nil
+	}
+	if m, ok := cronMacros[expr]; ok {
+		expr = m
+	}
+
+	fields := strings.Fields(expr)
+	if len(fields) != 5 {
+		return nil, fmt.Errorf("%w: %q must have 5 fields", errInvalidCron, expr)
+	}
+
+	bounds := [5][2]int{{0, 59}, {0, 23}, {1, 31}, {1, 12}, {0, 7}}
+	var parsed [5]cronField
+	for i, f := range fields {
+		v, err := parseCronField(f, bounds[i][0], bounds[i][1])
+		if err != nil {
+			return nil, fmt.Errorf("%w: %q: %v", errInvalidCron, expr, err)
+		}
+		parsed[i] = v
+	}
+
+	// Sunday may be written as either 0 or 7
+	dow := parsed[4]
+	if dow.has(7) {
+		dow |= 1
+	}
+
+	return &cronSchedule{
+		minute: parsed[0],
+		hour:   parsed[1],
+		dom:    parsed[2],
+		month:  parsed[3],
+		dow:    dow,
+		domAll: fields[2] == "*",
+		dowAll: fields[4] == "*",
+	}, nil
+}
+
+// parseCronField parses a single field, whose values must lie within [lo, hi]
+func parseCronField(field string, lo, hi int) (cronField, error) {
+	var f cronField
+	for _, part := range strings.Split(field, ",") {
+		rng, stepStr, hasStep := strings.Cut(part, "/")
+
+		step := 1
+		if hasStep {
+			s, err := strconv.Atoi(stepStr)
+			if err != nil || s <= 0 {
+				return 0, fmt.Errorf("invalid step %q", part)
+			}
+			step = s
+		}
+
+		start, end := lo, hi
+		if rng != "*" {
+			first, last, isRange := strings.Cut(rng, "-")
+			var err error
+			if start, err = strconv.Atoi(first); err != nil {
+				return 0, fmt.Errorf("invalid value %q", part)
+			}
+			end = start
+			if isRange {
+				if end, err = strconv.Atoi(last); err != nil {
+					return 0, fmt.Errorf("invalid value %q", part)
+				}
+			} else if hasStep {
+				end = hi
+			}
+		}
+		if start < lo || end > hi || start > end {
+			return 0, fmt.Errorf("%q is outside the range %d-%d", part, lo, hi)
+		}
+
+		for v := start; v <= end; v += step {
+			f |= 1 << uint(v)
+		}
+	}
+	return f, nil
+}
+
+// Next returns the first minute after t matching the expression
+func (c *cronSchedule) Next(t time.Time) time.Time {
+	loc := t.Location()
+	t = t.Truncate(time.Minute).Add(time.Minute)
+	limit := t.AddDate(cronSearchLimit, 0, 0)
+
+	for t.Before(limit) {
+		y, m, d := t.Date()
+		switch {
+		case !c.month.has(int(m)):
+			t = time.Date(y, m+1, 1, 0, 0, 0, 0, loc)
+		case !c.dayMatches(t):
+			t = time.Date(y, m, d+1, 0, 0, 0, 0, loc)
+		case !c.hour.has(t.Hour()):
+			t = time.Date(y, m, d, t.Hour()+1, 0, 0, 0, loc)
+		case !c.minute.has(t.Minute()):
+			t = t.Add(time.Minute)
+		default:
+			return t
+		}
+	}
+	return time.Time{}
+}
+
+// dayMatches applies the cron rule that, when both day fields are
+// restricted, a day matches if it satisfies either of them
+func (c *cronSchedule) dayMatches(t time.Time) bool {
+	dom := c.dom.has(t.Day())
+	dow := c.dow.has(int(t.Weekday()))
+	if c.domAll || c.dowAll {
+		return dom && dow
+	}
+	return dom || dow
+}
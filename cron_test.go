@@ -0,0 +1,57 @@
+package launcher
+
+import (
+	"testing"
+	"time"
+)
+
+func TestParseCron(t *testing.T) {
+
+	from := time.Date(2024, time.January, 15, 10, 30, 0, 0, time.UTC) // a Monday
+
+	tests := []struct {
+		expr string
+		next time.Time
+	}{
+		{"* * * * *", time.Date(2024, time.January, 15, 10, 31, 0, 0, time.UTC)},
+		{"*/15 * * * *", time.Date(2024, time.January, 15, 10, 45, 0, 0, time.UTC)},
+		{"0 9-17 * * *", time.Date(2024, time.January, 15, 11, 0, 0, 0, time.UTC)},
+		{"5,10 8 * * *", time.Date(2024, time.January, 16, 8, 5, 0, 0, time.UTC)},
+		{"0 0 1 * *", time.Date(2024, time.February, 1, 0, 0, 0, 0, time.UTC)},
+		{"0 12 * * 7", time.Date(2024, time.January, 21, 12, 0, 0, 0, time.UTC)},
+		{"0 12 1 * 3", time.Date(2024, time.January, 17, 12, 0, 0, 0, time.UTC)},
+		{"0 0 29 2 *", time.Date(2024, time.February, 29, 0, 0, 0, 0, time.UTC)},
+		{"@hourly", time.Date(2024, time.January, 15, 11, 0, 0, 0, time.UTC)},
+		{"@every 90s", from.Add(90 * time.Second)},
+	}
+
+	for _, test := range tests {
+		s, err := ParseCron(test.expr)
+		if err != nil {
+			t.Fatalf("%q: %v\n", test.expr, err)
+		}
+		if next := s.Next(from); !next.Equal(test.next) {
+			t.Fatalf("%q: expected %v, got %v\n", test.expr, test.next, next)
+		}
+	}
+}
+
+func TestParseCronInvalid(t *testing.T) {
+
+	for _, expr := range []string{"", "* * * *", "60 * * * *", "* * 0 * *", "5-1 * * * *", "*/0 * * * *", "x * * * *", "@every -1s"} {
+		if _, err := ParseCron(expr); err == nil {
+			t.Fatalf("expected error for %q\n", expr)
+		}
+	}
+}
+
+func TestParseCronNeverMatches(t *testing.T) {
+
+	s, err := ParseCron("0 0 31 2 *")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if next := s.Next(time.Now()); !next.IsZero() {
+		t.Fatalf("expected no next time, got %v\n", next)
+	}
+}
@@ -0,0 +1,34 @@
+package launcher
+
+// WithArgs replaces the arguments with which the Launcher is created,
+// typically when deriving a Launcher using With
+func WithArgs(args ...string) Option {
+	return func(o *options) error {
+		o.args = append([]string{}, args...)
+		return nil
+	}
+}
+
+// WithEnv replaces the environment with which the Launcher is created,
+// typically when deriving a Launcher using With
+func WithEnv(env ...string) Option {
+	return func(o *options) error {
+		o.env = append([]string{}, env...)
+		return nil
+	}
+}
+
+// With creates a new, unstarted Launcher with the same file, arguments,
+// environment and Options as this one, and the same parent context, with
+// the Options applied after those of this Launcher.  WithArgs, WithEnv
+// and WithDir override the corresponding settings, so that a Launcher can
+// serve as the template for others
+func (l *Launcher) With(opts ...Option) (*Launcher, error) {
+	if l.adopted {
+		return nil, errAdopted
+	}
+
+	spec := l.spec
+	spec.Options = append(append([]Option{}, spec.Options...), opts...)
+	return spec.New(l.parent)
+}
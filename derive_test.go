@@ -0,0 +1,79 @@
+package launcher
+
+import (
+	"context"
+	"errors"
+	"io"
+	"testing"
+)
+
+func TestLauncherWith(t *testing.T) {
+
+	base, err := NewWithOptions(context.Background(), "sh", []string{"NAME=base"}, []string{"-c", "echo $NAME $(pwd) $0", "arg"}, WithSecretEnv("NAME"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer base.Close()
+
+	run := func(l *Launcher) string {
+		defer l.Close()
+		if err := l.Run(); err != nil {
+			t.Fatal(err)
+		}
+		b, err := io.ReadAll(l.StdOutReader())
+		if err != nil {
+			t.Fatal(err)
+		}
+		return string(b)
+	}
+
+	l, err := base.With(WithEnv("NAME=derived"), WithDir("/"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if env := l.GetEnv(); len(env) != 1 || env[0] != "NAME="+redactedValue {
+		t.Fatalf("expected derived Launcher to keep the options of the base, got %v\n", env)
+	}
+	if out := run(l); out != "derived / arg\n" {
+		t.Fatalf("unexpected output %q\n", out)
+	}
+
+	l, err = base.With(WithArgs("-c", "echo $NAME $0", "other"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if out := run(l); out != "base other\n" {
+		t.Fatalf("unexpected output %q\n", out)
+	}
+
+	if base.IsStarted() || base.GetArgs()[2] != "arg" {
+		t.Fatal("expected base Launcher to be unchanged")
+	}
+}
+
+func TestLauncherWithAfterCancel(t *testing.T) {
+
+	ctx, cancel := context.WithCancel(context.Background())
+
+	base, err := New(ctx, "echo", nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer base.Close()
+
+	// Closing the base does not affect Launchers derived from it
+	base.Close()
+	l, err := base.With()
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer l.Close()
+	if err := l.Run(); err != nil {
+		t.Fatal(err)
+	}
+
+	cancel()
+	if _, err := base.With(); !errors.Is(err, context.Canceled) {
+		t.Fatal(err)
+	}
+}
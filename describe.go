@@ -0,0 +1,132 @@
+package launcher
+
+import (
+	"errors"
+	"log/slog"
+	"strconv"
+	"strings"
+)
+
+// redactedValue replaces values hidden by redaction
+const redactedValue = "***"
+
+var errMissingRedactor = errors.New("redactor must be provided")
+
+// Redactor returns a copy of the command line arguments in a form
+// which is safe to log, such as with any credentials replaced
+type Redactor func(args []string) []string
+
+// WithRedactor sets the Redactor applied to the arguments by String and
+// LogValue.  The process itself always receives the original arguments
+func WithRedactor(r Redactor) Option {
+	return func(o *options) error {
+		if r == nil {
+			return errMissingRedactor
+		}
+		o.redactor = r
+		return nil
+	}
+}
+
+// RedactFlags returns a Redactor which hides the values of the named
+// flags, whether written as "-name=value", "--name=value" or as the
+// argument following "-name" or "--name"
+func RedactFlags(names ...string) Redactor {
+	flags := map[string]bool{}
+	for _, n := range names {
+		flags[strings.TrimLeft(n, "-")] = true
+	}
+
+	return func(args []string) []string {
+		redacted := make([]string, len(args))
+		for i := 0; i < len(args); i++ {
+			arg := args[i]
+			redacted[i] = arg
+			if !strings.HasPrefix(arg, "-") {
+				continue
+			}
+
+			name, _, hasValue := strings.Cut(strings.TrimLeft(arg, "-"), "=")
+			switch {
+			case !flags[name]:
+			case hasValue:
+				redacted[i] = arg[:strings.Index(arg, "=")+1] + redactedValue
+			case i+1 < len(args):
+				i++
+				redacted[i] = redactedValue
+			}
+		}
+		return redacted
+	}
+}
+
+// displayArgs returns the arguments with any redaction applied
+func (l *Launcher) displayArgs() []string {
+	return l.opts.redact(l.GetArgs())
+}
+
+// redact returns the arguments with any redaction applied
+func (o *options) redact(args []string) []string {
+	if o.redactor != nil {
+		return o.redactor(args)
+	}
+	return args
+}
+
+// displayPath returns the path of the command, or the file
+// if it has yet to be resolved
+func (l *Launcher) displayPath() string {
+	if l.path == "" {
+		return l.file
+	}
+	return l.path
+}
+
+// envKeys returns the names of the environment variables
+func (l *Launcher) envKeys() []string {
+	env := l.GetEnv()
+	keys := make([]string, 0, len(env))
+	for _, kv := range env {
+		k, _, _ := strings.Cut(kv, "=")
+		keys = append(keys, k)
+	}
+	return keys
+}
+
+// String describes the command line in the form
+// "path arg1 arg2 (env: K1,K2)", with arguments redacted by any
+// Redactor and only the names of environment variables shown
+func (l *Launcher) String() string {
+	var b strings.Builder
+	b.WriteString(l.displayPath())
+	for _, a := range l.displayArgs() {
+		b.WriteByte(' ')
+		if a == "" || strings.ContainsAny(a, " \t\n\"'") {
+			a = strconv.Quote(a)
+		}
+		b.WriteString(a)
+	}
+	if keys := l.envKeys(); len(keys) > 0 {
+		b.WriteString(" (env: ")
+		b.WriteString(strings.Join(keys, ","))
+		b.WriteByte(')')
+	}
+	return b.String()
+}
+
+// LogValue implements slog.LogValuer, describing the command as String
+// does, along with the pid and State of the process, and any
+// correlation ID of the launch
+func (l *Launcher) LogValue() slog.Value {
+	attrs := []slog.Attr{
+		slog.String("path", l.displayPath()),
+		slog.Any("args", l.displayArgs()),
+		slog.Any("env", l.envKeys()),
+		slog.Int("pid", l.Pid()),
+		slog.String("state", l.State().String()),
+	}
+	if id := l.CorrelationID(); id != "" {
+		attrs = append(attrs, slog.String("correlation_id", id))
+	}
+	return slog.GroupValue(attrs...)
+}
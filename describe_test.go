@@ -0,0 +1,47 @@
+package launcher
+
+import (
+	"bytes"
+	"context"
+	"log/slog"
+	"strings"
+	"testing"
+)
+
+func TestLauncherString(t *testing.T) {
+
+	l, err := NewWithOptions(context.Background(), "echo", []string{"HOME=/root", "TOKEN=abc"},
+		[]string{"--user", "bob", "--password", "hunter2", "-key=secret", "hello world"},
+		WithRedactor(RedactFlags("password", "key")))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer l.Close()
+
+	expected := l.GetPath() + ` --user bob --password *** -key=*** "hello world" (env: HOME,TOKEN)`
+	if s := l.String(); s != expected {
+		t.Fatalf("expected %q, got %q\n", expected, s)
+	}
+
+	// The process receives the original arguments
+	if args := l.GetArgs(); args[3] != "hunter2" {
+		t.Fatalf("expected original arguments, got %v\n", args)
+	}
+}
+
+func TestLauncherLogValue(t *testing.T) {
+
+	l, err := NewWithOptions(context.Background(), "echo", nil, []string{"--password", "hunter2"}, WithRedactor(RedactFlags("password")))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer l.Close()
+
+	var buf bytes.Buffer
+	slog.New(slog.NewTextHandler(&buf, nil)).Info("launching", "cmd", l)
+
+	out := buf.String()
+	if strings.Contains(out, "hunter2") || !strings.Contains(out, "cmd.state=created") || !strings.Contains(out, "cmd.path=") {
+		t.Fatalf("unexpected log output %q\n", out)
+	}
+}
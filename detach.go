@@ -0,0 +1,109 @@
+package launcher
+
+import (
+	"errors"
+	"os"
+	"os/exec"
+)
+
+var errDetachedFiles = errors.New("descriptors cannot be passed to a detached process")
+var errDetachedCleanup = errors.New("a detached process cannot use files removed by Close")
+var errDetachedStdin = errors.New("stdin of a detached process is the null device")
+
+// WithDetachedOutput sets the files, created or appended to, which
+// receive the stdout and stderr of a process started by StartDetached.
+// An empty path discards the output
+func WithDetachedOutput(stdout, stderr string) Option {
+	return func(o *options) error {
+		o.detachedStdout = stdout
+		o.detachedStderr = stderr
+		return nil
+	}
+}
+
+// StartDetached launches the process fully detached from the parent, in
+// a new session with stdin from the null device and its output written
+// to the files set by WithDetachedOutput.  The process is not connected
+// to the Launcher, which remains unstarted, and is not killed when the
+// Launcher's context ends.  Any PID file is written but not removed, and
+// the returned pid may be used with Adopt to manage the process later.
+// An error is returned if the Launcher passes descriptors to the process,
+// as set by WithExtraFile, WithListener, WithSecretFD or WithHeartbeat,
+// sets its stdin, as by WithStdinBytes, or owns files which Close would
+// remove while the process uses them, as for WithTempWorkdir or NewScript
+func (l *Launcher) StartDetached() (int, error) {
+	if l.adopted {
+		return 0, errAdopted
+	}
+	if len(l.opts.extraFiles) > 0 || len(l.opts.listeners) > 0 ||
+		len(l.opts.secretFDs) > 0 || l.opts.heartbeat != nil {
+		return 0, errDetachedFiles
+	}
+	if l.opts.tempWorkdir != nil || l.script != "" {
+		return 0, errDetachedCleanup
+	}
+	if l.opts.stdinBytes != nil || l.opts.stdinFS != nil {
+		return 0, errDetachedStdin
+	}
+
+	if err := l.admit(l.ctx); err != nil {
+		return 0, err
+	}
+	// The process outlives any lock or Limiter slot, which are
+	// only held while it is started
+	defer l.releaseAll()
+
+	cmd := exec.Command(l.cmd.Path)
+	cmd.Args = l.copyStringArray(l.cmd.Args)
+	cmd.Env = l.copyStringArray(l.cmd.Env)
+	cmd.Dir = l.cmd.Dir
+	cmd.SysProcAttr = detachedAttr(l.cmd.SysProcAttr)
+
+	var files []*os.File
+	defer func() {
+		for _, f := range files {
+			f.Close()
+		}
+	}()
+
+	open := func(path string, flag int) (*os.File, error) {
+		if path == "" {
+			path = os.DevNull
+		}
+		f, err := os.OpenFile(path, flag, 0o644)
+		if err == nil {
+			files = append(files, f)
+		}
+		return f, err
+	}
+
+	var err error
+	if cmd.Stdin, err = open("", os.O_RDONLY); err != nil {
+		return 0, err
+	}
+	if cmd.Stdout, err = open(l.opts.detachedStdout, os.O_WRONLY|os.O_CREATE|os.O_APPEND); err != nil {
+		return 0, err
+	}
+	if cmd.Stderr, err = open(l.opts.detachedStderr, os.O_WRONLY|os.O_CREATE|os.O_APPEND); err != nil {
+		return 0, err
+	}
+
+	if err := startChild(cmd); err != nil {
+		if l.opts.breaker != nil {
+			l.opts.breaker.record(err)
+		}
+		return 0, l.launchError(StageStart, err)
+	}
+	pid := cmd.Process.Pid
+
+	// The process is reaped if it exits while the parent is still running,
+	// without which it would remain a zombie
+	go waitChild(cmd)
+
+	if l.opts.pidFile != "" {
+		if _, err := writePIDFile(l.opts.pidFile, pid); err != nil {
+			return pid, err
+		}
+	}
+	return pid, nil
+}
@@ -0,0 +1,160 @@
+package launcher
+
+import (
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"syscall"
+	"testing"
+	"time"
+)
+
+func TestStartDetached(t *testing.T) {
+
+	dir := t.TempDir()
+	out := filepath.Join(dir, "out.log")
+
+	ctx, cancel := context.WithCancel(context.Background())
+
+	l, err := NewWithOptions(ctx, "sh", nil, []string{"-c", "echo started; exec sleep 10"}, WithDetachedOutput(out, ""))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer l.Close()
+
+	pid, err := l.StartDetached()
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer syscall.Kill(pid, syscall.SIGKILL)
+
+	if l.IsStarted() {
+		t.Fatal("expected Launcher to remain unstarted")
+	}
+
+	sid, err := getsid(pid)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if sid != pid {
+		t.Fatalf("expected process to lead a new session, got sid %v\n", sid)
+	}
+
+	// Cancelling the context does not affect the detached process
+	cancel()
+	time.Sleep(100 * time.Millisecond)
+	if !processAlive(pid) {
+		t.Fatal("expected detached process to survive cancellation")
+	}
+
+	deadline := time.Now().Add(5 * time.Second)
+	for {
+		b, _ := os.ReadFile(out)
+		if strings.TrimSpace(string(b)) == "started" {
+			break
+		}
+		if time.Now().After(deadline) {
+			t.Fatalf("expected output in file, got %q\n", b)
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+}
+
+func getsid(pid int) (int, error) {
+	sid, _, errno := syscall.RawSyscall(syscall.SYS_GETSID, uintptr(pid), 0, 0)
+	if errno != 0 {
+		return 0, errno
+	}
+	return int(sid), nil
+}
+
+func TestStartDetachedPolicy(t *testing.T) {
+
+	out := filepath.Join(t.TempDir(), "out.log")
+
+	denyAtStart := PolicyFunc(func(req PolicyRequest) error {
+		if req.Stage == PolicyAtStart {
+			return errors.New("denied")
+		}
+		return nil
+	})
+
+	for _, lazy := range []bool{false, true} {
+		opts := []Option{WithPolicy(denyAtStart), WithDetachedOutput(out, "")}
+		if lazy {
+			opts = append(opts, WithLazyLookup())
+		}
+		l, err := NewWithOptions(context.Background(), "echo", nil, []string{"ran"}, opts...)
+		if err != nil {
+			t.Fatal(err)
+		}
+		defer l.Close()
+
+		var pe *PolicyError
+		if _, err := l.StartDetached(); !errors.As(err, &pe) {
+			t.Fatalf("expected PolicyError (lazy %v), got %v\n", lazy, err)
+		}
+	}
+
+	if _, err := os.Stat(out); !os.IsNotExist(err) {
+		t.Fatal("expected denied process not to run")
+	}
+}
+
+func TestStartDetachedUnsupported(t *testing.T) {
+
+	r, w, err := os.Pipe()
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer r.Close()
+	defer w.Close()
+
+	tests := map[string]struct {
+		opt      Option
+		expected error
+	}{
+		"extra file":   {WithExtraFile(w, "OUT"), errDetachedFiles},
+		"secret":       {WithSecretFD("TOKEN", []byte("secret")), errDetachedFiles},
+		"temp workdir": {WithTempWorkdir("work", false), errDetachedCleanup},
+		"stdin":        {WithStdinBytes([]byte("input")), errDetachedStdin},
+	}
+	for name, test := range tests {
+		out := filepath.Join(t.TempDir(), "out.log")
+
+		l, err := NewWithOptions(context.Background(), "sh", nil, []string{"-c", "echo started"},
+			test.opt, WithDetachedOutput(out, ""))
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		if _, err := l.StartDetached(); !errors.Is(err, test.expected) {
+			t.Fatalf("%s: expected %v, got %v\n", name, test.expected, err)
+		}
+		if _, err := os.Stat(out); !errors.Is(err, os.ErrNotExist) {
+			t.Fatalf("%s: expected the process not to start, got %v\n", name, err)
+		}
+		l.Close()
+	}
+
+	// The script file would be removed by Close while being read
+	l, err := NewScript(context.Background(), "sh", "echo started")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer l.Close()
+
+	if _, err := l.StartDetached(); !errors.Is(err, errDetachedCleanup) {
+		t.Fatalf("script: expected %v, got %v\n", errDetachedCleanup, err)
+	}
+}
+
+func TestDetachedAttr(t *testing.T) {
+
+	attr := detachedAttr(&syscall.SysProcAttr{Noctty: true})
+	if !attr.Setsid || !attr.Noctty {
+		t.Fatalf("expected existing attributes to be kept, got %+v\n", attr)
+	}
+}
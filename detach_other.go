@@ -0,0 +1,11 @@
+//go:build !unix && !windows
+
+package launcher
+
+import "syscall"
+
+// detachedAttr has no means of detaching the process on this platform,
+// so returns its attributes unchanged
+func detachedAttr(attr *syscall.SysProcAttr) *syscall.SysProcAttr {
+	return attr
+}
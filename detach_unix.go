@@ -0,0 +1,17 @@
+//go:build unix
+
+package launcher
+
+import "syscall"
+
+// detachedAttr adds to the attributes of the process that it starts in a
+// new session, without a controlling terminal, so it is not signalled
+// with the parent's
+func detachedAttr(attr *syscall.SysProcAttr) *syscall.SysProcAttr {
+	a := syscall.SysProcAttr{}
+	if attr != nil {
+		a = *attr
+	}
+	a.Setsid = true
+	return &a
+}
@@ -0,0 +1,20 @@
+//go:build windows
+
+package launcher
+
+import "syscall"
+
+// detachedProcess starts the process without a console
+const detachedProcess = 0x00000008
+
+// detachedAttr adds to the attributes of the process, such as the
+// command line of a shell, that it starts without a console, in a new
+// process group so it does not receive the parent's console events
+func detachedAttr(attr *syscall.SysProcAttr) *syscall.SysProcAttr {
+	a := syscall.SysProcAttr{}
+	if attr != nil {
+		a = *attr
+	}
+	a.CreationFlags |= syscall.CREATE_NEW_PROCESS_GROUP | detachedProcess
+	return &a
+}
@@ -0,0 +1,40 @@
+package launcher
+
+import (
+	"errors"
+	"io"
+
+	"golang.org/x/text/encoding"
+	"golang.org/x/text/transform"
+)
+
+var errMissingEncoding = errors.New("encoding must be provided")
+
+// WithOutputEncoding decodes the stdout and stderr of the process from
+// the encoding, such as charmap.CodePage437, charmap.Windows1252 or
+// japanese.ShiftJIS, so that every reader of the output sees UTF-8.
+// Bytes which are invalid in the encoding are replaced by U+FFFD
+func WithOutputEncoding(enc encoding.Encoding) Option {
+	return func(o *options) error {
+		if enc == nil {
+			return errMissingEncoding
+		}
+		o.encoding = enc
+		return nil
+	}
+}
+
+// decoder is an io.Writer which decodes output before passing it on
+type decoder struct {
+	*transform.Writer
+}
+
+// Flush decodes any incomplete character held at the end of the output
+func (d decoder) Flush() {
+	d.Close()
+}
+
+// decodeOutput is an outputFilter decoding the output to UTF-8
+func (l *Launcher) decodeOutput(w io.Writer) io.Writer {
+	return decoder{transform.NewWriter(w, l.opts.encoding.NewDecoder())}
+}
@@ -0,0 +1,50 @@
+package launcher
+
+import (
+	"bytes"
+	"context"
+	"testing"
+
+	"golang.org/x/text/encoding/charmap"
+	"golang.org/x/text/encoding/japanese"
+)
+
+func TestOutputEncoding(t *testing.T) {
+
+	tests := []struct {
+		name     string
+		opt      Option
+		script   string
+		expected string
+	}{
+		{"cp1252", WithOutputEncoding(charmap.Windows1252), `printf 'caf\351 \200\n'`, "café €\n"},
+		{"cp437", WithOutputEncoding(charmap.CodePage437), `printf '\311\315\273\n'`, "╔═╗\n"},
+		// The character is split across writes
+		{"shiftjis", WithOutputEncoding(japanese.ShiftJIS), `printf '\223'; sleep 0.1; printf '\372\226\173\n'`, "日本\n"},
+	}
+
+	for _, test := range tests {
+		l, err := NewWithOptions(context.Background(), "sh", nil, []string{"-c", test.script}, test.opt)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		var stdout bytes.Buffer
+		if err := l.Start(); err != nil {
+			t.Fatal(err)
+		}
+		l.copyOutput(&stdout, nil)
+		l.Close()
+
+		if stdout.String() != test.expected {
+			t.Fatalf("%v: expected %q, got %q\n", test.name, test.expected, stdout.String())
+		}
+	}
+}
+
+func TestOutputEncodingMissing(t *testing.T) {
+
+	if _, err := NewWithOptions(context.Background(), "echo", nil, nil, WithOutputEncoding(nil)); err != errMissingEncoding {
+		t.Fatalf("expected errMissingEncoding, got %v\n", err)
+	}
+}
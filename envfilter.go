@@ -0,0 +1,89 @@
+package launcher
+
+import (
+	"os"
+	"path"
+	"strings"
+)
+
+// WithInheritedEnv passes the environment of the parent to the process,
+// followed by the environment supplied to create the Launcher
+func WithInheritedEnv() Option {
+	return func(o *options) error {
+		o.inheritEnv = true
+		return nil
+	}
+}
+
+// WithEnvAllowlist passes the process only those variables inherited from
+// the parent whose names match one of the patterns, as used by path.Match
+// such as "LC_*".  It implies WithInheritedEnv, and does not restrict the
+// environment supplied to create the Launcher
+func WithEnvAllowlist(patterns ...string) Option {
+	return func(o *options) error {
+		if err := validatePatterns(patterns); err != nil {
+			return err
+		}
+		o.inheritEnv = true
+		o.envAllow = append(o.envAllow, patterns...)
+		return nil
+	}
+}
+
+// WithEnvDenylist withholds from the process those variables inherited
+// from the parent whose names match one of the patterns, as used by
+// path.Match such as "AWS_*", even if they are allowed by an allowlist.
+// It implies WithInheritedEnv, and does not restrict the environment
+// supplied to create the Launcher
+func WithEnvDenylist(patterns ...string) Option {
+	return func(o *options) error {
+		if err := validatePatterns(patterns); err != nil {
+			return err
+		}
+		o.inheritEnv = true
+		o.envDeny = append(o.envDeny, patterns...)
+		return nil
+	}
+}
+
+// validatePatterns checks that the patterns are well formed
+func validatePatterns(patterns []string) error {
+	for _, p := range patterns {
+		if _, err := path.Match(p, ""); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// matchesAny returns true if the name matches one of the patterns
+func matchesAny(name string, patterns []string) bool {
+	for _, p := range patterns {
+		if ok, _ := path.Match(p, name); ok {
+			return true
+		}
+	}
+	return false
+}
+
+// environment returns the environment of the process, combining any
+// permitted variables inherited from the parent with those supplied
+func (o *options) environment(env []string) []string {
+	if !o.inheritEnv {
+		return append([]string{}, env...)
+	}
+
+	environ := os.Environ()
+	result := make([]string, 0, len(environ)+len(env))
+	for _, kv := range environ {
+		name, _, _ := strings.Cut(kv, "=")
+		if len(o.envAllow) > 0 && !matchesAny(name, o.envAllow) {
+			continue
+		}
+		if matchesAny(name, o.envDeny) {
+			continue
+		}
+		result = append(result, kv)
+	}
+	return append(result, env...)
+}
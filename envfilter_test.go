@@ -0,0 +1,64 @@
+package launcher
+
+import (
+	"context"
+	"strings"
+	"testing"
+)
+
+func envOf(t *testing.T, opts ...Option) map[string]string {
+	l, err := NewWithOptions(context.Background(), "env", []string{"AWS_EXPLICIT=1"}, nil, opts...)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer l.Close()
+
+	env := map[string]string{}
+	for _, kv := range l.GetEnv() {
+		k, v, _ := strings.Cut(kv, "=")
+		env[k] = v
+	}
+	return env
+}
+
+func TestEnvFiltering(t *testing.T) {
+
+	t.Setenv("AWS_SECRET_ACCESS_KEY", "secret")
+	t.Setenv("SSH_AUTH_SOCK", "/tmp/agent")
+	t.Setenv("LC_TEST", "en")
+
+	env := envOf(t)
+	if len(env) != 1 || env["AWS_EXPLICIT"] != "1" {
+		t.Fatalf("expected only supplied environment, got %v\n", env)
+	}
+
+	env = envOf(t, WithInheritedEnv())
+	if env["SSH_AUTH_SOCK"] != "/tmp/agent" || env["AWS_EXPLICIT"] != "1" {
+		t.Fatalf("expected inherited environment, got %v\n", env)
+	}
+
+	env = envOf(t, WithEnvDenylist("AWS_*", "SSH_AUTH_SOCK"))
+	if _, ok := env["AWS_SECRET_ACCESS_KEY"]; ok {
+		t.Fatalf("expected AWS_* to be withheld, got %v\n", env)
+	}
+	if _, ok := env["SSH_AUTH_SOCK"]; ok || env["LC_TEST"] != "en" || env["AWS_EXPLICIT"] != "1" {
+		t.Fatalf("unexpected environment %v\n", env)
+	}
+
+	env = envOf(t, WithEnvAllowlist("LC_*", "SSH_*"), WithEnvDenylist("SSH_*"))
+	if env["LC_TEST"] != "en" || env["AWS_EXPLICIT"] != "1" {
+		t.Fatalf("expected allowed environment, got %v\n", env)
+	}
+	for k := range env {
+		if !strings.HasPrefix(k, "LC_") && k != "AWS_EXPLICIT" {
+			t.Fatalf("expected only allowed environment, got %v\n", env)
+		}
+	}
+}
+
+func TestEnvFilteringInvalidPattern(t *testing.T) {
+
+	if _, err := NewWithOptions(context.Background(), "env", nil, nil, WithEnvAllowlist("[")); err == nil {
+		t.Fatal("expected error for malformed pattern")
+	}
+}
@@ -0,0 +1,114 @@
+package launcher
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"os/exec"
+	"sync"
+)
+
+// ExitError is returned by Wait and Run when the process exits
+// unsuccessfully, recording its exit code along with the end of its
+// stderr, if retained by WithStderrTail.  It wraps the *exec.ExitError,
+// and the cause of the process being terminated, if any
+type ExitError struct {
+	// ExitCode is -1 if the process was terminated by a signal
+	ExitCode int
+	// Stderr holds the last bytes written to stderr
+	Stderr []byte
+	// Cause is the reason the process was terminated, as returned
+	// by Cause, or nil if it exited of its own accord
+	Cause error
+	Err   error
+}
+
+func (e *ExitError) Error() string {
+	msg := e.Err.Error()
+	if e.Cause != nil {
+		msg = fmt.Sprintf("%s (terminated: %v)", msg, e.Cause)
+	}
+	if tail := bytes.TrimSpace(e.Stderr); len(tail) > 0 {
+		msg = fmt.Sprintf("%s: %s", msg, tail)
+	}
+	return msg
+}
+
+func (e *ExitError) Unwrap() []error {
+	if e.Cause == nil {
+		return []error{e.Err}
+	}
+	return []error{e.Err, e.Cause}
+}
+
+// WithStderrTail retains the last n bytes of the stderr of the process,
+// which are included in the *ExitError returned if it fails, regardless
+// of whether stderr is otherwise read
+func WithStderrTail(n int) Option {
+	return func(o *options) error {
+		if n <= 0 {
+			return errInvalidSize
+		}
+		o.stderrTail = n
+		return nil
+	}
+}
+
+// prepareStderrTail taps stderr into a tail, if required
+func (l *Launcher) prepareStderrTail() {
+	if l.opts.stderrTail == 0 {
+		return
+	}
+	l.stderrTail = &tailBuffer{limit: l.opts.stderrTail}
+	l.tapStdErr(l.stderrTail)
+}
+
+// exitError wraps the error of a process which exited unsuccessfully,
+// including the cause of its termination
+func (l *Launcher) exitError(err error) error {
+	cause := l.Cause()
+
+	var exitErr *exec.ExitError
+	if !errors.As(err, &exitErr) {
+		// A process which exits successfully once cancelled
+		// reports the error of the context
+		if err != nil && cause != nil && !errors.Is(err, cause) {
+			err = fmt.Errorf("%w: %w", err, cause)
+		}
+		return err
+	}
+
+	e := &ExitError{ExitCode: exitErr.ExitCode(), Cause: cause, Err: err}
+	if l.stderrTail != nil {
+		// The end of stderr may still be in the pipe
+		l.drainPumps()
+		e.Stderr = l.stderrTail.Bytes()
+	}
+	return e
+}
+
+// tailBuffer retains the last bytes written to it, up to the limit
+type tailBuffer struct {
+	mu    sync.Mutex
+	limit int
+	buf   []byte
+}
+
+func (t *tailBuffer) Write(b []byte) (int, error) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	t.buf = append(t.buf, b...)
+	if excess := len(t.buf) - t.limit; excess > 0 {
+		t.buf = append(t.buf[:0], t.buf[excess:]...)
+	}
+	return len(b), nil
+}
+
+// Bytes returns a copy of the retained bytes
+func (t *tailBuffer) Bytes() []byte {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	return append([]byte{}, t.buf...)
+}
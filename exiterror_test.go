@@ -0,0 +1,62 @@
+package launcher
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"os/exec"
+	"strings"
+	"testing"
+)
+
+func TestExitErrorStderrTail(t *testing.T) {
+
+	script := "printf 'ignored%.0s' $(seq 1 100) >&2; echo; echo 'fatal: bad config' >&2; exit 4"
+
+	l, err := NewWithOptions(context.Background(), "sh", nil, []string{"-c", script}, WithStderrTail(20))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer l.Close()
+
+	err = l.Run()
+
+	var exitErr *ExitError
+	if !errors.As(err, &exitErr) {
+		t.Fatalf("expected an ExitError, got %v\n", err)
+	}
+	if exitErr.ExitCode != 4 {
+		t.Fatalf("expected exit code 4, got %v\n", exitErr.ExitCode)
+	}
+	if !bytes.HasSuffix(exitErr.Stderr, []byte("fatal: bad config\n")) || len(exitErr.Stderr) != 20 {
+		t.Fatalf("unexpected stderr tail %q\n", exitErr.Stderr)
+	}
+	if !strings.HasSuffix(err.Error(), "fatal: bad config") {
+		t.Fatalf("expected stderr in the message, got %q\n", err.Error())
+	}
+
+	// The error from exec remains available
+	var execErr *exec.ExitError
+	if !errors.As(err, &execErr) {
+		t.Fatal("expected an exec.ExitError\n")
+	}
+}
+
+func TestExitErrorWithoutTail(t *testing.T) {
+
+	l, err := New(context.Background(), "sh", nil, "-c", "echo oops >&2; exit 2")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer l.Close()
+
+	err = l.Run()
+
+	var exitErr *ExitError
+	if !errors.As(err, &exitErr) || exitErr.ExitCode != 2 || exitErr.Stderr != nil {
+		t.Fatalf("unexpected error %#v\n", err)
+	}
+	if exitErr.Error() != "exit status 2" {
+		t.Fatalf("unexpected message %q\n", exitErr.Error())
+	}
+}
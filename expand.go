@@ -0,0 +1,67 @@
+package launcher
+
+import (
+	"regexp"
+	"strings"
+)
+
+// expansionRef matches the ${VAR} references replaced by WithExpansion
+var expansionRef = regexp.MustCompile(`\$\{([A-Za-z_][A-Za-z0-9_]*)\}`)
+
+// WithExpansion replaces ${VAR} references in the arguments and the
+// values of environment variables when the process is started, with the
+// value returned by the mapping for VAR.  If mapping is nil, references
+// are replaced from the environment of the process itself.  Other uses
+// of $, such as $VAR, are left unchanged
+func WithExpansion(mapping func(name string) string) Option {
+	return func(o *options) error {
+		o.expand = true
+		o.expansion = mapping
+		return nil
+	}
+}
+
+// expandReferences replaces the references in the arguments and
+// environment, if required
+func (l *Launcher) expandReferences() {
+	if l.opts.expand {
+		args, env := l.expanded()
+
+		l.mu.Lock()
+		defer l.mu.Unlock()
+		l.cmd.Args, l.cmd.Env = args, env
+		l.view = nil
+	}
+}
+
+// expanded returns the arguments, including the first, and the
+// environment with the references replaced
+func (l *Launcher) expanded() (args, env []string) {
+	mapping := l.opts.expansion
+	if mapping == nil {
+		values := map[string]string{}
+		for _, kv := range withoutShimEnv(l.cmd.Env) {
+			k, v, _ := strings.Cut(kv, "=")
+			values[k] = v
+		}
+		mapping = func(name string) string { return values[name] }
+	}
+
+	expand := func(s string) string {
+		return expansionRef.ReplaceAllStringFunc(s, func(ref string) string {
+			return mapping(ref[2 : len(ref)-1])
+		})
+	}
+
+	args = l.copyStringArray(l.cmd.Args)
+	for i := 1; i < len(args); i++ {
+		args[i] = expand(args[i])
+	}
+	env = l.copyStringArray(l.cmd.Env)
+	for i, kv := range env {
+		if k, v, ok := strings.Cut(kv, "="); ok && k != shimEnv {
+			env[i] = k + "=" + expand(v)
+		}
+	}
+	return args, env
+}
@@ -0,0 +1,59 @@
+package launcher
+
+import (
+	"context"
+	"io"
+	"testing"
+)
+
+func TestExpansionFromEnvironment(t *testing.T) {
+
+	env := []string{"NAME=world", "GREETING=hello ${NAME}"}
+
+	l, err := NewWithOptions(context.Background(), "sh", env, []string{"-c", "echo \"$GREETING\" $0 $1", "${NAME}", "$NAME ${MISSING}x"}, WithExpansion(nil))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer l.Close()
+
+	if l.GetArgs()[2] != "${NAME}" {
+		t.Fatalf("expected expansion to be deferred until Start, got %v\n", l.GetArgs())
+	}
+
+	if err := l.Run(); err != nil {
+		t.Fatal(err)
+	}
+	b, err := io.ReadAll(l.StdOutReader())
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if string(b) != "hello world world $NAME x\n" {
+		t.Fatalf("unexpected output %q\n", b)
+	}
+}
+
+func TestExpansionMapping(t *testing.T) {
+
+	mapping := func(name string) string {
+		return "<" + name + ">"
+	}
+
+	l, err := NewWithOptions(context.Background(), "echo", nil, []string{"${A}-${B_1}", "${1}"}, WithExpansion(mapping))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer l.Close()
+
+	if err := l.Run(); err != nil {
+		t.Fatal(err)
+	}
+	b, err := io.ReadAll(l.StdOutReader())
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if string(b) != "<A>-<B_1> ${1}\n" {
+		t.Fatalf("unexpected output %q\n", b)
+	}
+}
@@ -0,0 +1,39 @@
+package launcher
+
+import (
+	"fmt"
+	"os"
+)
+
+// extraFile is a file of the parent passed to the process
+type extraFile struct {
+	name string
+	f    *os.File
+}
+
+// WithExtraFile passes the open file, pipe or socket to the process as an
+// inherited file descriptor, whose number is set in the environment
+// variable NAME_FD.  The file remains owned by the caller, who may close
+// it once the process has started.  Extra descriptors are not supported
+// on Windows
+func WithExtraFile(f *os.File, name string) Option {
+	return func(o *options) error {
+		if f == nil {
+			return errMissingFile
+		}
+		if name == "" {
+			return errMissingName
+		}
+		o.extraFiles = append(o.extraFiles, extraFile{name: name, f: f})
+		return nil
+	}
+}
+
+// passExtraFiles passes the extra files to the process
+func (l *Launcher) passExtraFiles() {
+	for _, e := range l.opts.extraFiles {
+		l.cmd.ExtraFiles = append(l.cmd.ExtraFiles, e.f)
+		fd := 2 + len(l.cmd.ExtraFiles)
+		l.cmd.Env = append(l.cmd.Env, fmt.Sprintf("%s_FD=%d", e.name, fd))
+	}
+}
@@ -0,0 +1,64 @@
+package launcher
+
+import (
+	"context"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestWithExtraFile(t *testing.T) {
+
+	path := filepath.Join(t.TempDir(), "config")
+	if err := os.WriteFile(path, []byte("setting=1"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	config, err := os.Open(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer config.Close()
+
+	pr, pw, err := os.Pipe()
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer pr.Close()
+
+	l, err := NewWithOptions(context.Background(), "sh", nil,
+		[]string{"-c", `cat <&$CONFIG_FD; echo "$CONFIG_FD $RESULT_FD"; echo done >&$RESULT_FD`},
+		WithSecretFD("SECRET", nil), WithExtraFile(config, "CONFIG"), WithExtraFile(pw, "RESULT"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer l.Close()
+
+	var out strings.Builder
+	if err := l.Start(); err != nil {
+		t.Fatal(err)
+	}
+	// The parent's copy of the pipe must be closed for the read to end
+	pw.Close()
+	l.copyOutput(&out, nil)
+	if err := l.Wait(); err != nil {
+		t.Fatal(err)
+	}
+
+	// The secret pipe is passed first, as descriptor 3
+	if out.String() != "setting=14 5\n" {
+		t.Fatalf("unexpected output %q\n", out.String())
+	}
+	result, err := io.ReadAll(pr)
+	if err != nil || string(result) != "done\n" {
+		t.Fatalf("unexpected result %q (%v)\n", result, err)
+	}
+
+	if _, err := NewWithOptions(context.Background(), "true", nil, nil, WithExtraFile(nil, "X")); err == nil {
+		t.Fatal("expected error for nil file")
+	}
+	if _, err := NewWithOptions(context.Background(), "true", nil, nil, WithExtraFile(config, "")); err == nil {
+		t.Fatal("expected error for missing name")
+	}
+}
@@ -1,3 +1,18 @@
 module github.com/gford1000-go/launcher
 
 go 1.21.1
+
+require (
+	github.com/coder/websocket v1.8.12
+	github.com/vmihailenco/msgpack/v5 v5.4.1
+	golang.org/x/sys v0.21.0
+	golang.org/x/text v0.16.0
+	google.golang.org/grpc v1.64.1
+	google.golang.org/protobuf v1.34.2
+)
+
+require (
+	github.com/vmihailenco/tagparser/v2 v2.0.0 // indirect
+	golang.org/x/net v0.26.0 // indirect
+	google.golang.org/genproto/googleapis/rpc v0.0.0-20240318140521-94a12d6c2237 // indirect
+)
@@ -0,0 +1,343 @@
+package launcher
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"sync"
+	"time"
+)
+
+var errGroupStarted = errors.New("group has already been started")
+var errUnknownDependency = errors.New("dependency is not a member of the group")
+var errDependencyCycle = errors.New("dependencies form a cycle")
+var errDependencyFailed = errors.New("dependency failed to start")
+
+// member is a process within a Group
+type member struct {
+	name    string
+	spec    Spec
+	deps    []string
+	l       *Launcher
+	err     error
+	ready   chan struct{}
+	drained chan struct{}
+	stopped chan struct{}
+	// replacing is set while the process is being restarted
+	// by RollingRestart, and closed once it is complete
+	replacing chan struct{}
+}
+
+// Group starts a set of named processes in dependency order, with each
+// process started only once all of its dependencies are ready, and
+// independent processes started in parallel.  Processes are stopped in
+// the reverse order, with each stopped only after all its dependents
+type Group struct {
+	// Output, if set, returns the writers receiving the stdout and stderr
+	// of the named member, whose output is otherwise discarded
+	Output func(name string) (stdout, stderr io.Writer)
+
+	ctx      context.Context
+	cancel   context.CancelFunc
+	mu       sync.Mutex
+	members  []*member
+	started  bool
+	stopping bool
+	stopOnce sync.Once
+	rollMu   sync.Mutex
+}
+
+// NewGroup creates a new Group, whose processes are terminated
+// when the context is cancelled
+func NewGroup(ctx context.Context) (*Group, error) {
+	if ctx == nil {
+		return nil, ErrMissingContext
+	}
+	myCtx, cancel := context.WithCancel(ctx)
+
+	return &Group{
+		ctx:    myCtx,
+		cancel: cancel,
+	}, nil
+}
+
+// Add adds a process to the Group, which is started after
+// all the named dependencies are ready
+func (g *Group) Add(name string, spec Spec, dependsOn ...string) error {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+
+	if g.started {
+		return errGroupStarted
+	}
+	if g.member(name) != nil {
+		return ErrDuplicateName
+	}
+
+	g.members = append(g.members, &member{
+		name:    name,
+		spec:    spec,
+		deps:    append([]string{}, dependsOn...),
+		ready:   make(chan struct{}),
+		drained: make(chan struct{}),
+		stopped: make(chan struct{}),
+	})
+	return nil
+}
+
+// Launcher returns the Launcher of the named member, which
+// is nil if the member has not been started
+func (g *Group) Launcher(name string) (*Launcher, error) {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+
+	m := g.member(name)
+	if m == nil {
+		return nil, ErrUnknownName
+	}
+	return m.l, nil
+}
+
+// Start validates the dependencies and starts the members of the Group,
+// returning once all are ready.  If any member fails to start, those
+// already started are stopped and the failures are returned
+func (g *Group) Start() error {
+	g.mu.Lock()
+	if g.started {
+		g.mu.Unlock()
+		return errGroupStarted
+	}
+	if err := g.validate(); err != nil {
+		g.mu.Unlock()
+		return err
+	}
+	g.started = true
+	g.mu.Unlock()
+
+	var wg sync.WaitGroup
+	for _, m := range g.members {
+		wg.Add(1)
+		go func(m *member) {
+			defer wg.Done()
+			g.startMember(m)
+		}(m)
+	}
+	wg.Wait()
+
+	var errs []error
+	for _, m := range g.members {
+		if m.err != nil && !errors.Is(m.err, errDependencyFailed) {
+			errs = append(errs, fmt.Errorf("%s: %w", m.name, m.err))
+		}
+	}
+	if len(errs) > 0 {
+		g.Stop(defaultStopGrace)
+		return errors.Join(errs...)
+	}
+	return nil
+}
+
+// Stop terminates the members of the Group in reverse dependency order,
+// allowing each the grace period to exit before it is killed
+func (g *Group) Stop(grace time.Duration) error {
+	g.mu.Lock()
+	started := g.started
+	g.stopping = true
+	g.mu.Unlock()
+
+	if !started {
+		g.cancel()
+		return nil
+	}
+
+	g.stopOnce.Do(func() {
+		dependents := map[string][]*member{}
+		for _, m := range g.members {
+			for _, d := range m.deps {
+				dependents[d] = append(dependents[d], m)
+			}
+		}
+
+		var wg sync.WaitGroup
+		for _, m := range g.members {
+			wg.Add(1)
+			go func(m *member) {
+				defer wg.Done()
+				g.stopMember(m, dependents[m.name], grace)
+			}(m)
+		}
+		wg.Wait()
+
+		g.cancel()
+	})
+	return nil
+}
+
+// Wait blocks until all started members have exited, returning
+// the errors of those that were unsuccessful.  It must only be
+// called after Start has returned
+func (g *Group) Wait() error {
+	var errs []error
+	for _, m := range g.members {
+		if err := g.waitMember(m); err != nil {
+			errs = append(errs, fmt.Errorf("%s: %w", m.name, err))
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// waitMember blocks until the process of the member has exited,
+// following any replacement made by RollingRestart
+func (g *Group) waitMember(m *member) error {
+	for {
+		g.mu.Lock()
+		l, drained := m.l, m.drained
+		g.mu.Unlock()
+
+		if l == nil {
+			return nil
+		}
+		<-drained
+		err := l.Wait()
+
+		g.mu.Lock()
+		replacing, current := m.replacing, m.l
+		g.mu.Unlock()
+
+		if replacing != nil {
+			<-replacing
+			continue
+		}
+		if current != l {
+			continue
+		}
+		return err
+	}
+}
+
+// startMember waits for the dependencies of the member to be ready
+// before starting it, skipping it if any dependency failed
+func (g *Group) startMember(m *member) {
+	for _, d := range m.deps {
+		dep := g.member(d)
+		<-dep.ready
+		if dep.err != nil {
+			m.err = errDependencyFailed
+			close(m.ready)
+			return
+		}
+	}
+
+	l, err := g.launch(m)
+	if err != nil {
+		m.err = err
+		close(m.ready)
+		return
+	}
+
+	g.mu.Lock()
+	m.l = l
+	g.mu.Unlock()
+	close(m.ready)
+
+	g.drain(m, l, m.drained)
+}
+
+// launch creates and starts a process for the member,
+// returning once it is ready
+func (g *Group) launch(m *member) (*Launcher, error) {
+	l, err := m.spec.New(g.ctx)
+	if err != nil {
+		return nil, err
+	}
+	if err := l.Start(); err != nil {
+		l.Close()
+		return nil, err
+	}
+	return l, nil
+}
+
+// drain copies the output of the process of the member,
+// closing drained once it is complete
+func (g *Group) drain(m *member, l *Launcher, drained chan struct{}) {
+	go func() {
+		defer close(drained)
+
+		var stdout, stderr io.Writer
+		if g.Output != nil {
+			stdout, stderr = g.Output(m.name)
+		}
+		l.copyOutput(stdout, stderr)
+	}()
+}
+
+// stopMember terminates the member once all its dependents are stopped
+func (g *Group) stopMember(m *member, dependents []*member, grace time.Duration) {
+	defer close(m.stopped)
+
+	for _, d := range dependents {
+		<-d.stopped
+	}
+
+	g.mu.Lock()
+	l, drained := m.l, m.drained
+	g.mu.Unlock()
+
+	if l != nil {
+		l.terminate(l.done, grace, ErrCancelled)
+		<-drained
+		l.Close()
+	}
+}
+
+// validate checks that all dependencies are members and that there
+// are no cycles, and must be called with the lock held
+func (g *Group) validate() error {
+	const (
+		unvisited = iota
+		visiting
+		visited
+	)
+	state := map[string]int{}
+
+	var visit func(m *member) error
+	visit = func(m *member) error {
+		switch state[m.name] {
+		case visiting:
+			return fmt.Errorf("%w: %s", errDependencyCycle, m.name)
+		case visited:
+			return nil
+		}
+
+		state[m.name] = visiting
+		for _, d := range m.deps {
+			dep := g.member(d)
+			if dep == nil {
+				return fmt.Errorf("%w: %s depends on %s", errUnknownDependency, m.name, d)
+			}
+			if err := visit(dep); err != nil {
+				return err
+			}
+		}
+		state[m.name] = visited
+		return nil
+	}
+
+	for _, m := range g.members {
+		if err := visit(m); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// member returns the named member, or nil if there is none
+func (g *Group) member(name string) *member {
+	for _, m := range g.members {
+		if m.name == name {
+			return m
+		}
+	}
+	return nil
+}
@@ -0,0 +1,133 @@
+package launcher
+
+import (
+	"context"
+	"errors"
+	"path/filepath"
+	"sync"
+	"testing"
+	"time"
+)
+
+func TestGroupWithNilCtx(t *testing.T) {
+
+	_, err := NewGroup(nil)
+	if err != ErrMissingContext {
+		t.Fatal(err)
+	}
+}
+
+func TestGroupDependencyOrder(t *testing.T) {
+
+	path := filepath.Join(t.TempDir(), "ready")
+
+	g, err := NewGroup(context.Background())
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// b and c fail immediately if a is not ready when they start
+	a := Spec{
+		File:    "sh",
+		Args:    []string{"-c", "sleep 0.2; touch " + path + "; exec sleep 10"},
+		Options: []Option{WithReadiness(FileProbe(path))},
+	}
+	dependent := Spec{
+		File:    "sh",
+		Args:    []string{"-c", "test -f " + path + " && exec sleep 10"},
+		Options: []Option{WithReadiness(ProbeFunc(func(ctx context.Context) error { return nil }))},
+	}
+
+	if err := g.Add("b", dependent, "a"); err != nil {
+		t.Fatal(err)
+	}
+	if err := g.Add("c", dependent, "a"); err != nil {
+		t.Fatal(err)
+	}
+	if err := g.Add("a", a); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := g.Start(); err != nil {
+		t.Fatal(err)
+	}
+
+	var mu sync.Mutex
+	exited := []string{}
+	var wg sync.WaitGroup
+	for _, name := range []string{"a", "b", "c"} {
+		l, err := g.Launcher(name)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if !l.IsRunning() {
+			t.Fatalf("%s is not running\n", name)
+		}
+
+		wg.Add(1)
+		go func(name string, l *Launcher) {
+			defer wg.Done()
+			l.Wait()
+			mu.Lock()
+			exited = append(exited, name)
+			mu.Unlock()
+		}(name, l)
+	}
+
+	if err := g.Stop(time.Second); err != nil {
+		t.Fatal(err)
+	}
+	wg.Wait()
+
+	if len(exited) != 3 || exited[2] != "a" {
+		t.Fatalf("expected a to be stopped last, got %v\n", exited)
+	}
+}
+
+func TestGroupStartFailure(t *testing.T) {
+
+	g, err := NewGroup(context.Background())
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	g.Add("a", Spec{File: "sleep", Args: []string{"10"}})
+	g.Add("b", Spec{File: "sh", Args: []string{"-c", "exit 1"}, Options: []Option{WithReadiness(FileProbe("/zzz/unknown"))}}, "a")
+	g.Add("c", Spec{File: "sleep", Args: []string{"10"}}, "b")
+
+	err = g.Start()
+	if !errors.Is(err, errExitedBeforeReady) {
+		t.Fatal(err)
+	}
+
+	l, _ := g.Launcher("a")
+	if l.IsRunning() {
+		t.Fatal("a should have been stopped")
+	}
+
+	if l, _ := g.Launcher("c"); l != nil {
+		t.Fatal("c should not have been started")
+	}
+}
+
+func TestGroupInvalidDependencies(t *testing.T) {
+
+	g, _ := NewGroup(context.Background())
+	g.Add("a", Spec{File: "true"}, "b")
+	g.Add("b", Spec{File: "true"}, "a")
+
+	if err := g.Start(); !errors.Is(err, errDependencyCycle) {
+		t.Fatal(err)
+	}
+
+	g, _ = NewGroup(context.Background())
+	g.Add("a", Spec{File: "true"}, "zzz")
+
+	if err := g.Start(); !errors.Is(err, errUnknownDependency) {
+		t.Fatal(err)
+	}
+
+	if err := g.Add("a", Spec{File: "true"}); err != ErrDuplicateName {
+		t.Fatal(err)
+	}
+}
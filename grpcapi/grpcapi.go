@@ -0,0 +1,187 @@
+// Package grpcapi provides a gRPC service for the remote management of
+// the processes of a launcher.Manager, as defined in launcherpb/launcher.proto.
+//
+// The service allows arbitrary commands to be launched, so the grpc.Server
+// it is registered with must authenticate and authorise its clients.
+package grpcapi
+
+//go:generate buf generate
+
+import (
+	"context"
+	"errors"
+	"io"
+	"syscall"
+
+	"github.com/gford1000-go/launcher"
+	"github.com/gford1000-go/launcher/grpcapi/launcherpb"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/types/known/durationpb"
+	"google.golang.org/protobuf/types/known/timestamppb"
+)
+
+// Server implements launcherpb.LauncherServiceServer for a Manager
+type Server struct {
+	launcherpb.UnimplementedLauncherServiceServer
+	m *launcher.Manager
+}
+
+// NewServer creates a new Server for the Manager
+func NewServer(m *launcher.Manager) *Server {
+	return &Server{m: m}
+}
+
+// Register creates a new Server for the Manager and registers it with s
+func Register(s grpc.ServiceRegistrar, m *launcher.Manager) {
+	launcherpb.RegisterLauncherServiceServer(s, NewServer(m))
+}
+
+// Launch registers and starts a named process
+func (s *Server) Launch(ctx context.Context, req *launcherpb.LaunchRequest) (*launcherpb.ProcessStatus, error) {
+	if req.GetName() == "" || req.GetFile() == "" {
+		return nil, status.Error(codes.InvalidArgument, "name and file must be provided")
+	}
+
+	spec := launcher.Spec{
+		File: req.GetFile(),
+		Env:  req.GetEnv(),
+		Args: req.GetArgs(),
+	}
+	if err := s.m.Launch(req.GetName(), spec); err != nil {
+		return nil, toStatus(err)
+	}
+	return s.inspect(req.GetName())
+}
+
+// Stream writes stdin to a named process, and sends its output line by
+// line, until the client cancels the call.  The output is that published
+// by the Manager, so lines are dropped if the client falls behind, with
+// the number dropped reported in the next response
+func (s *Server) Stream(stream launcherpb.LauncherService_StreamServer) error {
+	req, err := stream.Recv()
+	if err != nil {
+		return err
+	}
+
+	name := req.GetName()
+	if _, err := s.m.Inspect(name); err != nil {
+		return toStatus(err)
+	}
+
+	lines, cancel := s.m.Subscribe(name)
+	defer cancel()
+
+	stdin := make(chan error, 1)
+	go func() {
+		for {
+			if len(req.GetStdin()) > 0 {
+				if err := s.m.SendStdIn(name, req.GetStdin()); err != nil {
+					stdin <- toStatus(err)
+					return
+				}
+			}
+
+			req, err = stream.Recv()
+			if err != nil {
+				if err != io.EOF {
+					stdin <- err
+				}
+				return
+			}
+		}
+	}()
+
+	for {
+		select {
+		case <-stream.Context().Done():
+			return nil
+		case err := <-stdin:
+			return err
+		case line, ok := <-lines:
+			if !ok {
+				return nil
+			}
+			if err := stream.Send(toStreamResponse(line)); err != nil {
+				return err
+			}
+		}
+	}
+}
+
+// Signal sends a signal to a named process
+func (s *Server) Signal(ctx context.Context, req *launcherpb.SignalRequest) (*launcherpb.SignalResponse, error) {
+	if err := s.m.Signal(req.GetName(), syscall.Signal(req.GetSignal())); err != nil {
+		return nil, toStatus(err)
+	}
+	return &launcherpb.SignalResponse{}, nil
+}
+
+// Stop terminates a named process, allowing it the requested
+// grace period to exit before it is killed
+func (s *Server) Stop(ctx context.Context, req *launcherpb.StopRequest) (*launcherpb.ProcessStatus, error) {
+	if err := s.m.Stop(req.GetName(), req.GetGrace().AsDuration()); err != nil {
+		return nil, toStatus(err)
+	}
+	return s.inspect(req.GetName())
+}
+
+// List returns the status of all processes
+func (s *Server) List(ctx context.Context, req *launcherpb.ListRequest) (*launcherpb.ListResponse, error) {
+	resp := &launcherpb.ListResponse{}
+	for _, st := range s.m.List() {
+		resp.Processes = append(resp.Processes, toProcessStatus(st))
+	}
+	return resp, nil
+}
+
+func (s *Server) inspect(name string) (*launcherpb.ProcessStatus, error) {
+	st, err := s.m.Inspect(name)
+	if err != nil {
+		return nil, toStatus(err)
+	}
+	return toProcessStatus(st), nil
+}
+
+func toProcessStatus(s launcher.Status) *launcherpb.ProcessStatus {
+	ps := &launcherpb.ProcessStatus{
+		Name:         s.Name,
+		Pid:          int64(s.Pid),
+		Running:      s.Running,
+		Uptime:       durationpb.New(s.Uptime),
+		Restarts:     int64(s.Restarts),
+		LastExitCode: int32(s.LastExitCode),
+	}
+	if !s.StartedAt.IsZero() {
+		ps.StartedAt = timestamppb.New(s.StartedAt)
+	}
+	return ps
+}
+
+func toStreamResponse(line launcher.Line) *launcherpb.StreamResponse {
+	source := launcherpb.StreamResponse_SOURCE_STDOUT
+	if line.Stream == "stderr" {
+		source = launcherpb.StreamResponse_SOURCE_STDERR
+	}
+	return &launcherpb.StreamResponse{
+		Source:  source,
+		Data:    []byte(line.Text + "\n"),
+		Time:    timestamppb.New(line.Time),
+		Dropped: uint64(line.Dropped),
+	}
+}
+
+// toStatus maps Manager errors to gRPC status errors
+func toStatus(err error) error {
+	switch {
+	case errors.Is(err, launcher.ErrUnknownName):
+		return status.Error(codes.NotFound, err.Error())
+	case errors.Is(err, launcher.ErrDuplicateName):
+		return status.Error(codes.AlreadyExists, err.Error())
+	case errors.Is(err, launcher.ErrAlreadyRunning):
+		return status.Error(codes.FailedPrecondition, err.Error())
+	default:
+		return status.Error(codes.Unknown, err.Error())
+	}
+}
@@ -0,0 +1,122 @@
+package grpcapi
+
+import (
+	"context"
+	"net"
+	"testing"
+	"time"
+
+	"github.com/gford1000-go/launcher"
+	"github.com/gford1000-go/launcher/grpcapi/launcherpb"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/status"
+	"google.golang.org/grpc/test/bufconn"
+	"google.golang.org/protobuf/types/known/durationpb"
+)
+
+func newClient(t *testing.T) launcherpb.LauncherServiceClient {
+	m, err := launcher.NewManager(context.Background())
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	lis := bufconn.Listen(1024 * 1024)
+	s := grpc.NewServer()
+	Register(s, m)
+	go s.Serve(lis)
+
+	conn, err := grpc.NewClient("passthrough:///bufnet",
+		grpc.WithContextDialer(func(ctx context.Context, _ string) (net.Conn, error) {
+			return lis.DialContext(ctx)
+		}),
+		grpc.WithTransportCredentials(insecure.NewCredentials()))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	t.Cleanup(func() {
+		conn.Close()
+		s.Stop()
+		m.Close()
+	})
+	return launcherpb.NewLauncherServiceClient(conn)
+}
+
+func TestLaunchListStop(t *testing.T) {
+
+	c := newClient(t)
+	ctx := context.Background()
+
+	ps, err := c.Launch(ctx, &launcherpb.LaunchRequest{Name: "sleeper", File: "sleep", Args: []string{"10"}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !ps.GetRunning() || ps.GetPid() == 0 {
+		t.Fatalf("unexpected status: %v\n", ps)
+	}
+
+	_, err = c.Launch(ctx, &launcherpb.LaunchRequest{Name: "sleeper", File: "sleep", Args: []string{"10"}})
+	if status.Code(err) != codes.AlreadyExists {
+		t.Fatal(err)
+	}
+
+	l, err := c.List(ctx, &launcherpb.ListRequest{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(l.GetProcesses()) != 1 {
+		t.Fatalf("unexpected list: %v\n", l)
+	}
+
+	ps, err = c.Stop(ctx, &launcherpb.StopRequest{Name: "sleeper", Grace: durationpb.New(time.Second)})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if ps.GetRunning() {
+		t.Fatal("still running")
+	}
+
+	_, err = c.Signal(ctx, &launcherpb.SignalRequest{Name: "zzz", Signal: 15})
+	if status.Code(err) != codes.NotFound {
+		t.Fatal(err)
+	}
+}
+
+func TestStream(t *testing.T) {
+
+	c := newClient(t)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	if _, err := c.Launch(ctx, &launcherpb.LaunchRequest{Name: "cat", File: "cat"}); err != nil {
+		t.Fatal(err)
+	}
+
+	stream, err := c.Stream(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if err := stream.Send(&launcherpb.StreamRequest{Name: "cat", Stdin: []byte("foo\n")}); err != nil {
+		t.Fatal(err)
+	}
+
+	resp, err := stream.Recv()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if string(resp.GetData()) != "foo\n" || resp.GetSource() != launcherpb.StreamResponse_SOURCE_STDOUT {
+		t.Fatalf("unexpected response: %v\n", resp)
+	}
+}
+
+func TestStreamResponseDropped(t *testing.T) {
+
+	resp := toStreamResponse(launcher.Line{Stream: "stderr", Text: "foo", Dropped: 3})
+	if resp.GetDropped() != 3 || resp.GetSource() != launcherpb.StreamResponse_SOURCE_STDERR || string(resp.GetData()) != "foo\n" {
+		t.Fatalf("unexpected response: %v\n", resp)
+	}
+}
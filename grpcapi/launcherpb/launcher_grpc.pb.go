@@ -0,0 +1,313 @@
+// Code generated by protoc-gen-go-grpc. DO NOT EDIT.
+// versions:
+// - protoc-gen-go-grpc v1.4.0
+// - protoc             (unknown)
+// source: launcherpb/launcher.proto
+
+package launcherpb
+
+import (
+	context "context"
+	grpc "google.golang.org/grpc"
+	codes "google.golang.org/grpc/codes"
+	status "google.golang.org/grpc/status"
+)
+
+// This is a compile-time assertion to ensure that this generated file
+// is compatible with the grpc package it is being compiled against.
+// Requires gRPC-Go v1.62.0 or later.
+const _ = grpc.SupportPackageIsVersion8
+
+const (
+	LauncherService_Launch_FullMethodName = "/launcher.v1.LauncherService/Launch"
+	LauncherService_Stream_FullMethodName = "/launcher.v1.LauncherService/Stream"
+	LauncherService_Signal_FullMethodName = "/launcher.v1.LauncherService/Signal"
+	LauncherService_Stop_FullMethodName   = "/launcher.v1.LauncherService/Stop"
+	LauncherService_List_FullMethodName   = "/launcher.v1.LauncherService/List"
+)
+
+// LauncherServiceClient is the client API for LauncherService service.
+//
+// For semantics around ctx use and closing/ending streaming RPCs, please refer to https://pkg.go.dev/google.golang.org/grpc/?tab=doc#ClientConn.NewStream.
+//
+// LauncherService manages named child processes
+type LauncherServiceClient interface {
+	// Launch registers and starts a named process
+	Launch(ctx context.Context, in *LaunchRequest, opts ...grpc.CallOption) (*ProcessStatus, error)
+	// Stream writes stdin to a named process, and receives its output.
+	// The output is lossy: lines are dropped while the client is not
+	// keeping up, as reported by StreamResponse.dropped
+	Stream(ctx context.Context, opts ...grpc.CallOption) (LauncherService_StreamClient, error)
+	// Signal sends a signal to a named process
+	Signal(ctx context.Context, in *SignalRequest, opts ...grpc.CallOption) (*SignalResponse, error)
+	// Stop terminates a named process
+	Stop(ctx context.Context, in *StopRequest, opts ...grpc.CallOption) (*ProcessStatus, error)
+	// List returns the status of all processes
+	List(ctx context.Context, in *ListRequest, opts ...grpc.CallOption) (*ListResponse, error)
+}
+
+type launcherServiceClient struct {
+	cc grpc.ClientConnInterface
+}
+
+func NewLauncherServiceClient(cc grpc.ClientConnInterface) LauncherServiceClient {
+	return &launcherServiceClient{cc}
+}
+
+func (c *launcherServiceClient) Launch(ctx context.Context, in *LaunchRequest, opts ...grpc.CallOption) (*ProcessStatus, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(ProcessStatus)
+	err := c.cc.Invoke(ctx, LauncherService_Launch_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *launcherServiceClient) Stream(ctx context.Context, opts ...grpc.CallOption) (LauncherService_StreamClient, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	stream, err := c.cc.NewStream(ctx, &LauncherService_ServiceDesc.Streams[0], LauncherService_Stream_FullMethodName, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	x := &launcherServiceStreamClient{ClientStream: stream}
+	return x, nil
+}
+
+type LauncherService_StreamClient interface {
+	Send(*StreamRequest) error
+	Recv() (*StreamResponse, error)
+	grpc.ClientStream
+}
+
+type launcherServiceStreamClient struct {
+	grpc.ClientStream
+}
+
+func (x *launcherServiceStreamClient) Send(m *StreamRequest) error {
+	return x.ClientStream.SendMsg(m)
+}
+
+func (x *launcherServiceStreamClient) Recv() (*StreamResponse, error) {
+	m := new(StreamResponse)
+	if err := x.ClientStream.RecvMsg(m); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
+func (c *launcherServiceClient) Signal(ctx context.Context, in *SignalRequest, opts ...grpc.CallOption) (*SignalResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(SignalResponse)
+	err := c.cc.Invoke(ctx, LauncherService_Signal_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *launcherServiceClient) Stop(ctx context.Context, in *StopRequest, opts ...grpc.CallOption) (*ProcessStatus, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(ProcessStatus)
+	err := c.cc.Invoke(ctx, LauncherService_Stop_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *launcherServiceClient) List(ctx context.Context, in *ListRequest, opts ...grpc.CallOption) (*ListResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(ListResponse)
+	err := c.cc.Invoke(ctx, LauncherService_List_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+// LauncherServiceServer is the server API for LauncherService service.
+// All implementations must embed UnimplementedLauncherServiceServer
+// for forward compatibility
+//
+// LauncherService manages named child processes
+type LauncherServiceServer interface {
+	// Launch registers and starts a named process
+	Launch(context.Context, *LaunchRequest) (*ProcessStatus, error)
+	// Stream writes stdin to a named process, and receives its output.
+	// The output is lossy: lines are dropped while the client is not
+	// keeping up, as reported by StreamResponse.dropped
+	Stream(LauncherService_StreamServer) error
+	// Signal sends a signal to a named process
+	Signal(context.Context, *SignalRequest) (*SignalResponse, error)
+	// Stop terminates a named process
+	Stop(context.Context, *StopRequest) (*ProcessStatus, error)
+	// List returns the status of all processes
+	List(context.Context, *ListRequest) (*ListResponse, error)
+	mustEmbedUnimplementedLauncherServiceServer()
+}
+
+// UnimplementedLauncherServiceServer must be embedded to have forward compatible implementations.
+type UnimplementedLauncherServiceServer struct {
+}
+
+func (UnimplementedLauncherServiceServer) Launch(context.Context, *LaunchRequest) (*ProcessStatus, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method Launch not implemented")
+}
+func (UnimplementedLauncherServiceServer) Stream(LauncherService_StreamServer) error {
+	return status.Errorf(codes.Unimplemented, "method Stream not implemented")
+}
+func (UnimplementedLauncherServiceServer) Signal(context.Context, *SignalRequest) (*SignalResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method Signal not implemented")
+}
+func (UnimplementedLauncherServiceServer) Stop(context.Context, *StopRequest) (*ProcessStatus, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method Stop not implemented")
+}
+func (UnimplementedLauncherServiceServer) List(context.Context, *ListRequest) (*ListResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method List not implemented")
+}
+func (UnimplementedLauncherServiceServer) mustEmbedUnimplementedLauncherServiceServer() {}
+
+// UnsafeLauncherServiceServer may be embedded to opt out of forward compatibility for this service.
+// Use of this interface is not recommended, as added methods to LauncherServiceServer will
+// result in compilation errors.
+type UnsafeLauncherServiceServer interface {
+	mustEmbedUnimplementedLauncherServiceServer()
+}
+
+func RegisterLauncherServiceServer(s grpc.ServiceRegistrar, srv LauncherServiceServer) {
+	s.RegisterService(&LauncherService_ServiceDesc, srv)
+}
+
+func _LauncherService_Launch_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(LaunchRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(LauncherServiceServer).Launch(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: LauncherService_Launch_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(LauncherServiceServer).Launch(ctx, req.(*LaunchRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _LauncherService_Stream_Handler(srv interface{}, stream grpc.ServerStream) error {
+	return srv.(LauncherServiceServer).Stream(&launcherServiceStreamServer{ServerStream: stream})
+}
+
+type LauncherService_StreamServer interface {
+	Send(*StreamResponse) error
+	Recv() (*StreamRequest, error)
+	grpc.ServerStream
+}
+
+type launcherServiceStreamServer struct {
+	grpc.ServerStream
+}
+
+func (x *launcherServiceStreamServer) Send(m *StreamResponse) error {
+	return x.ServerStream.SendMsg(m)
+}
+
+func (x *launcherServiceStreamServer) Recv() (*StreamRequest, error) {
+	m := new(StreamRequest)
+	if err := x.ServerStream.RecvMsg(m); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
+func _LauncherService_Signal_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(SignalRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(LauncherServiceServer).Signal(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: LauncherService_Signal_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(LauncherServiceServer).Signal(ctx, req.(*SignalRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _LauncherService_Stop_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(StopRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(LauncherServiceServer).Stop(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: LauncherService_Stop_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(LauncherServiceServer).Stop(ctx, req.(*StopRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _LauncherService_List_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ListRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(LauncherServiceServer).List(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: LauncherService_List_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(LauncherServiceServer).List(ctx, req.(*ListRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+// LauncherService_ServiceDesc is the grpc.ServiceDesc for LauncherService service.
+// It's only intended for direct use with grpc.RegisterService,
+// and not to be introspected or modified (even as a copy)
+var LauncherService_ServiceDesc = grpc.ServiceDesc{
+	ServiceName: "launcher.v1.LauncherService",
+	HandlerType: (*LauncherServiceServer)(nil),
+	Methods: []grpc.MethodDesc{
+		{
+			MethodName: "Launch",
+			Handler:    _LauncherService_Launch_Handler,
+		},
+		{
+			MethodName: "Signal",
+			Handler:    _LauncherService_Signal_Handler,
+		},
+		{
+			MethodName: "Stop",
+			Handler:    _LauncherService_Stop_Handler,
+		},
+		{
+			MethodName: "List",
+			Handler:    _LauncherService_List_Handler,
+		},
+	},
+	Streams: []grpc.StreamDesc{
+		{
+			StreamName:    "Stream",
+			Handler:       _LauncherService_Stream_Handler,
+			ServerStreams: true,
+			ClientStreams: true,
+		},
+	},
+	Metadata: "launcherpb/launcher.proto",
+}
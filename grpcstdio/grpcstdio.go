@@ -0,0 +1,85 @@
+// Package grpcstdio carries gRPC over the stdin and stdout of a plugin
+// process, so that a launcher.Launcher may host strongly typed plugin
+// RPC without opening a network port.
+//
+// The parent dials the plugin with Dial, and the plugin serves its
+// services with Serve:
+//
+//	// parent
+//	cc, err := grpcstdio.Dial(l)
+//
+//	// plugin
+//	s := grpc.NewServer()
+//	pb.RegisterPluginServer(s, &plugin{})
+//	grpcstdio.Serve(s)
+//
+// As stdout carries the connection, the plugin must write any
+// diagnostics to stderr.
+package grpcstdio
+
+import (
+	"context"
+	"errors"
+	"net"
+	"sync"
+
+	"github.com/gford1000-go/launcher"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/credentials/insecure"
+)
+
+// ErrConnectionUsed is returned when gRPC attempts to reconnect once
+// the connection over stdio has been lost, as it cannot be reopened
+var ErrConnectionUsed = errors.New("stdio connection has already been used")
+
+// target is the gRPC target of a connection over stdio
+const target = "passthrough:///stdio"
+
+// Dial returns a grpc.ClientConn to the plugin process of the Launcher,
+// starting the process if it has not been.  The connection is insecure,
+// as it is private to the two processes, and further DialOptions may be
+// given
+func Dial(l *launcher.Launcher, opts ...grpc.DialOption) (*grpc.ClientConn, error) {
+	if err := l.Start(); err != nil && !errors.Is(err, launcher.ErrAlreadyStarted) {
+		return nil, err
+	}
+	return DialConn(l.Conn(), opts...)
+}
+
+// DialConn returns a grpc.ClientConn over the connection, which
+// is used for the first connection attempt only
+func DialConn(c net.Conn, opts ...grpc.DialOption) (*grpc.ClientConn, error) {
+	var once sync.Once
+	dialer := func(ctx context.Context, addr string) (net.Conn, error) {
+		conn := net.Conn(nil)
+		once.Do(func() { conn = c })
+		if conn == nil {
+			return nil, ErrConnectionUsed
+		}
+		return conn, nil
+	}
+
+	opts = append([]grpc.DialOption{
+		grpc.WithContextDialer(dialer),
+		grpc.WithTransportCredentials(insecure.NewCredentials()),
+	}, opts...)
+	return grpc.NewClient(target, opts...)
+}
+
+// Serve serves the gRPC server over the stdin and stdout of the
+// current process, returning once the server is stopped or stdin
+// is closed
+func Serve(s *grpc.Server) error {
+	return ServeConn(s, launcher.StdioConn())
+}
+
+// ServeConn serves the gRPC server over the connection, returning
+// once the server is stopped or the connection has ended, as when
+// the parent closes the stdin of the plugin
+func ServeConn(s *grpc.Server, c net.Conn) error {
+	err := s.Serve(launcher.ConnListener(c))
+	if errors.Is(err, grpc.ErrServerStopped) || errors.Is(err, net.ErrClosed) {
+		return nil
+	}
+	return err
+}
@@ -0,0 +1,78 @@
+package grpcstdio
+
+import (
+	"context"
+	"net"
+	"testing"
+	"time"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/health"
+	"google.golang.org/grpc/health/grpc_health_v1"
+)
+
+func TestDialConn(t *testing.T) {
+
+	client, server := net.Pipe()
+
+	s := grpc.NewServer()
+	grpc_health_v1.RegisterHealthServer(s, health.NewServer())
+	done := make(chan error, 1)
+	go func() { done <- ServeConn(s, server) }()
+
+	cc, err := DialConn(client)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer cc.Close()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	resp, err := grpc_health_v1.NewHealthClient(cc).Check(ctx, &grpc_health_v1.HealthCheckRequest{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if resp.GetStatus() != grpc_health_v1.HealthCheckResponse_SERVING {
+		t.Fatalf("expected SERVING, got %v\n", resp.GetStatus())
+	}
+
+	s.Stop()
+	if err := <-done; err != nil {
+		t.Fatalf("expected nil once stopped, got %v\n", err)
+	}
+}
+
+func TestServeConnClosedByParent(t *testing.T) {
+
+	client, server := net.Pipe()
+
+	s := grpc.NewServer()
+	defer s.Stop()
+	grpc_health_v1.RegisterHealthServer(s, health.NewServer())
+	done := make(chan error, 1)
+	go func() { done <- ServeConn(s, server) }()
+
+	cc, err := DialConn(client)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	if _, err := grpc_health_v1.NewHealthClient(cc).Check(ctx, &grpc_health_v1.HealthCheckRequest{}); err != nil {
+		t.Fatal(err)
+	}
+
+	// Closing the parent end ends the plugin's input
+	cc.Close()
+	select {
+	case err := <-done:
+		if err != nil {
+			t.Fatalf("expected nil once the parent has gone, got %v\n", err)
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatal("ServeConn did not return once the parent closed the connection")
+	}
+}
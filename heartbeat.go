@@ -0,0 +1,192 @@
+package launcher
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"os"
+	"strconv"
+	"time"
+)
+
+// ErrHeartbeatMissed is the cause recorded for a process terminated
+// after failing to answer a heartbeat within its deadline
+var ErrHeartbeatMissed = errors.New("process missed its heartbeat")
+
+var errMissingAction = errors.New("heartbeat action must be provided")
+
+const (
+	// heartbeatPingEnv names the descriptor from which the process reads pings
+	heartbeatPingEnv = "HEARTBEAT_PING"
+	// heartbeatPongEnv names the descriptor to which the process echoes pings
+	heartbeatPongEnv = "HEARTBEAT_PONG"
+)
+
+// HeartbeatAction is taken when the process misses a heartbeat, with
+// an error describing the miss
+type HeartbeatAction func(l *Launcher, err error)
+
+// HeartbeatSignal returns a HeartbeatAction sending the signal to
+// the process, which is left running
+func HeartbeatSignal(sig os.Signal) HeartbeatAction {
+	return func(l *Launcher, err error) {
+		l.Signal(sig)
+	}
+}
+
+// HeartbeatTerminate returns a HeartbeatAction asking the process to
+// terminate, killing it if it has not exited within the grace period,
+// with ErrHeartbeatMissed as the cause.  A Supervisor relaunches
+// a process terminated in this way, regardless of its RestartPolicy
+func HeartbeatTerminate(grace time.Duration) HeartbeatAction {
+	return func(l *Launcher, err error) {
+		go l.terminate(l.done, grace, ErrHeartbeatMissed)
+	}
+}
+
+// HeartbeatNotify returns a HeartbeatAction sending the error to the
+// channel, which is dropped if the channel is not ready to receive it
+func HeartbeatNotify(ch chan<- error) HeartbeatAction {
+	return func(l *Launcher, err error) {
+		select {
+		case ch <- err:
+		default:
+		}
+	}
+}
+
+// heartbeat is the configuration set by WithHeartbeat
+type heartbeat struct {
+	interval time.Duration
+	deadline time.Duration
+	action   HeartbeatAction
+}
+
+// WithHeartbeat sends the process a ping every interval, which it must
+// echo within the deadline, taking the action whenever it does not.
+// This catches a process which is live-locked, even if it produces no
+// output.  Each ping is a line holding a sequence number, written to
+// the descriptor whose number is set in HEARTBEAT_PING_FD, and must be
+// written back unchanged to that in HEARTBEAT_PONG_FD.  Extra
+// descriptors are not supported on Windows
+func WithHeartbeat(interval, deadline time.Duration, action HeartbeatAction) Option {
+	return func(o *options) error {
+		if interval <= 0 {
+			return errInvalidInterval
+		}
+		if deadline <= 0 {
+			return errInvalidTimeout
+		}
+		if action == nil {
+			return errMissingAction
+		}
+		o.heartbeat = &heartbeat{interval: interval, deadline: deadline, action: action}
+		return nil
+	}
+}
+
+// heartbeatPipes creates the pipes carrying the pings and their
+// echoes, passing the process its ends as extra files
+func (l *Launcher) heartbeatPipes() error {
+	if l.opts.heartbeat == nil {
+		return nil
+	}
+
+	pingR, pingW, err := os.Pipe()
+	if err != nil {
+		return err
+	}
+	l.childFiles = append(l.childFiles, pingR)
+	l.pingWriter = pingW
+
+	pongR, pongW, err := os.Pipe()
+	if err != nil {
+		return err
+	}
+	l.childFiles = append(l.childFiles, pongW)
+	l.pongReader = pongR
+
+	for _, p := range []struct {
+		name string
+		f    *os.File
+	}{{heartbeatPingEnv, pingR}, {heartbeatPongEnv, pongW}} {
+		l.cmd.ExtraFiles = append(l.cmd.ExtraFiles, p.f)
+		fd := 2 + len(l.cmd.ExtraFiles)
+		l.cmd.Env = append(l.cmd.Env, fmt.Sprintf("%s_FD=%d", p.name, fd))
+	}
+	return nil
+}
+
+// sendHeartbeats pings the process every interval until it exits,
+// taking the action for each ping not echoed within the deadline
+func (l *Launcher) sendHeartbeats() {
+	hb := l.opts.heartbeat
+	ping, pong := l.pingWriter, l.pongReader
+	defer ping.Close()
+	defer pong.Close()
+
+	echoes := make(chan string)
+	l.goLabelled("heartbeat-echo", func() {
+		defer close(echoes)
+
+		s := bufio.NewScanner(pong)
+		for s.Scan() {
+			select {
+			case echoes <- s.Text():
+			case <-l.done:
+				return
+			}
+		}
+	})
+
+	ticker := time.NewTicker(hb.interval)
+	defer ticker.Stop()
+
+	for seq := 1; ; seq++ {
+		select {
+		case <-l.done:
+			return
+		case <-ticker.C:
+		}
+
+		want := strconv.Itoa(seq)
+		deadline := time.NewTimer(hb.deadline)
+
+		// The write deadline prevents a process which has stopped
+		// reading pings from blocking the heartbeat, where supported
+		ping.SetWriteDeadline(time.Now().Add(hb.deadline))
+		_, err := ping.WriteString(want + "\n")
+
+		for err == nil {
+			select {
+			case <-l.done:
+				deadline.Stop()
+				return
+			case echo, ok := <-echoes:
+				if !ok {
+					echoes = nil
+					continue
+				}
+				if echo != want {
+					continue
+				}
+			case <-deadline.C:
+				err = fmt.Errorf("%w: no echo of ping %d within %v", ErrHeartbeatMissed, seq, hb.deadline)
+			}
+			break
+		}
+		deadline.Stop()
+
+		if err != nil {
+			select {
+			case <-l.done:
+				return
+			default:
+			}
+			if !errors.Is(err, ErrHeartbeatMissed) {
+				err = fmt.Errorf("%w: %v", ErrHeartbeatMissed, err)
+			}
+			hb.action(l, err)
+		}
+	}
+}
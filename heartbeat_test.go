@@ -0,0 +1,87 @@
+package launcher
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+)
+
+func TestHeartbeatEchoed(t *testing.T) {
+
+	missed := make(chan error, 10)
+	l, err := NewWithOptions(context.Background(), "sh", nil, []string{"-c", `eval "exec 5<&$HEARTBEAT_PING_FD 6>&$HEARTBEAT_PONG_FD"; for i in 1 2 3 4 5; do read -r p <&5; echo "$p" >&6; done`},
+		WithHeartbeat(50*time.Millisecond, time.Second, HeartbeatNotify(missed)))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer l.Close()
+
+	if err := l.Run(); err != nil {
+		t.Fatal(err)
+	}
+	select {
+	case err := <-missed:
+		t.Fatalf("unexpected missed heartbeat: %v\n", err)
+	default:
+	}
+}
+
+func TestHeartbeatMissed(t *testing.T) {
+
+	l, err := NewWithOptions(context.Background(), "sleep", nil, []string{"10"},
+		WithHeartbeat(50*time.Millisecond, 100*time.Millisecond, HeartbeatTerminate(time.Second)))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer l.Close()
+
+	start := time.Now()
+	if err := l.Run(); err == nil {
+		t.Fatal("expected error from terminated process")
+	}
+	if time.Since(start) > 5*time.Second {
+		t.Fatal("expected process to be terminated promptly")
+	}
+	if !errors.Is(l.Cause(), ErrHeartbeatMissed) {
+		t.Fatalf("expected ErrHeartbeatMissed, got %v\n", l.Cause())
+	}
+}
+
+func TestHeartbeatInvalid(t *testing.T) {
+
+	for _, o := range []Option{
+		WithHeartbeat(0, time.Second, HeartbeatNotify(nil)),
+		WithHeartbeat(time.Second, 0, HeartbeatNotify(nil)),
+		WithHeartbeat(time.Second, time.Second, nil),
+	} {
+		if _, err := NewWithOptions(context.Background(), "echo", nil, nil, o); err == nil {
+			t.Fatal("expected error from invalid heartbeat")
+		}
+	}
+}
+
+func TestSupervisorHeartbeatRestart(t *testing.T) {
+
+	spec := Spec{
+		File:    "sleep",
+		Args:    []string{"10"},
+		Options: []Option{WithHeartbeat(50*time.Millisecond, 100*time.Millisecond, HeartbeatTerminate(time.Second))},
+	}
+	s, err := NewSupervisor(context.Background(), spec)
+	if err != nil {
+		t.Fatal(err)
+	}
+	s.Restart = RestartNever
+	s.MaxRestarts = 1
+
+	if err := s.Start(); err != nil {
+		t.Fatal(err)
+	}
+	if err := s.Wait(); err == nil {
+		t.Fatal("expected error from terminated process")
+	}
+	if s.Restarts() != 1 {
+		t.Fatalf("expected 1 restart, got %v\n", s.Restarts())
+	}
+}
@@ -0,0 +1,184 @@
+// Package httpapi exposes a launcher.Manager over HTTP, allowing the
+// registered processes to be inspected and controlled, and their
+// output to be streamed as Server-Sent Events.
+//
+// The Handler serves the following endpoints:
+//
+//	GET  /processes                 status of all processes
+//	GET  /processes/{name}          status of the named process
+//	POST /processes/{name}/start    start the named process
+//	POST /processes/{name}/stop     stop the named process, with optional ?grace=duration
+//	POST /processes/{name}/restart  restart the named process
+//	GET  /processes/{name}/logs     stream output of the named process
+//	GET  /logs                      stream output of all processes
+//
+// Processes cannot be defined over HTTP; they must be launched through
+// the Manager.  The Handler performs no authentication, so should only
+// be exposed to trusted clients.
+package httpapi
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/gford1000-go/launcher"
+)
+
+// defaultGrace is the time allowed for a process to exit on stop,
+// if not specified in the request
+const defaultGrace = 10 * time.Second
+
+// Handler is an http.Handler controlling the processes of a Manager
+type Handler struct {
+	m *launcher.Manager
+}
+
+// NewHandler creates a new Handler for the Manager
+func NewHandler(m *launcher.Manager) *Handler {
+	return &Handler{m: m}
+}
+
+// ServeHTTP routes the request to the appropriate endpoint
+func (h *Handler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	parts := strings.Split(strings.Trim(r.URL.Path, "/"), "/")
+
+	switch {
+	case len(parts) == 1 && parts[0] == "logs":
+		h.allow(w, r, http.MethodGet, func() { h.logs(w, r, "") })
+	case len(parts) == 1 && parts[0] == "processes":
+		h.allow(w, r, http.MethodGet, func() { h.list(w) })
+	case len(parts) == 2 && parts[0] == "processes":
+		h.allow(w, r, http.MethodGet, func() { h.inspect(w, parts[1]) })
+	case len(parts) == 3 && parts[0] == "processes":
+		name := parts[1]
+		switch parts[2] {
+		case "start":
+			h.allow(w, r, http.MethodPost, func() { h.control(w, name, h.m.Start) })
+		case "stop":
+			h.allow(w, r, http.MethodPost, func() { h.stop(w, r, name) })
+		case "restart":
+			h.allow(w, r, http.MethodPost, func() { h.control(w, name, h.m.Restart) })
+		case "logs":
+			h.allow(w, r, http.MethodGet, func() { h.logs(w, r, name) })
+		default:
+			http.NotFound(w, r)
+		}
+	default:
+		http.NotFound(w, r)
+	}
+}
+
+// allow calls f if the request uses the method, and otherwise
+// responds with 405 Method Not Allowed
+func (h *Handler) allow(w http.ResponseWriter, r *http.Request, method string, f func()) {
+	if r.Method != method {
+		w.Header().Set("Allow", method)
+		http.Error(w, http.StatusText(http.StatusMethodNotAllowed), http.StatusMethodNotAllowed)
+		return
+	}
+	f()
+}
+
+func (h *Handler) list(w http.ResponseWriter) {
+	writeJSON(w, h.m.List())
+}
+
+func (h *Handler) inspect(w http.ResponseWriter, name string) {
+	s, err := h.m.Inspect(name)
+	if err != nil {
+		writeError(w, err)
+		return
+	}
+	writeJSON(w, s)
+}
+
+// control applies f to the named process, responding with its status
+func (h *Handler) control(w http.ResponseWriter, name string, f func(name string) error) {
+	if err := f(name); err != nil {
+		writeError(w, err)
+		return
+	}
+	h.inspect(w, name)
+}
+
+func (h *Handler) stop(w http.ResponseWriter, r *http.Request, name string) {
+	grace := defaultGrace
+	if s := r.URL.Query().Get("grace"); s != "" {
+		d, err := time.ParseDuration(s)
+		if err != nil {
+			http.Error(w, fmt.Sprintf("invalid grace: %v", err), http.StatusBadRequest)
+			return
+		}
+		grace = d
+	}
+
+	h.control(w, name, func(name string) error {
+		return h.m.Stop(name, grace)
+	})
+}
+
+// logs streams output lines as Server-Sent Events, with the event
+// type being the stream and the data being the JSON encoded Line
+func (h *Handler) logs(w http.ResponseWriter, r *http.Request, name string) {
+	if name != "" {
+		if _, err := h.m.Inspect(name); err != nil {
+			writeError(w, err)
+			return
+		}
+	}
+
+	flusher, ok := w.(http.Flusher)
+	if !ok {
+		http.Error(w, "streaming unsupported", http.StatusInternalServerError)
+		return
+	}
+
+	lines, cancel := h.m.Subscribe(name)
+	defer cancel()
+
+	w.Header().Set("Content-Type", "text/event-stream")
+	w.Header().Set("Cache-Control", "no-cache")
+	w.Header().Set("Connection", "keep-alive")
+	w.WriteHeader(http.StatusOK)
+	flusher.Flush()
+
+	for {
+		select {
+		case <-r.Context().Done():
+			return
+		case line, ok := <-lines:
+			if !ok {
+				return
+			}
+			b, err := json.Marshal(line)
+			if err != nil {
+				return
+			}
+			if _, err := fmt.Fprintf(w, "event: %s\ndata: %s\n\n", line.Stream, b); err != nil {
+				return
+			}
+			flusher.Flush()
+		}
+	}
+}
+
+func writeJSON(w http.ResponseWriter, v any) {
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(v)
+}
+
+// writeError maps Manager errors to the appropriate status code
+func writeError(w http.ResponseWriter, err error) {
+	code := http.StatusInternalServerError
+	switch {
+	case errors.Is(err, launcher.ErrUnknownName):
+		code = http.StatusNotFound
+	case errors.Is(err, launcher.ErrAlreadyRunning):
+		code = http.StatusConflict
+	}
+	http.Error(w, err.Error(), code)
+}
@@ -0,0 +1,127 @@
+package httpapi
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/gford1000-go/launcher"
+)
+
+func newServer(t *testing.T) (*launcher.Manager, *httptest.Server) {
+	m, err := launcher.NewManager(context.Background())
+	if err != nil {
+		t.Fatal(err)
+	}
+	srv := httptest.NewServer(NewHandler(m))
+	t.Cleanup(func() {
+		srv.Close()
+		m.Close()
+	})
+	return m, srv
+}
+
+func TestHandlerInspectAndStop(t *testing.T) {
+
+	m, srv := newServer(t)
+
+	if err := m.Launch("sleeper", launcher.Spec{File: "sleep", Args: []string{"10"}}); err != nil {
+		t.Fatal(err)
+	}
+
+	resp, err := http.Get(srv.URL + "/processes")
+	if err != nil {
+		t.Fatal(err)
+	}
+	var l []launcher.Status
+	json.NewDecoder(resp.Body).Decode(&l)
+	resp.Body.Close()
+
+	if len(l) != 1 || l[0].Name != "sleeper" || !l[0].Running {
+		t.Fatalf("unexpected list: %+v\n", l)
+	}
+
+	resp, err = http.Post(srv.URL+"/processes/sleeper/stop?grace=1s", "", nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var s launcher.Status
+	json.NewDecoder(resp.Body).Decode(&s)
+	resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK || s.Running {
+		t.Fatalf("unexpected response: %v %+v\n", resp.StatusCode, s)
+	}
+}
+
+func TestHandlerErrors(t *testing.T) {
+
+	_, srv := newServer(t)
+
+	for _, test := range []struct {
+		method string
+		path   string
+		code   int
+	}{
+		{http.MethodGet, "/processes/zzz", http.StatusNotFound},
+		{http.MethodPost, "/processes/zzz/start", http.StatusNotFound},
+		{http.MethodGet, "/processes/zzz/start", http.StatusMethodNotAllowed},
+		{http.MethodGet, "/unknown", http.StatusNotFound},
+	} {
+		req, _ := http.NewRequest(test.method, srv.URL+test.path, nil)
+		resp, err := http.DefaultClient.Do(req)
+		if err != nil {
+			t.Fatal(err)
+		}
+		resp.Body.Close()
+
+		if resp.StatusCode != test.code {
+			t.Fatalf("%s %s: expected %v, got %v\n", test.method, test.path, test.code, resp.StatusCode)
+		}
+	}
+}
+
+func TestHandlerLogs(t *testing.T) {
+
+	m, srv := newServer(t)
+
+	if err := m.Launch("talker", launcher.Spec{File: "sh", Args: []string{"-c", "sleep 0.2; echo foo; exec sleep 10"}}); err != nil {
+		t.Fatal(err)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	req, _ := http.NewRequestWithContext(ctx, http.MethodGet, srv.URL+"/processes/talker/logs", nil)
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer resp.Body.Close()
+
+	if ct := resp.Header.Get("Content-Type"); ct != "text/event-stream" {
+		t.Fatalf("unexpected content type %q\n", ct)
+	}
+
+	scanner := bufio.NewScanner(resp.Body)
+	for scanner.Scan() {
+		data, ok := strings.CutPrefix(scanner.Text(), "data: ")
+		if !ok {
+			continue
+		}
+		var line launcher.Line
+		if err := json.Unmarshal([]byte(data), &line); err != nil {
+			t.Fatal(err)
+		}
+		if line.Text != "foo" || line.Stream != "stdout" {
+			t.Fatalf("unexpected line: %+v\n", line)
+		}
+		return
+	}
+	t.Fatal(scanner.Err())
+}
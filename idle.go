@@ -0,0 +1,60 @@
+package launcher
+
+import (
+	"errors"
+	"time"
+)
+
+// ErrIdleTimeout is the cause recorded for a process terminated after
+// a period without activity, as set by WithIdleTimeout
+var ErrIdleTimeout = errors.New("process was idle for longer than its idle timeout")
+
+// minIdlePoll is the shortest pause between checks for activity
+const minIdlePoll = 10 * time.Millisecond
+
+// WithIdleTimeout terminates the process once nothing has been written
+// to its stdin or read from its stdout for the duration, killing it if
+// it has not exited within the grace period used by Stop, as for an
+// interactive interpreter which should not linger once unused.  Output
+// is only observed as it is read, so the stdout of the process should
+// be consumed
+func WithIdleTimeout(d time.Duration) Option {
+	return func(o *options) error {
+		if d <= 0 {
+			return errInvalidTimeout
+		}
+		o.idleTimeout = d
+		return nil
+	}
+}
+
+// watchIdle checks for activity on stdin and stdout until the process
+// exits, terminating it once the idle timeout has passed without any
+func (l *Launcher) watchIdle() {
+	timeout := l.opts.idleTimeout
+	poll := max(timeout/10, minIdlePoll)
+
+	ticker := time.NewTicker(poll)
+	defer ticker.Stop()
+
+	activity := func() int64 {
+		return l.counters.stdin.Load() + l.counters.stdout.Load()
+	}
+
+	last, lastAt := activity(), time.Now()
+	for {
+		select {
+		case <-l.done:
+			return
+		case now := <-ticker.C:
+			if n := activity(); n != last {
+				last, lastAt = n, now
+				continue
+			}
+			if now.Sub(lastAt) >= timeout {
+				l.terminate(l.done, defaultStopGrace, ErrIdleTimeout)
+				return
+			}
+		}
+	}
+}
@@ -0,0 +1,49 @@
+package launcher
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+)
+
+func TestIdleTimeout(t *testing.T) {
+
+	l, err := NewWithOptions(context.Background(), "cat", nil, nil, WithIdleTimeout(200*time.Millisecond))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer l.Close()
+
+	if err := l.Start(); err != nil {
+		t.Fatal(err)
+	}
+	go l.copyOutput(nil, nil)
+
+	// Activity keeps the process alive beyond the timeout
+	for i := 0; i < 5; i++ {
+		if _, err := l.Write([]byte("foo\n")); err != nil {
+			t.Fatal(err)
+		}
+		time.Sleep(100 * time.Millisecond)
+	}
+	if !l.IsRunning() {
+		t.Fatal("expected active process to be running")
+	}
+
+	select {
+	case <-l.done:
+	case <-time.After(5 * time.Second):
+		t.Fatal("expected idle process to be terminated")
+	}
+	if !errors.Is(l.Cause(), ErrIdleTimeout) {
+		t.Fatalf("expected ErrIdleTimeout, got %v\n", l.Cause())
+	}
+}
+
+func TestIdleTimeoutInvalid(t *testing.T) {
+
+	if _, err := NewWithOptions(context.Background(), "cat", nil, nil, WithIdleTimeout(0)); err == nil {
+		t.Fatal("expected error from invalid timeout")
+	}
+}
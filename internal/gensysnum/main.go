@@ -0,0 +1,85 @@
+// Command gensysnum generates the tables mapping syscall names to numbers
+// used by seccomp profiles, from the constants of golang.org/x/sys/unix
+package main
+
+import (
+	"bufio"
+	"bytes"
+	"fmt"
+	"go/format"
+	"log"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
+)
+
+// arches maps the architectures for which tables are generated
+// to the audit architecture identifying them to seccomp
+var arches = map[string]string{
+	"amd64": "AUDIT_ARCH_X86_64",
+	"arm64": "AUDIT_ARCH_AARCH64",
+}
+
+var sysnum = regexp.MustCompile(`^\s*SYS_([A-Z0-9_]+)\s*=\s*(\d+)`)
+
+func main() {
+	out, err := exec.Command("go", "list", "-m", "-f", "{{.Dir}}", "golang.org/x/sys").Output()
+	if err != nil {
+		log.Fatal(err)
+	}
+	dir := filepath.Join(strings.TrimSpace(string(out)), "unix")
+
+	for arch, audit := range arches {
+		if err := generate(dir, arch, audit); err != nil {
+			log.Fatal(err)
+		}
+	}
+}
+
+// generate writes the table for the architecture
+func generate(dir, arch, audit string) error {
+	f, err := os.Open(filepath.Join(dir, "zsysnum_linux_"+arch+".go"))
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+
+	nums := map[string]string{}
+	scanner := bufio.NewScanner(f)
+	for scanner.Scan() {
+		if m := sysnum.FindStringSubmatch(scanner.Text()); m != nil {
+			nums[strings.ToLower(m[1])] = m[2]
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return err
+	}
+
+	names := make([]string, 0, len(nums))
+	for n := range nums {
+		names = append(names, n)
+	}
+	sort.Strings(names)
+
+	var b bytes.Buffer
+	fmt.Fprintf(&b, "// Code generated by internal/gensysnum. DO NOT EDIT.\n\n")
+	fmt.Fprintf(&b, "//go:build linux && %s\n\npackage launcher\n\n", arch)
+	fmt.Fprintf(&b, "import \"golang.org/x/sys/unix\"\n\n")
+	fmt.Fprintf(&b, "// auditArch identifies the architecture to seccomp\n")
+	fmt.Fprintf(&b, "const auditArch = unix.%s\n\n", audit)
+	fmt.Fprintf(&b, "// syscallNumbers maps the names of syscalls to their numbers\n")
+	fmt.Fprintf(&b, "var syscallNumbers = map[string]uint32{\n")
+	for _, n := range names {
+		fmt.Fprintf(&b, "\t%q: %s,\n", n, nums[n])
+	}
+	fmt.Fprintf(&b, "}\n")
+
+	src, err := format.Source(b.Bytes())
+	if err != nil {
+		return err
+	}
+	return os.WriteFile("seccomp_sysnum_linux_"+arch+".go", src, 0o644)
+}
@@ -0,0 +1,79 @@
+package launcher
+
+import (
+	"io"
+	"os"
+	"sync/atomic"
+)
+
+// IOStats is the volume of data exchanged with a process
+type IOStats struct {
+	// StdinBytes is the number of bytes written to its stdin
+	StdinBytes int64
+	// StdoutBytes and StderrBytes are the number of bytes read from
+	// its stdout and stderr, before any filtering by the Launcher
+	StdoutBytes int64
+	StderrBytes int64
+}
+
+// ioCounters accumulates the IOStats of a process
+type ioCounters struct {
+	stdin  atomic.Int64
+	stdout atomic.Int64
+	stderr atomic.Int64
+}
+
+// IOStats returns the volume of data exchanged with the process so
+// far.  Streams which are not piped, because of WithDiscardOutput or
+// WithInheritStdio, are not counted
+func (l *Launcher) IOStats() IOStats {
+	return IOStats{
+		StdinBytes:  l.counters.stdin.Load(),
+		StdoutBytes: l.counters.stdout.Load(),
+		StderrBytes: l.counters.stderr.Load(),
+	}
+}
+
+// countIO counts the data passing through the pipes to the process,
+// and must be called during initialise, once stdin has been wrapped
+func (l *Launcher) countIO() {
+	if _, ok := l.cmdWriter.(inheritedStdin); !ok && l.cmdWriter != nil {
+		l.cmdWriter = &countingWriter{WriteCloser: l.cmdWriter, n: &l.counters.stdin}
+	}
+	if l.cmdStdOut != nil {
+		l.cmdStdOut = &countingReader{ReadCloser: l.cmdStdOut, n: &l.counters.stdout}
+	}
+	if l.cmdStdErr != nil {
+		l.cmdStdErr = &countingReader{ReadCloser: l.cmdStdErr, n: &l.counters.stderr}
+	}
+}
+
+// countingWriter counts the bytes written to stdin
+type countingWriter struct {
+	io.WriteCloser
+	n *atomic.Int64
+}
+
+func (c *countingWriter) Write(b []byte) (int, error) {
+	n, err := c.WriteCloser.Write(b)
+	c.n.Add(int64(n))
+	return n, err
+}
+
+// countingReader counts the bytes read from an output pipe
+type countingReader struct {
+	io.ReadCloser
+	n *atomic.Int64
+}
+
+func (c *countingReader) Read(b []byte) (int, error) {
+	n, err := c.ReadCloser.Read(b)
+	c.n.Add(int64(n))
+	return n, err
+}
+
+// file returns the pipe being counted, if it is an *os.File
+func (c *countingReader) file() (*os.File, bool) {
+	f, ok := c.ReadCloser.(*os.File)
+	return f, ok
+}
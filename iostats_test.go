@@ -0,0 +1,62 @@
+package launcher
+
+import (
+	"context"
+	"io"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestLauncherIOStats(t *testing.T) {
+
+	l, err := New(context.Background(), "sh", nil, "-c", "cat; echo error >&2")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer l.Close()
+
+	if err := l.Start(); err != nil {
+		t.Fatal(err)
+	}
+	if err := l.SendStdIn([]byte("hello world\n")); err != nil {
+		t.Fatal(err)
+	}
+	l.StdinWriter().Close()
+
+	// Output copied to a file is counted when spliced
+	f, err := os.Create(filepath.Join(t.TempDir(), "out"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer f.Close()
+	l.copyOutput(f, io.Discard)
+	l.Wait()
+
+	expected := IOStats{StdinBytes: 12, StdoutBytes: 12, StderrBytes: 6}
+	if stats := l.IOStats(); stats != expected {
+		t.Fatalf("expected %+v, got %+v\n", expected, stats)
+	}
+}
+
+func TestLauncherIOStatsFiltered(t *testing.T) {
+
+	l, err := NewWithOptions(context.Background(), "echo", nil, []string{"hello"}, WithOutputPrefix("[x] "))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer l.Close()
+
+	if err := l.Run(); err != nil {
+		t.Fatal(err)
+	}
+	b, err := io.ReadAll(l)
+	if err != nil || string(b) != "[x] hello\n" {
+		t.Fatalf("unexpected output %q (%v)\n", b, err)
+	}
+
+	// The output is counted before the prefix is added
+	if stats := l.IOStats(); stats.StdoutBytes != 6 {
+		t.Fatalf("expected 6 bytes of stdout, got %+v\n", stats)
+	}
+}
@@ -0,0 +1,56 @@
+package launcher
+
+import "io"
+
+// Launcher is an io.ReadWriteCloser, reading from the stdout of the
+// process, writing to its stdin, and releasing all resources on Close
+var _ io.ReadWriteCloser = (*Launcher)(nil)
+
+// Read reads from the stdout of the process, so that the Launcher can
+// be passed directly to io.Copy, bufio.NewReader or json.NewDecoder
+func (l *Launcher) Read(p []byte) (int, error) {
+	return l.StdOutReader().Read(p)
+}
+
+// WriteTo copies the stdout of the process to w until EOF, so that
+// io.Copy from the Launcher to an *os.File is spliced by the kernel
+// where the platform supports it
+func (l *Launcher) WriteTo(w io.Writer) (int64, error) {
+	return copyStream(w, l.StdOutReader())
+}
+
+// Write writes to the stdin of the process, returning the number of
+// bytes transferred along with any error.  SendStdIn is equivalent,
+// but treats a partial write as ErrIncompleteStdinTransfer
+func (l *Launcher) Write(p []byte) (int, error) {
+	if l.adopted {
+		return 0, errAdopted
+	}
+	if l.State() == StateClosed {
+		return 0, l.launchError(StageRuntime, ErrAlreadyClosed)
+	}
+	return l.cmdWriter.Write(p)
+}
+
+// StdinWriter returns a writer to the stdin of the process, whose Close
+// closes only stdin so that the process sees EOF, as is needed when
+// stdin is the destination of io.Copy or a json.Encoder
+func (l *Launcher) StdinWriter() io.WriteCloser {
+	return stdinWriter{l}
+}
+
+// stdinWriter is the io.WriteCloser returned by StdinWriter
+type stdinWriter struct {
+	l *Launcher
+}
+
+func (s stdinWriter) Write(p []byte) (int, error) {
+	return s.l.Write(p)
+}
+
+func (s stdinWriter) Close() error {
+	if s.l.adopted {
+		return errAdopted
+	}
+	return s.l.cmdWriter.Close()
+}
@@ -0,0 +1,86 @@
+package launcher
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"io"
+	"strings"
+	"testing"
+)
+
+func TestLauncherReadWriter(t *testing.T) {
+
+	l, err := New(context.Background(), "cat", nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer l.Close()
+
+	if err := l.Start(); err != nil {
+		t.Fatal(err)
+	}
+
+	type message struct {
+		Name  string
+		Count int
+	}
+
+	go func() {
+		enc := json.NewEncoder(l)
+		enc.Encode(message{"a", 1})
+		enc.Encode(message{"b", 2})
+		l.StdinWriter().Close()
+	}()
+
+	dec := json.NewDecoder(l)
+	var got []message
+	for {
+		var m message
+		if err := dec.Decode(&m); err == io.EOF {
+			break
+		} else if err != nil {
+			t.Fatal(err)
+		}
+		got = append(got, m)
+	}
+
+	if len(got) != 2 || got[0] != (message{"a", 1}) || got[1] != (message{"b", 2}) {
+		t.Fatalf("unexpected messages %v\n", got)
+	}
+	if err := l.Wait(); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestStdinWriterCopy(t *testing.T) {
+
+	l, err := New(context.Background(), "tr", nil, "a-z", "A-Z")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer l.Close()
+
+	if err := l.Start(); err != nil {
+		t.Fatal(err)
+	}
+
+	go func() {
+		w := l.StdinWriter()
+		io.Copy(w, strings.NewReader("hello world"))
+		w.Close()
+	}()
+
+	var sb strings.Builder
+	if _, err := io.Copy(&sb, l); err != nil {
+		t.Fatal(err)
+	}
+	if sb.String() != "HELLO WORLD" {
+		t.Fatalf("unexpected output %q\n", sb.String())
+	}
+
+	l.Close()
+	if _, err := l.Write([]byte("x")); !errors.Is(err, ErrAlreadyClosed) {
+		t.Fatalf("expected ErrAlreadyClosed, got %v\n", err)
+	}
+}
@@ -0,0 +1,71 @@
+package launcher
+
+import (
+	"errors"
+	"os"
+)
+
+// maxKillTreeSweeps limits the enumerations of the tree made while
+// freezing it, in case descendants are forked faster than they stop
+const maxKillTreeSweeps = 10
+
+// WithKillTree makes cancellation of the Launcher kill all descendants
+// of the process, as KillTree does, rather than just the process itself,
+// so that cancelling a shell wrapper leaves no stray workers behind
+func WithKillTree() Option {
+	return func(o *options) error {
+		o.killTree = true
+		return nil
+	}
+}
+
+// KillTree kills the process and all of its descendants, including those
+// which have started new sessions or process groups.  Where the platform
+// allows, the tree is first frozen so that no new descendants escape
+func (l *Launcher) KillTree() error {
+	if !l.IsStarted() {
+		return l.launchError(StageRuntime, ErrNotStarted)
+	}
+	select {
+	case <-l.done:
+		return nil
+	default:
+	}
+
+	root := l.Pid()
+	stopProcess(root)
+
+	// Freeze each newly found descendant until no more appear
+	var tree []ProcInfo
+	stopped := map[int]bool{}
+	for i := 0; i < maxKillTreeSweeps; i++ {
+		procs, err := listProcesses()
+		if err != nil {
+			break
+		}
+		tree = descendants(procs, root)
+
+		found := false
+		for _, p := range tree {
+			if !stopped[p.Pid] {
+				stopped[p.Pid] = true
+				stopProcess(p.Pid)
+				found = true
+			}
+		}
+		if !found {
+			break
+		}
+	}
+
+	var errs []error
+	if err := l.cmd.Process.Kill(); err != nil && !errors.Is(err, os.ErrProcessDone) {
+		errs = append(errs, err)
+	}
+	for pid := range stopped {
+		if err := killProcess(pid); err != nil {
+			errs = append(errs, err)
+		}
+	}
+	return errors.Join(errs...)
+}
@@ -0,0 +1,100 @@
+package launcher
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+)
+
+// waitForTree waits until the process has at least n descendants
+func waitForTree(t *testing.T, l *Launcher, n int) []ProcInfo {
+	deadline := time.Now().Add(5 * time.Second)
+	for {
+		tree, err := l.ProcessTree()
+		if err != nil {
+			t.Fatal(err)
+		}
+		if len(tree) >= n {
+			return tree
+		}
+		if time.Now().After(deadline) {
+			t.Fatalf("expected %v descendants, got %+v\n", n, tree)
+		}
+		time.Sleep(20 * time.Millisecond)
+	}
+}
+
+// waitDead waits until none of the processes are running, treating
+// zombies as dead as they may not be reaped promptly by init
+func waitDead(t *testing.T, tree []ProcInfo) {
+	deadline := time.Now().Add(5 * time.Second)
+	for _, p := range tree {
+		for {
+			if s, err := readProcStat(p.Pid); err != nil || s.state == 'Z' {
+				break
+			}
+			if time.Now().After(deadline) {
+				t.Fatalf("expected %+v to have been killed\n", p)
+			}
+			time.Sleep(10 * time.Millisecond)
+		}
+	}
+}
+
+func TestKillTree(t *testing.T) {
+
+	l, err := New(context.Background(), "sh", nil, "-c", "sh -c 'sleep 10' & setsid sleep 10 & wait")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer l.Close()
+
+	if err := l.KillTree(); !errors.Is(err, ErrNotStarted) {
+		t.Fatalf("expected ErrNotStarted, got %v\n", err)
+	}
+	if err := l.Start(); err != nil {
+		t.Fatal(err)
+	}
+
+	tree := waitForTree(t, l, 3)
+
+	if err := l.KillTree(); err != nil {
+		t.Fatal(err)
+	}
+	if err := l.Wait(); err == nil {
+		t.Fatal("expected killed process to fail")
+	}
+	waitDead(t, tree)
+}
+
+func TestWithKillTree(t *testing.T) {
+
+	l, err := NewWithOptions(context.Background(), "sh", nil, []string{"-c", "sleep 10 & sleep 10 & wait"}, WithKillTree())
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer l.Close()
+
+	if err := l.Start(); err != nil {
+		t.Fatal(err)
+	}
+	tree := waitForTree(t, l, 2)
+
+	l.Cancel()
+
+	// The output pipes are closed once the sleeps holding them are killed
+	done := make(chan struct{})
+	go func() {
+		l.copyOutput(nil, nil)
+		close(done)
+	}()
+	select {
+	case <-done:
+	case <-time.After(5 * time.Second):
+		t.Fatal("expected output to end once the tree was killed")
+	}
+
+	l.Wait()
+	waitDead(t, tree)
+}
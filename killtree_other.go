@@ -0,0 +1,25 @@
+//go:build !unix
+
+package launcher
+
+import (
+	"errors"
+	"os"
+)
+
+// stopProcess cannot freeze a process on this platform
+func stopProcess(pid int) {}
+
+// killProcess kills the process, ignoring processes which have exited
+func killProcess(pid int) error {
+	p, err := os.FindProcess(pid)
+	if err != nil {
+		return nil
+	}
+	defer p.Release()
+
+	if err := p.Kill(); err != nil && !errors.Is(err, os.ErrProcessDone) {
+		return err
+	}
+	return nil
+}
@@ -0,0 +1,21 @@
+//go:build unix
+
+package launcher
+
+import (
+	"os"
+	"syscall"
+)
+
+// stopProcess freezes the process, so that it cannot fork
+func stopProcess(pid int) {
+	syscall.Kill(pid, syscall.SIGSTOP)
+}
+
+// killProcess kills the process, ignoring processes which have exited
+func killProcess(pid int) error {
+	if err := syscall.Kill(pid, syscall.SIGKILL); err != nil && err != syscall.ESRCH {
+		return os.NewSyscallError("kill", err)
+	}
+	return nil
+}
@@ -0,0 +1,23 @@
+package launcher
+
+import "errors"
+
+var errMissingLabel = errors.New("security label must be provided")
+
+// WithSecurityLabel launches the process under the label, which is an
+// AppArmor profile name or an SELinux context depending upon the module
+// enabled on the host, as done by aa_change_onexec or setexeccon.  The
+// label is set by re-executing the current program as a shim before it
+// execs the target, and is only supported on linux
+func WithSecurityLabel(label string) Option {
+	return func(o *options) error {
+		if label == "" {
+			return errMissingLabel
+		}
+		if _, err := securityModule(); err != nil {
+			return err
+		}
+		o.label = label
+		return nil
+	}
+}
@@ -0,0 +1,57 @@
+package launcher
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"os"
+)
+
+var errLabelUnsupported = errors.New("neither AppArmor nor SELinux is enabled")
+
+// Linux security modules supporting labels
+const (
+	moduleAppArmor = "apparmor"
+	moduleSELinux  = "selinux"
+)
+
+// securityModule returns the security module enabled on the host
+func securityModule() (string, error) {
+	if _, err := os.Stat("/sys/fs/selinux/enforce"); err == nil {
+		return moduleSELinux, nil
+	}
+	if b, err := os.ReadFile("/sys/module/apparmor/parameters/enabled"); err == nil && bytes.HasPrefix(b, []byte("Y")) {
+		return moduleAppArmor, nil
+	}
+	return "", errLabelUnsupported
+}
+
+// installLabel sets the label to be applied to the calling thread
+// when it next execs
+func installLabel(label string) error {
+	module, err := securityModule()
+	if err != nil {
+		return err
+	}
+
+	var path, value string
+	switch module {
+	case moduleSELinux:
+		path, value = "/proc/thread-self/attr/exec", label
+	default:
+		path, value = "/proc/thread-self/attr/apparmor/exec", "exec "+label
+		if _, err := os.Stat(path); err != nil {
+			path = "/proc/thread-self/attr/exec"
+		}
+	}
+
+	f, err := os.OpenFile(path, os.O_WRONLY, 0)
+	if err != nil {
+		return err
+	}
+	if _, err := f.Write([]byte(value)); err != nil {
+		f.Close()
+		return fmt.Errorf("%s label %q: %w", module, label, err)
+	}
+	return f.Close()
+}
@@ -0,0 +1,11 @@
+//go:build !linux
+
+package launcher
+
+import "errors"
+
+var errLabelUnsupported = errors.New("security labels are only supported on linux")
+
+func securityModule() (string, error) {
+	return "", errLabelUnsupported
+}
@@ -0,0 +1,26 @@
+package launcher
+
+import (
+	"context"
+	"testing"
+)
+
+func TestSecurityLabelMissing(t *testing.T) {
+
+	_, err := NewWithOptions(context.Background(), "echo", nil, nil, WithSecurityLabel(""))
+	if err != errMissingLabel {
+		t.Fatal(err)
+	}
+}
+
+func TestSecurityLabelUnsupported(t *testing.T) {
+
+	if _, err := securityModule(); err == nil {
+		t.Skip("a security module is enabled")
+	}
+
+	_, err := NewWithOptions(context.Background(), "echo", nil, nil, WithSecurityLabel("unconfined"))
+	if err != errLabelUnsupported {
+		t.Fatal(err)
+	}
+}
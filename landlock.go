@@ -0,0 +1,45 @@
+package launcher
+
+import "errors"
+
+var errMissingRules = errors.New("landlock rules must be provided")
+
+// LandlockRules declare the filesystem access of a process, beneath
+// each of the paths.  Access to paths not declared is denied
+type LandlockRules struct {
+	// Read lists the paths which may be read and executed
+	Read []string `json:"read,omitempty"`
+	// Write lists the paths which may also be written, created and removed
+	Write []string `json:"write,omitempty"`
+	// BestEffort, if set, launches the process without the restriction
+	// when the kernel does not support Landlock, rather than failing
+	BestEffort bool `json:"-"`
+}
+
+// WithLandlock restricts the filesystem access of the process to the
+// paths declared by the rules, which must include the target and any
+// libraries it loads.  The restriction is applied by re-executing the
+// current program as a shim before it execs the target.  If the kernel
+// does not support Landlock the Launcher cannot be created, unless the
+// rules are BestEffort, and Landlocked reports whether it was applied
+func WithLandlock(rules *LandlockRules) Option {
+	return func(o *options) error {
+		if rules == nil {
+			return errMissingRules
+		}
+		if LandlockABI() == 0 {
+			if rules.BestEffort {
+				return nil
+			}
+			return errLandlockUnsupported
+		}
+		o.landlock = rules
+		return nil
+	}
+}
+
+// Landlocked returns true if the filesystem access of the
+// process is restricted by Landlock
+func (l *Launcher) Landlocked() bool {
+	return l.opts.landlock != nil
+}
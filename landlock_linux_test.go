@@ -0,0 +1,63 @@
+package launcher
+
+import (
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestLandlockRestrictsWrites(t *testing.T) {
+
+	if LandlockABI() == 0 {
+		t.Skip("landlock is not supported by the kernel")
+	}
+
+	allowed, denied := t.TempDir(), t.TempDir()
+	rules := &LandlockRules{
+		Read:  []string{"/"},
+		Write: []string{allowed},
+	}
+
+	script := "touch $0/ok; touch $1/denied"
+	l, err := NewWithOptions(context.Background(), "sh", nil, []string{"-c", script, allowed, denied}, WithLandlock(rules))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer l.Close()
+
+	if !l.Landlocked() {
+		t.Fatal("expected process to be landlocked")
+	}
+	if err := l.Run(); err == nil {
+		t.Fatal("expected write to the denied directory to fail")
+	}
+
+	if _, err := os.Stat(filepath.Join(allowed, "ok")); err != nil {
+		t.Fatalf("expected file in allowed directory, got %v\n", err)
+	}
+	if _, err := os.Stat(filepath.Join(denied, "denied")); !errors.Is(err, os.ErrNotExist) {
+		t.Fatalf("expected no file in denied directory, got %v\n", err)
+	}
+}
+
+func TestLandlockMissingPath(t *testing.T) {
+
+	if LandlockABI() == 0 {
+		t.Skip("landlock is not supported by the kernel")
+	}
+
+	rules := &LandlockRules{Read: []string{"/zzzUnknownzzz"}}
+
+	l, err := NewWithOptions(context.Background(), "echo", nil, nil, WithLandlock(rules))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer l.Close()
+
+	l.Run()
+	if l.ExitCode() != shimExitCode {
+		t.Fatalf("expected shim to fail, got exit code %v\n", l.ExitCode())
+	}
+}
@@ -0,0 +1,12 @@
+//go:build !linux
+
+package launcher
+
+import "errors"
+
+var errLandlockUnsupported = errors.New("landlock is only supported on linux")
+
+// LandlockABI returns 0, as Landlock is only supported on linux
+func LandlockABI() int {
+	return 0
+}
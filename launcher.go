@@ -4,50 +4,144 @@ import (
 	"context"
 	"errors"
 	"io"
+	"os"
 	"os/exec"
+	"sync"
+	"syscall"
+	"time"
 )
 
-var errMissingContext = errors.New("context must be provided")
-var errIncompleteStdIntransfer = errors.New("command did not receive all bytes sent to stdin")
+// ErrMissingContext is returned when a nil context is provided
+var ErrMissingContext = errors.New("context must be provided")
+
+// ErrIncompleteStdinTransfer is returned by SendStdIn when
+// the process did not receive all of the bytes sent
+var ErrIncompleteStdinTransfer = errors.New("command did not receive all bytes sent to stdin")
+
+// ErrNotStarted is returned by operations which
+// require the process to have been started
+var ErrNotStarted = errors.New("process has not been started")
+
+// pumpDrainTimeout is the time allowed by Close for the pumps
+// to finish once the process has exited
+const pumpDrainTimeout = 100 * time.Millisecond
+
+// ErrAlreadyStarted is returned when starting a Launcher
+// which has already been started
+var ErrAlreadyStarted = errors.New("process has already been started")
+
+// ErrAlreadyClosed is returned when starting, or sending
+// stdin to, a Launcher which has been closed
+var ErrAlreadyClosed = errors.New("launcher has already been closed")
 
 // New creates a new instance of Launcher, initialising but not launching
 // the requested file as a child process.
 func New(ctx context.Context, file string, env []string, arg ...string) (*Launcher, error) {
+	return NewWithOptions(ctx, file, env, arg)
+}
+
+// NewWithOptions creates a new instance of Launcher in the same way as New,
+// with its optional behaviour configured by the supplied Options.
+func NewWithOptions(ctx context.Context, file string, env []string, args []string, opts ...Option) (*Launcher, error) {
+	var o options
+	for _, opt := range opts {
+		if err := opt(&o); err != nil {
+			return nil, err
+		}
+	}
+	if o.args != nil {
+		args = o.args
+	}
+	if o.env != nil {
+		env = o.env
+	}
+
 	if ctx == nil {
-		return nil, errMissingContext
+		return nil, newLaunchError(StageSetup, file, args, &o, ErrMissingContext)
 	}
-	myCtx, cancel := context.WithCancel(ctx)
+	myCtx, cancel := context.WithCancelCause(ctx)
+	o.correlate(ctx)
 
-	path, err := exec.LookPath(file)
-	if err != nil {
-		cancel()
-		return nil, err
+	path, err := o.resolve(file)
+	if err != nil && !o.lazyLookup {
+		cancel(nil)
+		return nil, newLaunchError(StageLookup, file, args, &o, err)
 	}
 
 	l := &Launcher{
 		file:   file,
 		path:   path,
+		spec:   Spec{File: file, Env: env, Args: args, Options: opts},
+		parent: ctx,
 		ctx:    myCtx,
 		cancel: cancel,
+		opts:   o,
+		done:   make(chan struct{}),
 	}
 
-	if err := l.initialise(env, arg...); err != nil {
-		return nil, err
+	if err := l.initialise(env, args...); err != nil {
+		l.Close()
+		return nil, l.launchError(StageSetup, err)
+	}
+
+	if l.path != "" {
+		if err := l.checkPolicies(PolicyAtNew); err != nil {
+			l.Close()
+			return nil, l.launchError(StageSetup, err)
+		}
 	}
 
 	return l, nil
 }
 
-// Launcher wraps exec.Cmd behaviours
+// Launcher wraps exec.Cmd behaviours.  A Launcher is safe for concurrent
+// use: its process is started at most once, with further calls to Start
+// or Run returning ErrAlreadyStarted, whilst it may be signalled, sent
+// stdin, cancelled and closed from any goroutine
 type Launcher struct {
-	file      string
-	path      string
-	ctx       context.Context
-	cancel    context.CancelFunc
-	cmd       *exec.Cmd
-	cmdWriter io.WriteCloser
-	cmdStdOut io.ReadCloser
-	cmdStdErr io.ReadCloser
+	file          string
+	path          string
+	spec          Spec
+	parent        context.Context
+	ctx           context.Context
+	cancel        context.CancelCauseFunc
+	termCause     error
+	opts          options
+	cmd           *exec.Cmd
+	cmdWriter     io.WriteCloser
+	cmdStdOut     io.ReadCloser
+	cmdStdErr     io.ReadCloser
+	childFiles    []*os.File
+	secretWriters []*os.File
+	pingWriter    *os.File
+	pongReader    *os.File
+	tempDir       string
+	script        string
+	shellLine     string
+	stdOutSource  io.ReadCloser
+	stdOutTaps    []io.Writer
+	stdErrSource  io.ReadCloser
+	stdErrTaps    []io.Writer
+	stdOutPumped  bool
+	stdErrPumped  bool
+	ring          *lineRing
+	combined      chan OutputChunk
+	outputFile    *rotatingFile
+	transcript    *transcript
+	stderrTail    *tailBuffer
+	view          *view
+	pumps         sync.WaitGroup
+	startedAt     time.Time
+	finishedAt    time.Time
+	counters      ioCounters
+	probeMatched  chan struct{}
+	releases      []func()
+	pidRecorded   bool
+	adopted       bool
+	mu            sync.Mutex
+	state         State
+	done          chan struct{}
+	waitErr       error
 }
 
 // GetFile returns the requested file details
@@ -55,49 +149,204 @@ func (l *Launcher) GetFile() string {
 	return l.file
 }
 
-// GetPath returns the fully qualified path identified for the file
+// GetPath returns the fully qualified path identified for the file,
+// which is empty until the process is started if WithLazyLookup is
+// set and the file could not be resolved when the Launcher was created
 func (l *Launcher) GetPath() string {
 	return l.path
 }
 
-// GetArgs returns the arguments supplied to create the instance
+// GetArgs returns the arguments supplied to create the instance,
+// with any secret arguments masked
 func (l *Launcher) GetArgs() []string {
-	return l.copyStringArray(l.cmd.Args[1:])
+	return l.copyStringArray(l.views().args)
 }
 
-// GetEnv returns the environment supplied to create the instance
+// GetEnv returns the environment supplied to create the instance,
+// with the values of any secret variables masked
 func (l *Launcher) GetEnv() []string {
-	return l.copyStringArray(l.cmd.Env)
+	return l.copyStringArray(l.views().env)
+}
+
+// RangeArgs calls f for each argument returned by GetArgs, stopping if
+// f returns false, without the allocation of a copy of the arguments
+func (l *Launcher) RangeArgs(f func(i int, arg string) bool) {
+	for i, arg := range l.views().args {
+		if !f(i, arg) {
+			return
+		}
+	}
+}
+
+// RangeEnv calls f for each variable returned by GetEnv, stopping if
+// f returns false, without the allocation of a copy of the environment
+func (l *Launcher) RangeEnv(f func(i int, kv string) bool) {
+	for i, kv := range l.views().env {
+		if !f(i, kv) {
+			return
+		}
+	}
+}
+
+// view is the masked arguments and environment of the command,
+// which must not be modified
+type view struct {
+	args []string
+	env  []string
+}
+
+// views returns the view of the command, building it on first use
+// after the command has been changed
+func (l *Launcher) views() *view {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	if l.view == nil {
+		v := &view{args: []string{}}
+		if len(l.cmd.Args) > 0 {
+			v.args = l.opts.maskArgs(l.copyStringArray(l.cmd.Args[1:]))
+		}
+		v.env = l.opts.maskEnv(withoutShimEnv(l.cmd.Env))
+		l.view = v
+	}
+	return l.view
 }
 
-// IsStarted returns true if Start() has been called successfully
+// IsStarted returns true if Start() has launched the process
 func (l *Launcher) IsStarted() bool {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
 	return l.cmd.Process != nil
 }
 
-// IsRunning returns true if the underlying process has started
-// and has not exited in some way
+// IsRunning returns true if the underlying process has started, has
+// not exited in some way and has not been cancelled.  A cancelled
+// process remains in StateRunning until it has been reaped
 func (l *Launcher) IsRunning() bool {
+	return l.State() == StateRunning && l.ctx.Err() == nil
+}
+
+// Pid returns the process id of the underlying process,
+// or 0 if it has not been started
+func (l *Launcher) Pid() int {
+	if !l.IsStarted() {
+		return 0
+	}
+	return l.cmd.Process.Pid
+}
+
+// ExitCode returns the exit code of the exited process, or -1
+// if the process has not exited or was terminated by a signal
+func (l *Launcher) ExitCode() int {
 	select {
-	case <-l.ctx.Done():
-		return false
+	case <-l.done:
+		return l.cmd.ProcessState.ExitCode()
 	default:
-		return l.IsStarted() && (l.cmd.ProcessState == nil)
+		return -1
 	}
 }
 
-// Close should be called to release all resources
-func (l *Launcher) Close() error {
-	var err error
+// StdOutReader returns the reader for the stdout of the process,
+// which remains readable after the process has exited, or which
+// returns io.EOF if the stdout of the process is not piped
+func (l *Launcher) StdOutReader() io.Reader {
+	l.mu.Lock()
+	defer l.mu.Unlock()
 
-	// Cancel the context for this instance
-	l.cancel()
+	if l.cmdStdOut == nil {
+		return eofReader{}
+	}
+	return l.cmdStdOut
+}
 
-	// Close pipe
+// StdErrReader returns the reader for the stderr of the process,
+// which remains readable after the process has exited, or which
+// returns io.EOF if the stderr of the process is not piped
+func (l *Launcher) StdErrReader() io.Reader {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	if l.cmdStdErr == nil {
+		return eofReader{}
+	}
+	return l.cmdStdErr
+}
+
+// Close should be called to release all resources.  A running process
+// is asked to terminate, allowing the grace period set by WithCloseGrace
+// before it is killed, and is reaped before the pipes are closed and any
+// temporary working directory removed.  The errors from releasing the
+// resources are joined
+func (l *Launcher) Close() error {
+	var errs []error
+
+	// Close stdin first, which ends many processes gracefully
 	if l.cmdWriter != nil {
-		err = l.cmdWriter.Close()
+		if err := l.cmdWriter.Close(); err != nil && !errors.Is(err, os.ErrClosed) {
+			errs = append(errs, err)
+		}
+	}
+
+	// Terminate and reap the process
+	if l.cmd != nil && l.IsStarted() {
+		if l.opts.closeGrace > 0 {
+			l.terminate(l.done, l.opts.closeGrace, ErrCancelled)
+		}
+		l.cancel(ErrCancelled)
+		<-l.done
+		l.drainPumps()
+	}
+	l.cancel(ErrCancelled)
+
+	// Release the output pipes, and those of unwritten secrets
+	l.mu.Lock()
+	closers := []io.Closer{l.cmdStdOut, l.cmdStdErr, l.stdOutSource, l.stdErrSource}
+	l.mu.Unlock()
+	if l.outputFile != nil {
+		closers = append(closers, l.outputFile)
+	}
+	for _, w := range l.secretWriters {
+		closers = append(closers, w)
+	}
+	if l.pingWriter != nil {
+		closers = append(closers, l.pingWriter, l.pongReader)
+	}
+	for _, c := range closers {
+		if c == nil {
+			continue
+		}
+		if err := c.Close(); err != nil && !errors.Is(err, os.ErrClosed) {
+			errs = append(errs, err)
+		}
+	}
+	l.closeChildFiles()
+
+	if err := l.removeScript(); err != nil {
+		errs = append(errs, err)
+	}
+	if err := l.removeTempWorkdir(); err != nil {
+		errs = append(errs, err)
+	}
+
+	l.mu.Lock()
+	l.state = StateClosed
+	l.mu.Unlock()
+
+	return l.launchError(StageCleanup, errors.Join(errs...))
+}
+
+// WithCloseGrace sets the time allowed for a running process to exit
+// after being asked to terminate by Close, before it is killed.  Without
+// it, Close kills the process immediately
+func WithCloseGrace(d time.Duration) Option {
+	return func(o *options) error {
+		if d <= 0 {
+			return errInvalidTimeout
+		}
+		o.closeGrace = d
+		return nil
 	}
-	return err
 }
 
 // copyStringArray replicates a string array
@@ -116,65 +365,508 @@ func (l *Launcher) initialise(env []string, arg ...string) error {
 	default:
 	}
 
-	l.cmd = exec.CommandContext(l.ctx, l.path, l.copyStringArray(arg)...)
-	l.cmd.Env = l.copyStringArray(env)
+	name := l.path
+	if name == "" {
+		name = l.file
+	}
+	l.cmd = exec.CommandContext(l.ctx, name, arg...)
+	l.cmd.Env = l.opts.environment(env)
+	l.correlationEnv()
+	if l.opts.killTree {
+		l.cmd.Cancel = l.KillTree
+	}
+
+	if err := l.inputPipe(); err != nil {
+		return err
+	}
 
-	pw, err := l.cmd.StdinPipe()
-	if err != nil {
+	if err := l.outputPipes(); err != nil {
+		return err
+	}
+
+	if err := l.makeWorkdir(); err != nil {
+		return err
+	}
+
+	if err := l.passListeners(); err != nil {
+		return err
+	}
+
+	if err := l.secretPipes(); err != nil {
+		return err
+	}
+	if err := l.heartbeatPipes(); err != nil {
+		return err
+	}
+	l.passExtraFiles()
+
+	if err := l.useShim(); err != nil {
+		return err
+	}
+
+	if p, ok := l.opts.readiness.(preparer); ok {
+		p.prepare(l)
+	}
+	l.prepareRing()
+	l.prepareCombined()
+	l.prepareSinks()
+	if err := l.prepareOutputFile(); err != nil {
 		return err
 	}
-	l.cmdWriter = pw
+	l.prepareTranscript()
+	l.prepareStderrTail()
+	l.countIO()
+
+	// Output observed or filtered by the Launcher is pumped into a
+	// buffer, so that it is seen regardless of when the caller reads
+	// it, with the process blocked if the caller falls too far behind,
+	// unless the oldest unread output may be discarded
+	filtered := len(l.outputFilters()) > 0
+	if l.cmdStdOut == nil && (filtered || len(l.stdOutTaps) > 0 || len(l.stdErrTaps) > 0) {
+		return errOutputNotPiped
+	}
+	if len(l.stdOutTaps) > 0 || filtered {
+		l.stdOutSource = l.cmdStdOut
+		l.cmdStdOut = newOutputBuffer(l.opts.lossyOutput)
+	}
+	if len(l.stdErrTaps) > 0 || filtered {
+		l.stdErrSource = l.cmdStdErr
+		l.cmdStdErr = newOutputBuffer(l.opts.lossyOutput)
+	}
+
+	return nil
+}
+
+// outputPipes connects the stdout and stderr of the process
+// to the readers of the Launcher
+func (l *Launcher) outputPipes() error {
+	switch {
+	case l.opts.discardOutput:
+		return nil
+	case l.opts.inheritStdio:
+		l.cmd.Stdout = os.Stdout
+		l.cmd.Stderr = os.Stderr
+		return nil
+	}
 
-	pr, err := l.cmd.StdoutPipe()
+	pr, err := l.outputPipe(&l.cmd.Stdout)
 	if err != nil {
 		return err
 	}
 	l.cmdStdOut = pr
 
-	pr, err = l.cmd.StderrPipe()
+	pr, err = l.outputPipe(&l.cmd.Stderr)
 	if err != nil {
 		return err
 	}
 	l.cmdStdErr = pr
-
 	return nil
 }
 
-// Start attempts to launch the underlying process
+// outputPipe creates a pipe whose write end is set as the output w of
+// the process, returning the read end.  Unlike the pipes of exec.Cmd,
+// the read end is not closed by Wait, so that output is not lost
+func (l *Launcher) outputPipe(w *io.Writer) (io.ReadCloser, error) {
+	pr, pw, err := os.Pipe()
+	if err != nil {
+		return nil, err
+	}
+	*w = pw
+	l.childFiles = append(l.childFiles, pw)
+	if err := l.resizePipe(pw); err != nil {
+		return nil, err
+	}
+	return pr, nil
+}
+
+// closeChildFiles closes the parent's copies of the files
+// passed to the process
+func (l *Launcher) closeChildFiles() {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	for _, f := range l.childFiles {
+		f.Close()
+	}
+	l.childFiles = nil
+}
+
+// tapStdOut adds a writer which observes all stdout of the process,
+// and must be called during initialise
+func (l *Launcher) tapStdOut(w io.Writer) {
+	l.stdOutTaps = append(l.stdOutTaps, w)
+}
+
+// tapStdErr adds a writer which observes all stderr of the process,
+// and must be called during initialise
+func (l *Launcher) tapStdErr(w io.Writer) {
+	l.stdErrTaps = append(l.stdErrTaps, w)
+}
+
+// pump copies output from the source, through the filters, into the
+// buffer read by the caller, passing it to the taps as it arrives.
+// Taps which buffer incomplete lines are flushed, and closable taps
+// closed, once the source is exhausted
+func (l *Launcher) pump(buf *pipeBuffer, source io.Reader, taps []io.Writer) {
+	defer l.pumps.Done()
+
+	var dst io.Writer = buf
+	if len(taps) > 0 {
+		dst = io.MultiWriter(append(taps[:len(taps):len(taps)], buf)...)
+	}
+	w, flush := filterChain(dst, l.outputFilters())
+	_, err := pooledCopy(w, source)
+	flush()
+	for _, w := range taps {
+		if f, ok := w.(interface{ Flush() }); ok {
+			f.Flush()
+		}
+		if c, ok := w.(io.Closer); ok {
+			c.Close()
+		}
+	}
+	buf.CloseWithError(err)
+}
+
+// startPumps starts pumping the output which is buffered, unless it is
+// already being pumped, and must be called with the lock held
+func (l *Launcher) startPumps() {
+	if l.stdOutSource != nil && !l.stdOutPumped {
+		l.stdOutPumped = true
+		l.pumps.Add(1)
+		buf, source, taps := l.cmdStdOut.(*pipeBuffer), l.stdOutSource, l.stdOutTaps
+		l.goLabelled("stdout-pump", func() { l.pump(buf, source, taps) })
+	}
+	if l.stdErrSource != nil && !l.stdErrPumped {
+		l.stdErrPumped = true
+		l.pumps.Add(1)
+		buf, source, taps := l.cmdStdErr.(*pipeBuffer), l.stdErrSource, l.stdErrTaps
+		l.goLabelled("stderr-pump", func() { l.pump(buf, source, taps) })
+	}
+}
+
+// drainPumps waits briefly for the output remaining in the pipes of
+// an exited process to be pumped, which never completes if the pipes
+// are held open by its descendants
+func (l *Launcher) drainPumps() {
+	drained := make(chan struct{})
+	go func() {
+		l.pumps.Wait()
+		close(drained)
+	}()
+
+	select {
+	case <-drained:
+	case <-time.After(pumpDrainTimeout):
+	}
+}
+
+// wait reaps the process once it exits, recording the result
+func (l *Launcher) wait() {
+	l.waitErr = l.exitError(waitChild(l.cmd))
+	if _, ok := l.waitErr.(*ExitError); ok {
+		l.waitErr = l.launchError(StageRuntime, l.waitErr)
+	} else {
+		l.waitErr = l.launchError(StageWait, l.waitErr)
+	}
+	l.exited(l.waitErr)
+	l.recordExit(l.waitErr)
+	l.releaseAll()
+	l.removeScript()
+	if l.opts.breaker != nil && l.ctx.Err() == nil {
+		l.opts.breaker.record(l.waitErr)
+	}
+	close(l.done)
+}
+
+// releaseAll releases, in reverse order, the resources
+// held while the process is running
+func (l *Launcher) releaseAll() {
+	for i := len(l.releases) - 1; i >= 0; i-- {
+		l.releases[i]()
+	}
+	l.releases = nil
+}
+
+// copyOutput copies the stdout and stderr of the process to the
+// writers until both pipes are closed, discarding output if a
+// writer is nil
+func (l *Launcher) copyOutput(stdout, stderr io.Writer) {
+	var wg sync.WaitGroup
+	cp := func(w io.Writer, r io.Reader) {
+		defer wg.Done()
+		if w == nil {
+			w = io.Discard
+		}
+		copyStream(w, r)
+	}
+
+	wg.Add(2)
+	outReader, errReader := l.StdOutReader(), l.StdErrReader()
+	l.goLabelled("stdout-copy", func() { cp(stdout, outReader) })
+	l.goLabelled("stderr-copy", func() { cp(stderr, errReader) })
+	wg.Wait()
+}
+
+// terminate asks the process to exit, and kills it if it has not
+// done so by the end of the grace period, recording the cause of its
+// termination.  done must be closed once the process has been waited upon
+func (l *Launcher) terminate(done <-chan struct{}, grace time.Duration, cause error) {
+	select {
+	case <-done:
+		return
+	default:
+	}
+
+	l.mu.Lock()
+	if l.termCause == nil {
+		l.termCause = cause
+	}
+	l.mu.Unlock()
+
+	if err := l.Signal(syscall.SIGTERM); err != nil {
+		l.CancelWithCause(cause)
+	}
+
+	select {
+	case <-done:
+	case <-time.After(grace):
+		l.CancelWithCause(cause)
+		<-done
+	}
+}
+
+// Start attempts to launch the underlying process.  If a readiness
+// Probe has been configured, Start only returns successfully once
+// the Probe has passed
 func (l *Launcher) Start() error {
+	return l.StartAndWaitReady(l.ctx)
+}
+
+// StartAndWaitReady attempts to launch the underlying process, and then
+// waits for any configured readiness Probe to pass, giving up when the
+// context ends or the process exits
+func (l *Launcher) StartAndWaitReady(ctx context.Context) error {
+	if ctx == nil {
+		return l.launchError(StageStart, ErrMissingContext)
+	}
+	if err := l.start(ctx); err != nil {
+		return err
+	}
+	return l.launchError(StageStart, l.waitReady(ctx))
+}
+
+// start launches the underlying process, unless it has already been
+// started or another goroutine is starting it
+func (l *Launcher) start(ctx context.Context) error {
+	if l.adopted {
+		return errAdopted
+	}
+
+	l.mu.Lock()
+	if l.state == StateClosed {
+		l.mu.Unlock()
+		return l.launchError(StageStart, ErrAlreadyClosed)
+	}
+	if !l.state.canStart() || l.cmd.Process != nil {
+		l.mu.Unlock()
+		return l.launchError(StageStart, ErrAlreadyStarted)
+	}
+	l.setState(StateStarting)
+	l.mu.Unlock()
+
+	err := l.launch(ctx)
+
+	l.mu.Lock()
+	if l.state == StateStarting {
+		l.setState(StateFailed)
+	}
+	l.mu.Unlock()
+
+	return l.launchError(StageStart, err)
+}
+
+// admit makes the checks, and acquires the resources, required before
+// the process is started, whether attached or detached, releasing the
+// resources if a check fails.  Any Limiter is waited upon until the
+// context ends
+func (l *Launcher) admit(ctx context.Context) error {
 	select {
 	case <-l.ctx.Done():
 		return l.ctx.Err()
 	default:
 	}
-	return l.cmd.Start()
+
+	if err := l.resolveLazily(); err != nil {
+		return err
+	}
+	l.setShellCommandLine()
+	l.expandReferences()
+
+	if err := l.checkPolicies(PolicyAtStart); err != nil {
+		return err
+	}
+
+	if l.opts.breaker != nil {
+		if err := l.opts.breaker.allow(); err != nil {
+			return err
+		}
+	}
+
+	if l.opts.lockPath != "" {
+		f, err := lockFile(l.opts.lockPath)
+		if err != nil {
+			return err
+		}
+		l.releases = append(l.releases, func() { f.Close() })
+	}
+
+	if l.opts.pidFile != "" {
+		remove, err := claimPIDFile(l.opts.pidFile)
+		if err != nil {
+			l.releaseAll()
+			return err
+		}
+		// Once the pid of the process is recorded, the file may be claimed
+		// again by another Launcher of this process after it exits
+		l.releases = append(l.releases, func() {
+			if !l.pidRecorded {
+				remove()
+			}
+		})
+	}
+
+	if l.opts.limiter != nil {
+		release, err := l.opts.limiter.acquire(ctx)
+		if err != nil {
+			l.releaseAll()
+			return err
+		}
+		l.releases = append(l.releases, release)
+	}
+	return nil
+}
+
+// launch starts the underlying process and the goroutines servicing
+// it, once admitted by any Limiter, which is waited upon until the
+// context ends
+func (l *Launcher) launch(ctx context.Context) error {
+	if err := l.admit(ctx); err != nil {
+		return err
+	}
+
+	l.mu.Lock()
+	l.startedAt = time.Now()
+	err := startChild(l.cmd)
+	if err == nil && l.opts.pidFile != "" {
+		err = l.recordPID()
+	}
+	if err == nil {
+		l.setState(StateRunning)
+	} else {
+		l.startedAt = time.Time{}
+	}
+	l.mu.Unlock()
+	l.recordStart(err)
+	if err != nil {
+		l.releaseAll()
+		if l.opts.breaker != nil {
+			l.opts.breaker.record(err)
+		}
+		return err
+	}
+	l.closeChildFiles()
+	l.writeSecrets()
+	if l.opts.heartbeat != nil {
+		l.goLabelled("heartbeat", l.sendHeartbeats)
+	}
+	if l.opts.idleTimeout > 0 {
+		l.goLabelled("idle-watch", l.watchIdle)
+	}
+	if l.transcript != nil {
+		l.transcript.begin(l.startedAt)
+	}
+	if l.opts.stdinBytes != nil {
+		l.goLabelled("stdin-feed", func() { l.feedStdIn(l.opts.stdinBytes) })
+	}
+	if l.opts.stdinFS != nil {
+		l.goLabelled("stdin-feed", l.feedStdInFS)
+	}
+
+	l.mu.Lock()
+	l.startPumps()
+	l.mu.Unlock()
+	l.goLabelled("wait", l.wait)
+
+	return nil
+}
+
+// recordPID replaces the claim on the PID file with the id of the started
+// process, killing and reaping the process if it cannot be written, so
+// that the Launcher is left unstarted, and must be called with the lock held
+func (l *Launcher) recordPID() error {
+	remove, err := writePIDFile(l.opts.pidFile, l.cmd.Process.Pid)
+	if err != nil {
+		l.cmd.Process.Kill()
+		waitChild(l.cmd)
+		l.cmd.Process = nil
+		return err
+	}
+	l.pidRecorded = true
+	l.releases = append(l.releases, remove)
+	return nil
 }
 
 // Run attempts to launch the underlying process
 // and waits until it completes
 func (l *Launcher) Run() error {
-	select {
-	case <-l.ctx.Done():
-		return l.ctx.Err()
-	default:
+	if err := l.Start(); err != nil {
+		return err
 	}
-	return l.cmd.Run()
+	return l.Wait()
 }
 
-// Cancel ends processing
+// Cancel ends processing, killing any running process
+// with ErrCancelled as the cause
 func (l *Launcher) Cancel() {
-	l.cancel()
+	l.cancel(ErrCancelled)
+}
+
+// CancelWithCause ends processing in the same way as Cancel, recording
+// the cause, which is returned by Cause and included in the error of
+// Wait if the process is killed
+func (l *Launcher) CancelWithCause(cause error) {
+	l.cancel(cause)
 }
 
 // SendStdIn passes the supplied bytes to the stdin of the
 // underlying process, provided it is still running
 func (l *Launcher) SendStdIn(b []byte) error {
-	n, err := l.cmdWriter.Write(b)
+	n, err := l.Write(b)
 	if err != nil {
 		return err
 	}
 	if n != len(b) {
-		return errIncompleteStdIntransfer
+		return l.launchError(StageRuntime, ErrIncompleteStdinTransfer)
 	}
 	return nil
 }
+
+// Signal sends the signal to the underlying process
+func (l *Launcher) Signal(sig os.Signal) error {
+	if !l.IsStarted() {
+		return l.launchError(StageRuntime, ErrNotStarted)
+	}
+	return l.cmd.Process.Signal(sig)
+}
+
+// Wait waits for the started process to exit, returning the same
+// result as exec.Cmd.Wait.  The process is reaped as soon as it exits,
+// so Wait may be called any number of times, from any goroutine
+func (l *Launcher) Wait() error {
+	if !l.IsStarted() {
+		return l.launchError(StageWait, ErrNotStarted)
+	}
+	<-l.done
+	return l.waitErr
+}
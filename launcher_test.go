@@ -6,8 +6,10 @@ import (
 	"crypto/sha256"
 	"errors"
 	"fmt"
+	"io"
 	"os/exec"
 	"strings"
+	"sync"
 	"testing"
 	"time"
 )
@@ -15,7 +17,7 @@ import (
 func TestLauncherWithNilCtx(t *testing.T) {
 
 	_, err := New(nil, "sh", []string{}, "-c", "cat", "<<!")
-	if err != errMissingContext {
+	if !errors.Is(err, ErrMissingContext) {
 		t.Fatal(err)
 	}
 }
@@ -74,7 +76,7 @@ func TestLauncherWithCtxCancelBeforeNew(t *testing.T) {
 	cancel()
 
 	_, err := New(ctx, "sh", []string{}, "-c", "cat", "<<!")
-	if err != context.Canceled {
+	if !errors.Is(err, context.Canceled) {
 		t.Fatal(err)
 	}
 }
@@ -92,7 +94,7 @@ func TestLauncherWithCtxCancelAfterNew(t *testing.T) {
 	cancel()
 
 	err = l.Start()
-	if err != context.Canceled {
+	if !errors.Is(err, context.Canceled) {
 		t.Fatal(err)
 	}
 }
@@ -269,3 +271,245 @@ func TestLauncherRunWithCtxCancelAfterNew(t *testing.T) {
 	}
 
 }
+
+func TestLauncherWaitBeforeRead(t *testing.T) {
+
+	foo := "foo"
+
+	l, err := New(context.Background(), "echo", []string{}, foo)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer l.Close()
+
+	if err := l.Wait(); !errors.Is(err, ErrNotStarted) {
+		t.Fatal(err)
+	}
+
+	if err := l.Start(); err != nil {
+		t.Fatal(err)
+	}
+
+	for i := 0; i < 2; i++ {
+		if err := l.Wait(); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	if l.IsRunning() {
+		t.Fatal("still running")
+	}
+
+	// Output remains available after the process has been reaped
+	var b = make([]byte, len(foo))
+	_, err = l.StdOutReader().Read(b)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if string(b) != foo {
+		t.Fatalf("invalid response - expected %q, got %q\n", foo, string(b))
+	}
+}
+
+func TestLauncherCloseReaps(t *testing.T) {
+
+	l, err := New(context.Background(), "sleep", nil, "10")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if err := l.Start(); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := l.Close(); err != nil {
+		t.Fatal(err)
+	}
+
+	if l.IsRunning() || processAlive(l.Pid()) {
+		t.Fatal("expected process to have been reaped")
+	}
+
+	if err := l.Close(); err != nil {
+		t.Fatalf("expected repeated Close to succeed, got %v\n", err)
+	}
+}
+
+func TestLauncherCloseGrace(t *testing.T) {
+
+	l, err := NewWithOptions(context.Background(), "sh", nil, []string{"-c", "trap 'exit 0' TERM; sleep 10 & wait"}, WithCloseGrace(5*time.Second))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if err := l.Start(); err != nil {
+		t.Fatal(err)
+	}
+	// Allow the shell to install its trap
+	time.Sleep(100 * time.Millisecond)
+
+	if err := l.Close(); err != nil {
+		t.Fatal(err)
+	}
+
+	if l.ExitCode() != 0 {
+		t.Fatalf("expected graceful exit, got %v\n", l.ExitCode())
+	}
+}
+
+func TestLauncherConcurrentUse(t *testing.T) {
+
+	l, err := New(context.Background(), "cat", nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var started, already int32
+	var mu sync.Mutex
+	var wg sync.WaitGroup
+	for i := 0; i < 10; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+
+			err := l.Start()
+
+			mu.Lock()
+			defer mu.Unlock()
+			switch {
+			case err == nil:
+				started++
+			case errors.Is(err, ErrAlreadyStarted):
+				already++
+			default:
+				t.Error(err)
+			}
+		}()
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			l.IsRunning()
+			l.Pid()
+			l.SendStdIn([]byte("x\n"))
+		}()
+	}
+	wg.Wait()
+
+	if started != 1 || already != 9 {
+		t.Fatalf("expected a single start, got %v started and %v already started\n", started, already)
+	}
+
+	wg.Add(2)
+	go func() {
+		defer wg.Done()
+		l.Cancel()
+	}()
+	go func() {
+		defer wg.Done()
+		l.Close()
+	}()
+	wg.Wait()
+
+	if err := l.Run(); !errors.Is(err, ErrAlreadyClosed) {
+		t.Fatalf("expected ErrAlreadyClosed, got %v\n", err)
+	}
+}
+
+func BenchmarkGetArgs(b *testing.B) {
+
+	l, err := NewWithOptions(context.Background(), "echo", []string{"A=1", "B=2", "C=3"}, []string{"a", "b", "c", "d"}, WithSecretArgIndexes(1))
+	if err != nil {
+		b.Fatal(err)
+	}
+	defer l.Close()
+
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		l.GetArgs()
+		l.GetEnv()
+	}
+}
+
+func BenchmarkRangeArgs(b *testing.B) {
+
+	l, err := NewWithOptions(context.Background(), "echo", []string{"A=1", "B=2", "C=3"}, []string{"a", "b", "c", "d"}, WithSecretArgIndexes(1))
+	if err != nil {
+		b.Fatal(err)
+	}
+	defer l.Close()
+
+	n := 0
+	count := func(int, string) bool {
+		n++
+		return true
+	}
+
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		l.RangeArgs(count)
+		l.RangeEnv(count)
+	}
+}
+
+func BenchmarkNew(b *testing.B) {
+
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		l, err := New(context.Background(), "echo", []string{"A=1", "B=2"}, "a", "b")
+		if err != nil {
+			b.Fatal(err)
+		}
+		l.Close()
+	}
+}
+
+func BenchmarkPump(b *testing.B) {
+
+	l, err := New(context.Background(), "echo", nil)
+	if err != nil {
+		b.Fatal(err)
+	}
+	defer l.Close()
+
+	input := bytes.Repeat([]byte("0123456789abcdef\n"), 1024)
+
+	b.ReportAllocs()
+	b.SetBytes(int64(len(input)))
+	for i := 0; i < b.N; i++ {
+		buf := newPipeBuffer()
+		l.pumps.Add(1)
+		// The source hides WriteTo, as do the pipes from the process
+		go l.pump(buf, struct{ io.Reader }{bytes.NewReader(input)}, nil)
+		io.Copy(io.Discard, buf)
+	}
+}
+
+func TestLauncherRangeArgs(t *testing.T) {
+
+	l, err := NewWithOptions(context.Background(), "echo", []string{"A=1", "B=2"}, []string{"a", "b", "c"}, WithSecretArgIndexes(1))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer l.Close()
+
+	var args, env []string
+	l.RangeArgs(func(i int, arg string) bool {
+		args = append(args, arg)
+		return i < 1
+	})
+	l.RangeEnv(func(i int, kv string) bool {
+		env = append(env, kv)
+		return true
+	})
+
+	if strings.Join(args, ",") != "a,"+redactedValue || strings.Join(env, ",") != "A=1,B=2" {
+		t.Fatalf("unexpected args %v and env %v\n", args, env)
+	}
+
+	// The copies returned by the accessors do not share the view
+	l.GetArgs()[0] = "changed"
+	if l.GetArgs()[0] != "a" {
+		t.Fatal("expected GetArgs to return a copy")
+	}
+}
@@ -0,0 +1,63 @@
+package launcher
+
+import (
+	"errors"
+	"fmt"
+)
+
+// LaunchStage identifies the stage in the life of a process at which
+// a LaunchError occurred
+type LaunchStage string
+
+const (
+	// StageLookup is the resolution of the file to an executable
+	StageLookup LaunchStage = "lookup"
+	// StageSetup is the preparation of the process and its pipes
+	// when the Launcher is created
+	StageSetup LaunchStage = "setup"
+	// StageStart is the launch of the process, including the checks
+	// made beforehand and the wait for any readiness Probe
+	StageStart LaunchStage = "start"
+	// StageRuntime is the unsuccessful exit of the process
+	StageRuntime LaunchStage = "runtime"
+	// StageWait is the reaping of the process
+	StageWait LaunchStage = "wait"
+	// StageCleanup is the release of resources by Close
+	StageCleanup LaunchStage = "cleanup"
+)
+
+// LaunchError records the stage at which a Launcher failed, and
+// the command concerned, wrapping the error which caused the failure
+type LaunchError struct {
+	Stage LaunchStage
+	// Path is the resolved path of the command, or the file
+	// if it could not be resolved
+	Path string
+	// Args are masked and redacted as for String
+	Args []string
+	Err  error
+}
+
+func (e *LaunchError) Error() string {
+	return fmt.Sprintf("%s: %s: %v", e.Path, e.Stage, e.Err)
+}
+
+func (e *LaunchError) Unwrap() error {
+	return e.Err
+}
+
+// newLaunchError wraps err in a *LaunchError, unless it is nil or
+// already a *LaunchError
+func newLaunchError(stage LaunchStage, path string, args []string, o *options, err error) error {
+	var launchErr *LaunchError
+	if err == nil || errors.As(err, &launchErr) {
+		return err
+	}
+	args = o.redact(o.maskArgs(append([]string{}, args...)))
+	return &LaunchError{Stage: stage, Path: path, Args: args, Err: err}
+}
+
+// launchError wraps err in a *LaunchError describing the Launcher
+func (l *Launcher) launchError(stage LaunchStage, err error) error {
+	return newLaunchError(stage, l.displayPath(), l.spec.Args, &l.opts, err)
+}
@@ -0,0 +1,104 @@
+package launcher
+
+import (
+	"context"
+	"errors"
+	"os/exec"
+	"testing"
+)
+
+func TestLaunchErrorStages(t *testing.T) {
+
+	stageOf := func(err error) LaunchStage {
+		var launchErr *LaunchError
+		if !errors.As(err, &launchErr) {
+			t.Fatalf("expected a LaunchError, got %v\n", err)
+		}
+		return launchErr.Stage
+	}
+
+	_, err := New(context.Background(), "no-such-command-xyz", nil)
+	if stageOf(err) != StageLookup || !errors.Is(err, exec.ErrNotFound) {
+		t.Fatalf("unexpected error %v\n", err)
+	}
+
+	l, err := NewWithOptions(context.Background(), "no-such-command-xyz", nil, nil, WithLazyLookup())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := l.Start(); stageOf(err) != StageLookup {
+		t.Fatalf("unexpected error %v\n", err)
+	}
+	l.Close()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	if _, err := New(ctx, "echo", nil); stageOf(err) != StageSetup {
+		t.Fatalf("unexpected error %v\n", err)
+	}
+
+	l, err = New(context.Background(), "sh", nil, "-c", "exit 3")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer l.Close()
+
+	err = l.Run()
+	if stageOf(err) != StageRuntime {
+		t.Fatalf("unexpected error %v\n", err)
+	}
+	var exitErr *ExitError
+	if !errors.As(err, &exitErr) || exitErr.ExitCode != 3 {
+		t.Fatalf("expected an ExitError, got %v\n", err)
+	}
+}
+
+func TestLaunchErrorIdentity(t *testing.T) {
+
+	opts := []Option{WithSecretArgIndexes(1), WithRedactor(RedactFlags("token"))}
+	_, err := NewWithOptions(context.Background(), "no-such-command-xyz", nil, []string{"--token", "abc", "def"}, opts...)
+
+	var launchErr *LaunchError
+	if !errors.As(err, &launchErr) {
+		t.Fatalf("expected a LaunchError, got %v\n", err)
+	}
+	if launchErr.Path != "no-such-command-xyz" {
+		t.Fatalf("unexpected path %v\n", launchErr.Path)
+	}
+	for _, a := range launchErr.Args {
+		if a == "abc" {
+			t.Fatalf("expected the secret to be hidden, got %v\n", launchErr.Args)
+		}
+	}
+}
+
+func TestSentinelErrors(t *testing.T) {
+
+	l, err := New(context.Background(), "cat", nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if err := l.Wait(); !errors.Is(err, ErrNotStarted) {
+		t.Fatalf("expected ErrNotStarted, got %v\n", err)
+	}
+	if err := l.StartAndWaitReady(nil); !errors.Is(err, ErrMissingContext) {
+		t.Fatalf("expected ErrMissingContext, got %v\n", err)
+	}
+	if err := l.Start(); err != nil {
+		t.Fatal(err)
+	}
+	if err := l.Start(); !errors.Is(err, ErrAlreadyStarted) {
+		t.Fatalf("expected ErrAlreadyStarted, got %v\n", err)
+	}
+	l.Close()
+
+	err = l.SendStdIn([]byte("foo"))
+	if !errors.Is(err, ErrAlreadyClosed) {
+		t.Fatalf("expected ErrAlreadyClosed, got %v\n", err)
+	}
+	var launchErr *LaunchError
+	if !errors.As(err, &launchErr) || launchErr.Path != l.GetPath() {
+		t.Fatalf("expected the command in the error, got %v\n", err)
+	}
+}
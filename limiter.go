@@ -0,0 +1,116 @@
+package launcher
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"time"
+)
+
+var errMissingLimiter = errors.New("limiter must be provided")
+var errInvalidLimit = errors.New("limit must be positive")
+
+// Limiter restricts the launching of processes by the Launchers that
+// share it, so that bursts of launches are queued rather than forking
+// many processes at once.  Launchers waiting for the Limiter give up
+// when the context passed to StartAndWaitReady ends
+type Limiter struct {
+	slots    chan struct{}
+	interval time.Duration
+	mu       sync.Mutex
+	next     time.Time
+}
+
+// LimiterOption configures a Limiter
+type LimiterOption func(lim *Limiter) error
+
+// WithMaxConcurrent limits the number of processes which may be running
+// at once, with further launches waiting until a process has exited
+func WithMaxConcurrent(n int) LimiterOption {
+	return func(lim *Limiter) error {
+		if n <= 0 {
+			return errInvalidLimit
+		}
+		lim.slots = make(chan struct{}, n)
+		return nil
+	}
+}
+
+// WithLaunchRate limits the number of processes launched per second
+func WithLaunchRate(perSecond float64) LimiterOption {
+	return func(lim *Limiter) error {
+		if perSecond <= 0 {
+			return errInvalidLimit
+		}
+		lim.interval = time.Duration(float64(time.Second) / perSecond)
+		return nil
+	}
+}
+
+// NewLimiter creates a new Limiter, which is unrestricted
+// unless configured by the supplied options
+func NewLimiter(opts ...LimiterOption) (*Limiter, error) {
+	lim := &Limiter{}
+	for _, opt := range opts {
+		if err := opt(lim); err != nil {
+			return nil, err
+		}
+	}
+	return lim, nil
+}
+
+// WithLimiter requires the Launcher to be admitted by the Limiter
+// before its process is started
+func WithLimiter(lim *Limiter) Option {
+	return func(o *options) error {
+		if lim == nil {
+			return errMissingLimiter
+		}
+		o.limiter = lim
+		return nil
+	}
+}
+
+// acquire waits until a launch is admitted, returning the function
+// which must be called once the launched process has exited
+func (lim *Limiter) acquire(ctx context.Context) (func(), error) {
+	if lim.slots != nil {
+		select {
+		case lim.slots <- struct{}{}:
+		case <-ctx.Done():
+			return nil, ctx.Err()
+		}
+	}
+
+	var once sync.Once
+	release := func() {
+		once.Do(func() {
+			if lim.slots != nil {
+				<-lim.slots
+			}
+		})
+	}
+
+	if lim.interval > 0 {
+		lim.mu.Lock()
+		at := lim.next
+		if now := time.Now(); at.Before(now) {
+			at = now
+		}
+		lim.next = at.Add(lim.interval)
+		lim.mu.Unlock()
+
+		if d := time.Until(at); d > 0 {
+			timer := time.NewTimer(d)
+			defer timer.Stop()
+			select {
+			case <-timer.C:
+			case <-ctx.Done():
+				release()
+				return nil, ctx.Err()
+			}
+		}
+	}
+
+	return release, nil
+}
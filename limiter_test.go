@@ -0,0 +1,114 @@
+package launcher
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"testing"
+	"time"
+)
+
+func TestLimiterMaxConcurrent(t *testing.T) {
+
+	lim, err := NewLimiter(WithMaxConcurrent(2))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	start := time.Now()
+
+	var wg sync.WaitGroup
+	for i := 0; i < 4; i++ {
+		l, err := NewWithOptions(context.Background(), "sleep", nil, []string{"0.3"}, WithLimiter(lim))
+		if err != nil {
+			t.Fatal(err)
+		}
+		defer l.Close()
+
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			if err := l.Run(); err != nil {
+				t.Error(err)
+			}
+		}()
+	}
+	wg.Wait()
+
+	if d := time.Since(start); d < 600*time.Millisecond {
+		t.Fatalf("expected launches to be queued, took %v\n", d)
+	}
+}
+
+func TestLimiterLaunchRate(t *testing.T) {
+
+	lim, err := NewLimiter(WithLaunchRate(10))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	start := time.Now()
+	for i := 0; i < 4; i++ {
+		l, err := NewWithOptions(context.Background(), "echo", nil, nil, WithLimiter(lim))
+		if err != nil {
+			t.Fatal(err)
+		}
+		if err := l.Run(); err != nil {
+			t.Fatal(err)
+		}
+		l.Close()
+	}
+
+	if d := time.Since(start); d < 300*time.Millisecond {
+		t.Fatalf("expected launches to be rate limited, took %v\n", d)
+	}
+}
+
+func TestLimiterContext(t *testing.T) {
+
+	lim, err := NewLimiter(WithMaxConcurrent(1))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	first, err := NewWithOptions(context.Background(), "sleep", nil, []string{"10"}, WithLimiter(lim))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer first.Close()
+	if err := first.Start(); err != nil {
+		t.Fatal(err)
+	}
+
+	second, err := NewWithOptions(context.Background(), "echo", nil, nil, WithLimiter(lim))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer second.Close()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 100*time.Millisecond)
+	defer cancel()
+	if err := second.StartAndWaitReady(ctx); !errors.Is(err, context.DeadlineExceeded) {
+		t.Fatalf("expected DeadlineExceeded, got %v\n", err)
+	}
+
+	first.Cancel()
+	first.Wait()
+
+	if err := second.Run(); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestLimiterInvalid(t *testing.T) {
+
+	if _, err := NewLimiter(WithMaxConcurrent(0)); err != errInvalidLimit {
+		t.Fatalf("expected errInvalidLimit, got %v\n", err)
+	}
+	if _, err := NewLimiter(WithLaunchRate(-1)); err != errInvalidLimit {
+		t.Fatalf("expected errInvalidLimit, got %v\n", err)
+	}
+	if _, err := NewWithOptions(context.Background(), "echo", nil, nil, WithLimiter(nil)); err != errMissingLimiter {
+		t.Fatalf("expected errMissingLimiter, got %v\n", err)
+	}
+}
@@ -0,0 +1,32 @@
+package launcher
+
+import (
+	"fmt"
+	"io"
+	"time"
+)
+
+// WithLineTimestamps prefixes each line of the stdout and stderr of the
+// process with the time since it was started, in the form
+// "[    1.234567] ", taken from the monotonic clock when the first
+// byte of the line is read.  The prefix is seen by all readers of the
+// output, so that the slow phases of a process may be found afterwards
+func WithLineTimestamps() Option {
+	return func(o *options) error {
+		o.lineTimestamps = true
+		return nil
+	}
+}
+
+// timestampLines is an outputFilter prefixing each line
+// with the time since the process was started
+func (l *Launcher) timestampLines(w io.Writer) io.Writer {
+	start := l.startedAt
+	return &prefixWriter{
+		w: w,
+		prefix: func() []byte {
+			d := time.Since(start)
+			return fmt.Appendf(nil, "[%5d.%06d] ", d/time.Second, (d%time.Second)/time.Microsecond)
+		},
+	}
+}
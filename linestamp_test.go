@@ -0,0 +1,54 @@
+package launcher
+
+import (
+	"context"
+	"io"
+	"regexp"
+	"strconv"
+	"strings"
+	"testing"
+)
+
+func TestLineTimestamps(t *testing.T) {
+
+	script := "echo one; sleep 0.2; printf 'two\\nthr'; sleep 0.2; echo ee"
+
+	l, err := NewWithOptions(context.Background(), "sh", nil, []string{"-c", script}, WithLineTimestamps())
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer l.Close()
+
+	if err := l.Start(); err != nil {
+		t.Fatal(err)
+	}
+	b, err := io.ReadAll(l.StdOutReader())
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	lines := strings.Split(strings.TrimSuffix(string(b), "\n"), "\n")
+	expected := []string{"one", "two", "three"}
+	if len(lines) != len(expected) {
+		t.Fatalf("expected %v lines, got %q\n", len(expected), b)
+	}
+
+	re := regexp.MustCompile(`^\[ *(\d+\.\d{6})\] (.*)$`)
+	var last float64
+	for i, line := range lines {
+		m := re.FindStringSubmatch(line)
+		if m == nil || m[2] != expected[i] {
+			t.Fatalf("unexpected line %q\n", line)
+		}
+		secs, _ := strconv.ParseFloat(m[1], 64)
+		if secs < last {
+			t.Fatalf("timestamps out of order in %q\n", b)
+		}
+		last = secs
+	}
+
+	// The third line is stamped when its first byte arrives
+	if last < 0.2 || last >= 0.4 {
+		t.Fatalf("unexpected timestamp %v for the final line\n", last)
+	}
+}
@@ -0,0 +1,62 @@
+package launcher
+
+import (
+	"errors"
+	"net"
+	"os"
+	"strconv"
+)
+
+var errMissingListener = errors.New("listener must be provided")
+
+var errUnsupportedListener = errors.New("listener does not provide its file")
+
+// listenFDsStart is the first descriptor of the sockets passed
+// under the systemd socket activation convention
+const listenFDsStart = 3
+
+// fileListener is a net.Listener whose socket can be passed
+// to a process, such as a *net.TCPListener or *net.UnixListener
+type fileListener interface {
+	File() (*os.File, error)
+}
+
+// WithListener passes the listening socket to the process following the
+// socket activation convention of systemd, so that the process serves
+// connections on a port bound by the parent, which may be handed from
+// one process to the next without refusing connections.  The sockets are
+// passed as descriptors from 3 in the order the options are given, with
+// LISTEN_FDS set to their number and LISTEN_PID to the pid of the process,
+// which is set by re-executing the current program as a shim, in the same
+// way as the restrictions of WithSeccomp.  The listener remains owned by
+// the caller.  Socket activation is not supported on Windows
+func WithListener(ln net.Listener) Option {
+	return func(o *options) error {
+		if ln == nil {
+			return errMissingListener
+		}
+		if _, ok := ln.(fileListener); !ok {
+			return errUnsupportedListener
+		}
+		o.listeners = append(o.listeners, ln)
+		return nil
+	}
+}
+
+// passListeners passes a duplicate of each listening socket to the
+// process, and must be called before any other extra files are added
+func (l *Launcher) passListeners() error {
+	if len(l.opts.listeners) == 0 {
+		return nil
+	}
+	for _, ln := range l.opts.listeners {
+		f, err := ln.(fileListener).File()
+		if err != nil {
+			return err
+		}
+		l.childFiles = append(l.childFiles, f)
+		l.cmd.ExtraFiles = append(l.cmd.ExtraFiles, f)
+	}
+	l.cmd.Env = setEnv(l.cmd.Env, []string{"LISTEN_FDS"}, strconv.Itoa(len(l.opts.listeners)))
+	return nil
+}
@@ -0,0 +1,57 @@
+package launcher
+
+import (
+	"context"
+	"net"
+	"strconv"
+	"strings"
+	"testing"
+)
+
+func TestWithListener(t *testing.T) {
+
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer ln.Close()
+
+	l, err := NewWithOptions(context.Background(), "sh", nil,
+		[]string{"-c", `echo $LISTEN_FDS $LISTEN_PID $$; readlink /proc/self/fd/3`},
+		WithListener(ln))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer l.Close()
+
+	res, err := l.RunCaptured()
+	if err != nil {
+		t.Fatalf("%v: %s\n", err, res.Stderr)
+	}
+
+	lines := strings.Split(strings.TrimSpace(string(res.Stdout)), "\n")
+	if len(lines) != 2 {
+		t.Fatalf("unexpected output %q\n", res.Stdout)
+	}
+	fields := strings.Fields(lines[0])
+	if len(fields) != 3 || fields[0] != "1" || fields[1] != fields[2] || fields[1] != strconv.Itoa(l.Pid()) {
+		t.Fatalf("unexpected socket activation environment %q\n", lines[0])
+	}
+	if !strings.HasPrefix(lines[1], "socket:") {
+		t.Fatalf("expected descriptor 3 to be a socket, got %q\n", lines[1])
+	}
+
+	// The listener remains usable by the parent
+	conn, err := net.Dial("tcp", ln.Addr().String())
+	if err != nil {
+		t.Fatal(err)
+	}
+	conn.Close()
+}
+
+func TestWithListenerInvalid(t *testing.T) {
+
+	if _, err := NewWithOptions(context.Background(), "true", nil, nil, WithListener(nil)); err == nil {
+		t.Fatal("expected error for nil listener")
+	}
+}
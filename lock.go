@@ -0,0 +1,23 @@
+package launcher
+
+import "errors"
+
+// ErrLocked is returned when starting a Launcher whose
+// exclusive lock is held by another instance
+var ErrLocked = errors.New("exclusive lock is held by another instance")
+
+var errMissingPath = errors.New("path must be provided")
+
+// WithExclusiveLock prevents the Launcher from starting its process while
+// another instance, possibly in another OS process, holds the lock file at
+// path.  The lock is held from Start until the process exits, so that jobs
+// which must not run concurrently are never run twice
+func WithExclusiveLock(path string) Option {
+	return func(o *options) error {
+		if path == "" {
+			return errMissingPath
+		}
+		o.lockPath = path
+		return nil
+	}
+}
@@ -0,0 +1,15 @@
+//go:build !(darwin || dragonfly || freebsd || linux || netbsd || openbsd || windows)
+
+package launcher
+
+import (
+	"errors"
+	"os"
+)
+
+var errLockUnsupported = errors.New("exclusive locks are not supported on this platform")
+
+// lockFile is not supported on this platform
+func lockFile(path string) (*os.File, error) {
+	return nil, errLockUnsupported
+}
@@ -0,0 +1,46 @@
+package launcher
+
+import (
+	"context"
+	"errors"
+	"path/filepath"
+	"testing"
+)
+
+func TestExclusiveLock(t *testing.T) {
+
+	path := filepath.Join(t.TempDir(), "job.lock")
+
+	first, err := NewWithOptions(context.Background(), "sleep", nil, []string{"10"}, WithExclusiveLock(path))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer first.Close()
+
+	second, err := NewWithOptions(context.Background(), "echo", nil, nil, WithExclusiveLock(path))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer second.Close()
+
+	if err := first.Start(); err != nil {
+		t.Fatal(err)
+	}
+	if err := second.Start(); !errors.Is(err, ErrLocked) {
+		t.Fatalf("expected ErrLocked, got %v\n", err)
+	}
+
+	first.Cancel()
+	first.Wait()
+
+	if err := second.Run(); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestExclusiveLockMissingPath(t *testing.T) {
+
+	if _, err := NewWithOptions(context.Background(), "echo", nil, nil, WithExclusiveLock("")); err != errMissingPath {
+		t.Fatalf("expected errMissingPath, got %v\n", err)
+	}
+}
@@ -0,0 +1,35 @@
+//go:build windows
+
+package launcher
+
+import (
+	"os"
+	"syscall"
+)
+
+// errorSharingViolation is returned by CreateFile when the
+// file is already open without sharing
+const errorSharingViolation syscall.Errno = 32
+
+// lockFile opens the file at path, creating it if necessary, without
+// sharing, so that the lock is released when the file is closed
+func lockFile(path string) (*os.File, error) {
+	name, err := syscall.UTF16PtrFromString(path)
+	if err != nil {
+		return nil, err
+	}
+	h, err := syscall.CreateFile(name,
+		syscall.GENERIC_READ|syscall.GENERIC_WRITE,
+		0,
+		nil,
+		syscall.OPEN_ALWAYS,
+		syscall.FILE_ATTRIBUTE_NORMAL,
+		0)
+	if err != nil {
+		if err == errorSharingViolation {
+			return nil, ErrLocked
+		}
+		return nil, err
+	}
+	return os.NewFile(uintptr(h), path), nil
+}
@@ -0,0 +1,478 @@
+package launcher
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"sort"
+	"sync"
+	"time"
+)
+
+// ErrUnknownName is returned when no process is registered with a name
+var ErrUnknownName = errors.New("no process registered with the given name")
+
+// ErrDuplicateName is returned when a name is already registered
+var ErrDuplicateName = errors.New("a process is already registered with the given name")
+
+// ErrAlreadyRunning is returned when starting a process that is running
+var ErrAlreadyRunning = errors.New("process is already running")
+
+// defaultStopGrace is the time allowed for a process to exit
+// after being asked to terminate during a Restart
+const defaultStopGrace = 5 * time.Second
+
+// lineBuffer is the number of lines held for a subscriber, beyond
+// which further lines are dropped until the subscriber catches up
+const lineBuffer = 256
+
+// Status describes the current state of a named process
+type Status struct {
+	Name      string        `json:"name"`
+	Pid       int           `json:"pid"`
+	Running   bool          `json:"running"`
+	StartedAt time.Time     `json:"started_at"`
+	Uptime    time.Duration `json:"uptime"`
+	Restarts  int           `json:"restarts"`
+	// LastExitCode is the exit code of the most recently completed run,
+	// or -1 if no run has completed or it was terminated by a signal
+	LastExitCode int `json:"last_exit_code"`
+}
+
+// Line is a line of output from a named process
+type Line struct {
+	Name string `json:"name"`
+	// Stream is either "stdout" or "stderr"
+	Stream string    `json:"stream"`
+	Time   time.Time `json:"time"`
+	Text   string    `json:"text"`
+	// Dropped is the number of lines dropped for the subscriber
+	// before this line, as it had fallen behind
+	Dropped int `json:"dropped,omitempty"`
+}
+
+// subscriber is the subscription of a channel to output
+type subscriber struct {
+	name    string
+	dropped int
+}
+
+// managed holds the registry entry for a named process
+type managed struct {
+	name     string
+	spec     Spec
+	l        *Launcher
+	done     chan struct{}
+	running  bool
+	restarts int
+	exitCode int
+
+	// starting is set while a start is in progress, with launching set
+	// once its Launcher is created, and abandoned if it is stopped
+	starting  bool
+	launching *Launcher
+	abandoned bool
+}
+
+// Manager maintains a registry of named processes, each created
+// from a Spec, which can be listed, inspected, stopped and restarted
+type Manager struct {
+	ctx    context.Context
+	cancel context.CancelFunc
+	mu     sync.Mutex
+	procs  map[string]*managed
+	lim    *Limiter
+	subMu  sync.Mutex
+	subs   map[chan Line]*subscriber
+}
+
+// NewManager creates a new Manager.  All processes launched by the
+// Manager are terminated when the context is cancelled
+func NewManager(ctx context.Context) (*Manager, error) {
+	if ctx == nil {
+		return nil, ErrMissingContext
+	}
+	myCtx, cancel := context.WithCancel(ctx)
+
+	return &Manager{
+		ctx:    myCtx,
+		cancel: cancel,
+		procs:  map[string]*managed{},
+		subs:   map[chan Line]*subscriber{},
+	}, nil
+}
+
+// SetLimiter requires processes subsequently started by the Manager
+// to be admitted by the Limiter, in place of any set by their Spec
+func (m *Manager) SetLimiter(lim *Limiter) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	m.lim = lim
+}
+
+// Launch registers the Spec under the name and starts it
+func (m *Manager) Launch(name string, spec Spec) error {
+	m.mu.Lock()
+	if _, ok := m.procs[name]; ok {
+		m.mu.Unlock()
+		return ErrDuplicateName
+	}
+
+	p := &managed{
+		name:     name,
+		spec:     spec,
+		exitCode: -1,
+	}
+	lim, err := m.reserve(p)
+	if err != nil {
+		m.mu.Unlock()
+		return err
+	}
+	m.procs[name] = p
+	m.mu.Unlock()
+
+	if err := m.start(p, lim); err != nil {
+		m.mu.Lock()
+		if m.procs[name] == p {
+			delete(m.procs, name)
+		}
+		m.mu.Unlock()
+		return err
+	}
+	return nil
+}
+
+// Start starts the named process, which must not be running
+func (m *Manager) Start(name string) error {
+	m.mu.Lock()
+	p, ok := m.procs[name]
+	if !ok {
+		m.mu.Unlock()
+		return ErrUnknownName
+	}
+	lim, err := m.reserve(p)
+	m.mu.Unlock()
+
+	if err != nil {
+		return err
+	}
+	return m.start(p, lim)
+}
+
+// List returns the Status of all registered processes, ordered by name
+func (m *Manager) List() []Status {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	s := make([]Status, 0, len(m.procs))
+	for _, p := range m.procs {
+		s = append(s, p.status())
+	}
+	sort.Slice(s, func(i, j int) bool { return s[i].Name < s[j].Name })
+	return s
+}
+
+// Inspect returns the Status of the named process
+func (m *Manager) Inspect(name string) (Status, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	p, ok := m.procs[name]
+	if !ok {
+		return Status{}, ErrUnknownName
+	}
+	return p.status(), nil
+}
+
+// Stop asks the named process to terminate, killing it if it
+// has not exited within the grace period
+func (m *Manager) Stop(name string, grace time.Duration) error {
+	m.mu.Lock()
+	p, ok := m.procs[name]
+	if !ok {
+		m.mu.Unlock()
+		return ErrUnknownName
+	}
+	l, done, starting, launching := p.l, p.done, p.starting, p.launching
+	if starting {
+		p.abandoned = true
+	}
+	m.mu.Unlock()
+
+	// A process still waiting to start is abandoned
+	if starting {
+		if launching != nil {
+			launching.CancelWithCause(ErrCancelled)
+		}
+		return nil
+	}
+	if l != nil {
+		l.terminate(done, grace, ErrCancelled)
+	}
+	return nil
+}
+
+// Signal sends the signal to the named process
+func (m *Manager) Signal(name string, sig os.Signal) error {
+	l, err := m.running(name)
+	if err != nil {
+		return err
+	}
+	return l.Signal(sig)
+}
+
+// SendStdIn passes the supplied bytes to the stdin of the named process
+func (m *Manager) SendStdIn(name string, b []byte) error {
+	l, err := m.running(name)
+	if err != nil {
+		return err
+	}
+	return l.SendStdIn(b)
+}
+
+// Restart stops the named process, if running, and starts
+// a new instance from its Spec
+func (m *Manager) Restart(name string) error {
+	if err := m.Stop(name, defaultStopGrace); err != nil {
+		return err
+	}
+
+	m.mu.Lock()
+	p, ok := m.procs[name]
+	if !ok {
+		m.mu.Unlock()
+		return ErrUnknownName
+	}
+	lim, err := m.reserve(p)
+	m.mu.Unlock()
+
+	if err != nil {
+		return err
+	}
+	if err := m.start(p, lim); err != nil {
+		return err
+	}
+
+	m.mu.Lock()
+	p.restarts++
+	m.mu.Unlock()
+
+	return nil
+}
+
+// Remove stops the named process, if running, and removes it
+// from the registry
+func (m *Manager) Remove(name string, grace time.Duration) error {
+	if err := m.Stop(name, grace); err != nil {
+		return err
+	}
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	delete(m.procs, name)
+	return nil
+}
+
+// Subscribe returns a channel receiving each line of output from the
+// named process, or from all processes if name is empty.  Lines are
+// dropped if the receiver falls behind, with the number dropped given
+// by the Dropped field of the next line received.  The returned
+// function must be called to end the subscription, after which the
+// channel is closed
+func (m *Manager) Subscribe(name string) (<-chan Line, func()) {
+	ch := make(chan Line, lineBuffer)
+
+	m.subMu.Lock()
+	m.subs[ch] = &subscriber{name: name}
+	m.subMu.Unlock()
+
+	var once sync.Once
+	return ch, func() {
+		once.Do(func() {
+			m.subMu.Lock()
+			defer m.subMu.Unlock()
+
+			if _, ok := m.subs[ch]; ok {
+				delete(m.subs, ch)
+				close(ch)
+			}
+		})
+	}
+}
+
+// publish sends the line to all interested subscribers
+func (m *Manager) publish(line Line) {
+	m.subMu.Lock()
+	defer m.subMu.Unlock()
+
+	for ch, sub := range m.subs {
+		if sub.name != "" && sub.name != line.Name {
+			continue
+		}
+		line.Dropped = sub.dropped
+		select {
+		case ch <- line:
+			sub.dropped = 0
+		default:
+			sub.dropped++
+		}
+	}
+}
+
+// publisher returns a writer that publishes each line written
+// to it as output of the named process on the stream
+func (m *Manager) publisher(name, stream string) *lineWriter {
+	return newLineWriter(func(text string) {
+		m.publish(Line{
+			Name:   name,
+			Stream: stream,
+			Time:   time.Now(),
+			Text:   text,
+		})
+	})
+}
+
+// Close terminates all running processes and waits for them to exit,
+// ending all subscriptions
+func (m *Manager) Close() error {
+	m.cancel()
+
+	m.mu.Lock()
+	var dones []chan struct{}
+	for _, p := range m.procs {
+		if p.done != nil {
+			dones = append(dones, p.done)
+		}
+	}
+	m.mu.Unlock()
+
+	for _, done := range dones {
+		<-done
+	}
+
+	m.subMu.Lock()
+	defer m.subMu.Unlock()
+
+	for ch := range m.subs {
+		delete(m.subs, ch)
+		close(ch)
+	}
+	return nil
+}
+
+// running returns the Launcher of the named process if it is running
+func (m *Manager) running(name string) (*Launcher, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	p, ok := m.procs[name]
+	if !ok {
+		return nil, ErrUnknownName
+	}
+	if !p.running {
+		return nil, fmt.Errorf("%s: %w", name, ErrNotStarted)
+	}
+	return p.l, nil
+}
+
+// reserve marks the process as starting, so that no other start is
+// attempted, returning the Limiter to apply to it, and must be called
+// with the lock held
+func (m *Manager) reserve(p *managed) (*Limiter, error) {
+	if p.running || p.starting {
+		return nil, ErrAlreadyRunning
+	}
+	p.starting = true
+	p.abandoned = false
+	return m.lim, nil
+}
+
+// start creates and launches a new instance of the process reserved by
+// reserve, without holding the lock as creating it may verify the binary,
+// and starting it may wait for a Limiter or readiness, and then records it
+func (m *Manager) start(p *managed, lim *Limiter) error {
+	spec := p.spec
+	if lim != nil {
+		spec.Options = append(append([]Option{}, spec.Options...), WithLimiter(lim))
+	}
+	l, err := spec.New(m.ctx)
+
+	m.mu.Lock()
+	abandoned := p.abandoned
+	if err != nil || abandoned {
+		p.starting = false
+	} else {
+		p.launching = l
+	}
+	m.mu.Unlock()
+
+	if err != nil {
+		return err
+	}
+	if abandoned {
+		l.Close()
+		return ErrCancelled
+	}
+
+	err = l.Start()
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	p.starting = false
+	p.launching = nil
+	if err != nil {
+		l.Close()
+		return err
+	}
+
+	p.l = l
+	p.done = make(chan struct{})
+	p.running = true
+
+	done := p.done
+	l.goLabelled("manager-watch", func() { m.watch(p, l, done) })
+
+	return nil
+}
+
+// watch publishes the output of the process to subscribers, and
+// then records its exit
+func (m *Manager) watch(p *managed, l *Launcher, done chan struct{}) {
+	stdout, stderr := m.publisher(p.name, "stdout"), m.publisher(p.name, "stderr")
+	l.copyOutput(stdout, stderr)
+	stdout.Flush()
+	stderr.Flush()
+
+	l.Wait()
+	l.Close()
+
+	m.mu.Lock()
+	p.running = false
+	p.exitCode = l.ExitCode()
+	m.mu.Unlock()
+
+	close(done)
+}
+
+// status returns the Status of the process, and must be
+// called with the lock held
+func (p *managed) status() Status {
+	s := Status{
+		Name:         p.name,
+		Running:      p.running,
+		Restarts:     p.restarts,
+		LastExitCode: p.exitCode,
+	}
+	if p.l != nil {
+		s.Pid = p.l.Pid()
+		s.StartedAt = p.l.StartedAt()
+	}
+	if p.running {
+		s.Uptime = p.l.Duration()
+	}
+	return s
+}
@@ -0,0 +1,393 @@
+package launcher
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"sync"
+	"testing"
+	"time"
+)
+
+func TestManagerWithNilCtx(t *testing.T) {
+
+	_, err := NewManager(nil)
+	if err != ErrMissingContext {
+		t.Fatal(err)
+	}
+}
+
+func TestManagerLaunchAndStop(t *testing.T) {
+
+	m, err := NewManager(context.Background())
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer m.Close()
+
+	if err := m.Launch("sleeper", Spec{File: "sleep", Args: []string{"10"}}); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := m.Launch("sleeper", Spec{File: "sleep", Args: []string{"10"}}); err != ErrDuplicateName {
+		t.Fatal(err)
+	}
+
+	s, err := m.Inspect("sleeper")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !s.Running || s.Pid == 0 {
+		t.Fatalf("expected running process, got %+v\n", s)
+	}
+
+	if err := m.Stop("sleeper", time.Second); err != nil {
+		t.Fatal(err)
+	}
+
+	s, err = m.Inspect("sleeper")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if s.Running {
+		t.Fatal("still running")
+	}
+	if s.LastExitCode != -1 {
+		t.Fatalf("expected exit by signal, got %v\n", s.LastExitCode)
+	}
+}
+
+func TestManagerExitCode(t *testing.T) {
+
+	m, err := NewManager(context.Background())
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer m.Close()
+
+	if err := m.Launch("exit", Spec{File: "sh", Args: []string{"-c", "exit 3"}}); err != nil {
+		t.Fatal(err)
+	}
+
+	var s Status
+	for deadline := time.Now().Add(5 * time.Second); time.Now().Before(deadline); time.Sleep(10 * time.Millisecond) {
+		if s, err = m.Inspect("exit"); err != nil {
+			t.Fatal(err)
+		}
+		if !s.Running {
+			break
+		}
+	}
+	if s.LastExitCode != 3 {
+		t.Fatalf("expected exit code 3, got %v\n", s.LastExitCode)
+	}
+}
+
+func TestManagerRestartAndList(t *testing.T) {
+
+	m, err := NewManager(context.Background())
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer m.Close()
+
+	for _, name := range []string{"b", "a"} {
+		if err := m.Launch(name, Spec{File: "sleep", Args: []string{"10"}}); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	before, _ := m.Inspect("a")
+
+	if err := m.Restart("a"); err != nil {
+		t.Fatal(err)
+	}
+
+	after, _ := m.Inspect("a")
+	if after.Restarts != 1 {
+		t.Fatalf("expected 1 restart, got %v\n", after.Restarts)
+	}
+	if after.Pid == before.Pid {
+		t.Fatal("expected new process after restart")
+	}
+
+	l := m.List()
+	if len(l) != 2 || l[0].Name != "a" || l[1].Name != "b" {
+		t.Fatalf("unexpected list: %+v\n", l)
+	}
+}
+
+func TestManagerUnknownName(t *testing.T) {
+
+	m, err := NewManager(context.Background())
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer m.Close()
+
+	if _, err := m.Inspect("zzz"); err != ErrUnknownName {
+		t.Fatal(err)
+	}
+	if err := m.Stop("zzz", time.Second); err != ErrUnknownName {
+		t.Fatal(err)
+	}
+	if err := m.Restart("zzz"); err != ErrUnknownName {
+		t.Fatal(err)
+	}
+}
+
+func TestManagerSubscribe(t *testing.T) {
+
+	m, err := NewManager(context.Background())
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer m.Close()
+
+	lines, cancel := m.Subscribe("talker")
+	defer cancel()
+
+	others, cancelOthers := m.Subscribe("other")
+	defer cancelOthers()
+
+	if err := m.Launch("talker", Spec{File: "sh", Args: []string{"-c", "echo foo; echo bar >&2; exec sleep 10"}}); err != nil {
+		t.Fatal(err)
+	}
+
+	got := map[string]string{}
+	for len(got) < 2 {
+		select {
+		case line := <-lines:
+			got[line.Stream] = line.Text
+		case <-time.After(5 * time.Second):
+			t.Fatal("timed out waiting for output")
+		}
+	}
+
+	if got["stdout"] != "foo" || got["stderr"] != "bar" {
+		t.Fatalf("unexpected output: %v\n", got)
+	}
+
+	select {
+	case line := <-others:
+		t.Fatalf("unexpected line for other process: %v\n", line)
+	default:
+	}
+
+	cancel()
+	if _, ok := <-lines; ok {
+		t.Fatal("expected channel to be closed")
+	}
+}
+
+func TestManagerSendStdInAndSignal(t *testing.T) {
+
+	m, err := NewManager(context.Background())
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer m.Close()
+
+	lines, cancel := m.Subscribe("cat")
+	defer cancel()
+
+	if err := m.Launch("cat", Spec{File: "cat"}); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := m.SendStdIn("cat", []byte("foo\n")); err != nil {
+		t.Fatal(err)
+	}
+
+	select {
+	case line := <-lines:
+		if line.Text != "foo" {
+			t.Fatalf("unexpected line: %+v\n", line)
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatal("timed out waiting for output")
+	}
+
+	if err := m.Signal("cat", os.Kill); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := m.SendStdIn("zzz", []byte("foo\n")); err != ErrUnknownName {
+		t.Fatal(err)
+	}
+}
+
+func TestManagerQueuedLaunch(t *testing.T) {
+
+	m, err := NewManager(context.Background())
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer m.Close()
+
+	lim, err := NewLimiter(WithMaxConcurrent(1))
+	if err != nil {
+		t.Fatal(err)
+	}
+	m.SetLimiter(lim)
+
+	if err := m.Launch("a", Spec{File: "sleep", Args: []string{"10"}}); err != nil {
+		t.Fatal(err)
+	}
+
+	// b waits for a to release the limiter
+	launched := make(chan error, 1)
+	go func() { launched <- m.Launch("b", Spec{File: "sleep", Args: []string{"10"}}) }()
+
+	within := func(what string, f func()) {
+		done := make(chan struct{})
+		go func() {
+			f()
+			close(done)
+		}()
+		select {
+		case <-done:
+		case <-time.After(2 * time.Second):
+			t.Fatalf("%s blocked by a queued launch\n", what)
+		}
+	}
+
+	for {
+		if _, err := m.Inspect("b"); err == nil {
+			break
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+	within("List", func() {
+		if l := m.List(); len(l) != 2 || l[1].Running {
+			t.Errorf("unexpected list: %+v\n", l)
+		}
+	})
+	within("Stop", func() {
+		if err := m.Stop("b", time.Second); err != nil {
+			t.Error(err)
+		}
+	})
+
+	select {
+	case err := <-launched:
+		if err == nil {
+			t.Fatal("expected error from abandoned launch")
+		}
+	case <-time.After(2 * time.Second):
+		t.Fatal("expected stopped launch to return")
+	}
+	if _, err := m.Inspect("b"); err != ErrUnknownName {
+		t.Fatalf("expected abandoned launch to be removed, got %v\n", err)
+	}
+}
+
+func TestManagerSubscribeDropped(t *testing.T) {
+
+	m, err := NewManager(context.Background())
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer m.Close()
+
+	lines, cancel := m.Subscribe("talker")
+	defer cancel()
+
+	script := fmt.Sprintf("i=0; while [ $i -lt %d ]; do echo $i; i=$((i+1)); done; read x; echo last", lineBuffer+10)
+	if err := m.Launch("talker", Spec{File: "sh", Args: []string{"-c", script}}); err != nil {
+		t.Fatal(err)
+	}
+
+	// The lines beyond those buffered are dropped as they are not read
+	deadline := time.Now().Add(5 * time.Second)
+	for {
+		m.subMu.Lock()
+		dropped := 0
+		for _, sub := range m.subs {
+			dropped = sub.dropped
+		}
+		m.subMu.Unlock()
+		if dropped == 10 {
+			break
+		}
+		if time.Now().After(deadline) {
+			t.Fatalf("timed out waiting for lines to be dropped, got %v\n", dropped)
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+
+	for i := 0; i < lineBuffer; i++ {
+		if line := <-lines; line.Dropped != 0 {
+			t.Fatalf("unexpected dropped count for %q: %v\n", line.Text, line.Dropped)
+		}
+	}
+
+	if err := m.SendStdIn("talker", []byte("\n")); err != nil {
+		t.Fatal(err)
+	}
+	select {
+	case line := <-lines:
+		if line.Text != "last" || line.Dropped != 10 {
+			t.Fatalf("expected last line to report 10 dropped, got %+v\n", line)
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatal("timed out waiting for output")
+	}
+}
+
+func TestManagerSlowCreation(t *testing.T) {
+
+	m, err := NewManager(context.Background())
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer m.Close()
+
+	// The Policy holds up the creation of the Launcher
+	checking := make(chan struct{})
+	release := make(chan struct{})
+	var once sync.Once
+	policy := PolicyFunc(func(req PolicyRequest) error {
+		if req.Stage == PolicyAtNew {
+			once.Do(func() { close(checking) })
+			<-release
+		}
+		return nil
+	})
+
+	launched := make(chan error, 1)
+	go func() {
+		launched <- m.Launch("slow", Spec{File: "sleep", Args: []string{"10"}, Options: []Option{WithPolicy(policy)}})
+	}()
+	<-checking
+
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		m.List()
+		if _, err := m.Inspect("slow"); err != nil {
+			t.Error(err)
+		}
+		if err := m.Launch("other", Spec{File: "sleep", Args: []string{"10"}}); err != nil {
+			t.Error(err)
+		}
+		if err := m.Stop("slow", time.Second); err != nil {
+			t.Error(err)
+		}
+	}()
+	select {
+	case <-done:
+	case <-time.After(2 * time.Second):
+		t.Fatal("registry blocked by the creation of a Launcher")
+	}
+
+	// The stopped process is abandoned once created
+	close(release)
+	if err := <-launched; !errors.Is(err, ErrCancelled) {
+		t.Fatalf("expected %v, got %v\n", ErrCancelled, err)
+	}
+	if _, err := m.Inspect("slow"); !errors.Is(err, ErrUnknownName) {
+		t.Fatalf("expected abandoned launch to be unregistered, got %v\n", err)
+	}
+}
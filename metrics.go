@@ -0,0 +1,150 @@
+package launcher
+
+import (
+	"errors"
+	"fmt"
+	"net"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+var errMissingSink = errors.New("metrics sink must be provided")
+
+// The names of the metrics recorded for each launch
+const (
+	// MetricLaunches counts the processes started
+	MetricLaunches = "launcher.launches"
+	// MetricStartFailures counts the processes which failed to start
+	MetricStartFailures = "launcher.start_failures"
+	// MetricFailures counts the processes which exited unsuccessfully
+	MetricFailures = "launcher.failures"
+	// MetricDuration is the time for which each process ran
+	MetricDuration = "launcher.duration"
+	// MetricStdinBytes, MetricStdoutBytes and MetricStderrBytes count
+	// the data exchanged with each process by the time it exited
+	MetricStdinBytes  = "launcher.stdin_bytes"
+	MetricStdoutBytes = "launcher.stdout_bytes"
+	MetricStderrBytes = "launcher.stderr_bytes"
+)
+
+// MetricsSink receives the metrics of launches, tagged with the
+// command and any correlation ID as "key:value" pairs.  Its methods
+// are called from many goroutines, and must not block
+type MetricsSink interface {
+	// Count adds delta to the named counter
+	Count(name string, delta int64, tags ...string)
+	// Timing records a duration against the named timer
+	Timing(name string, d time.Duration, tags ...string)
+}
+
+// WithMetrics sends the metrics of the launch to the sink
+func WithMetrics(sink MetricsSink) Option {
+	return func(o *options) error {
+		if sink == nil {
+			return errMissingSink
+		}
+		o.metrics = sink
+		return nil
+	}
+}
+
+// metricTags returns the tags of the metrics of the launch
+func (l *Launcher) metricTags() []string {
+	tags := []string{"command:" + filepath.Base(l.file)}
+	if id := l.CorrelationID(); id != "" {
+		tags = append(tags, "correlation_id:"+id)
+	}
+	return tags
+}
+
+// recordStart records the outcome of starting the process
+func (l *Launcher) recordStart(err error) {
+	if l.opts.metrics == nil {
+		return
+	}
+	if err != nil {
+		l.opts.metrics.Count(MetricStartFailures, 1, l.metricTags()...)
+		return
+	}
+	l.opts.metrics.Count(MetricLaunches, 1, l.metricTags()...)
+}
+
+// recordExit records the run of the exited process
+func (l *Launcher) recordExit(err error) {
+	m := l.opts.metrics
+	if m == nil {
+		return
+	}
+	tags := l.metricTags()
+	m.Timing(MetricDuration, l.Duration(), tags...)
+	if err != nil {
+		m.Count(MetricFailures, 1, tags...)
+	}
+
+	stats := l.IOStats()
+	m.Count(MetricStdinBytes, stats.StdinBytes, tags...)
+	m.Count(MetricStdoutBytes, stats.StdoutBytes, tags...)
+	m.Count(MetricStderrBytes, stats.StderrBytes, tags...)
+}
+
+// StatsD is a MetricsSink sending metrics over UDP to a StatsD server,
+// with tags in the extended format of DogStatsD, as used by Datadog
+type StatsD struct {
+	prefix string
+	tags   []string
+	mu     sync.Mutex
+	conn   net.Conn
+	buf    []byte
+}
+
+// NewStatsD creates a StatsD sending metrics to the address, such as
+// "localhost:8125", with names prefixed by prefix if set, and with the
+// tags added to those of every metric
+func NewStatsD(addr, prefix string, tags ...string) (*StatsD, error) {
+	conn, err := net.Dial("udp", addr)
+	if err != nil {
+		return nil, err
+	}
+	if prefix != "" && !strings.HasSuffix(prefix, ".") {
+		prefix += "."
+	}
+	return &StatsD{
+		prefix: prefix,
+		tags:   append([]string{}, tags...),
+		conn:   conn,
+	}, nil
+}
+
+// Count sends the counter increment
+func (s *StatsD) Count(name string, delta int64, tags ...string) {
+	s.send(name, strconv.FormatInt(delta, 10), "c", tags)
+}
+
+// Timing sends the duration in milliseconds
+func (s *StatsD) Timing(name string, d time.Duration, tags ...string) {
+	ms := strconv.FormatFloat(float64(d)/float64(time.Millisecond), 'f', -1, 64)
+	s.send(name, ms, "ms", tags)
+}
+
+// Close closes the connection to the server
+func (s *StatsD) Close() error {
+	return s.conn.Close()
+}
+
+// send writes a single metric as a datagram.  Errors are ignored,
+// as delivery over UDP is not assured in any case
+func (s *StatsD) send(name, value, typ string, tags []string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	b := fmt.Appendf(s.buf[:0], "%s%s:%s|%s", s.prefix, name, value, typ)
+	if len(s.tags)+len(tags) > 0 {
+		b = append(b, "|#"...)
+		b = append(b, strings.Join(append(s.tags[:len(s.tags):len(s.tags)], tags...), ",")...)
+	}
+	s.buf = b
+	s.conn.Write(b)
+}
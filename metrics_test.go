@@ -0,0 +1,103 @@
+package launcher
+
+import (
+	"context"
+	"net"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+)
+
+// recordingSink is a MetricsSink retaining the metrics sent to it
+type recordingSink struct {
+	mu      sync.Mutex
+	counts  map[string]int64
+	timings map[string]time.Duration
+	tags    []string
+}
+
+func newRecordingSink() *recordingSink {
+	return &recordingSink{counts: map[string]int64{}, timings: map[string]time.Duration{}}
+}
+
+func (r *recordingSink) Count(name string, delta int64, tags ...string) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.counts[name] += delta
+	r.tags = tags
+}
+
+func (r *recordingSink) Timing(name string, d time.Duration, tags ...string) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.timings[name] = d
+	r.tags = tags
+}
+
+func TestWithMetrics(t *testing.T) {
+
+	sink := newRecordingSink()
+	ctx := ContextWithCorrelationID(context.Background(), "run-1")
+
+	l, err := NewWithOptions(ctx, "sh", nil, []string{"-c", "echo hello; exit 2"}, WithMetrics(sink))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer l.Close()
+
+	if _, err := l.RunCaptured(); err == nil {
+		t.Fatal("expected exit status error")
+	}
+
+	sink.mu.Lock()
+	defer sink.mu.Unlock()
+
+	if sink.counts[MetricLaunches] != 1 || sink.counts[MetricFailures] != 1 || sink.counts[MetricStartFailures] != 0 {
+		t.Fatalf("unexpected counts %v\n", sink.counts)
+	}
+	if sink.timings[MetricDuration] <= 0 {
+		t.Fatalf("unexpected timings %v\n", sink.timings)
+	}
+	if strings.Join(sink.tags, ",") != "command:sh,correlation_id:run-1" {
+		t.Fatalf("unexpected tags %v\n", sink.tags)
+	}
+
+	if _, err := NewWithOptions(context.Background(), "true", nil, nil, WithMetrics(nil)); err == nil {
+		t.Fatal("expected error for nil sink")
+	}
+}
+
+func TestStatsD(t *testing.T) {
+
+	server, err := net.ListenPacket("udp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer server.Close()
+
+	s, err := NewStatsD(server.LocalAddr().String(), "app", "env:test")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer s.Close()
+
+	s.Count(MetricLaunches, 1, "command:ls")
+	s.Timing(MetricDuration, 1500*time.Microsecond)
+
+	expected := []string{
+		"app.launcher.launches:1|c|#env:test,command:ls",
+		"app.launcher.duration:1.5|ms|#env:test",
+	}
+	b := make([]byte, 1024)
+	for _, e := range expected {
+		server.SetReadDeadline(time.Now().Add(time.Second))
+		n, _, err := server.ReadFrom(b)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if string(b[:n]) != e {
+			t.Fatalf("expected %q, got %q\n", e, b[:n])
+		}
+	}
+}
@@ -0,0 +1,145 @@
+// Package msgconn exchanges framed messages with a process over its
+// stdin and stdout, encoded by a pluggable Codec, with JSON, protobuf
+// and MessagePack codecs provided.
+//
+// In the parent, the stream is the launcher.Launcher, and in the child
+// it is launcher.StdioConn:
+//
+//	// parent
+//	c, err := msgconn.New(l, msgconn.ProtoCodec)
+//	err = c.SendMsg(req)
+//	err = c.RecvMsg(resp)
+//
+//	// child
+//	c, err := msgconn.New(launcher.StdioConn(), msgconn.ProtoCodec)
+package msgconn
+
+import (
+	"encoding/binary"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"sync"
+
+	"github.com/vmihailenco/msgpack/v5"
+	"google.golang.org/protobuf/proto"
+)
+
+var errMissingCodec = errors.New("codec must be provided")
+var errNotProtoMessage = errors.New("value is not a proto.Message")
+var errMsgTooLarge = errors.New("message exceeds the maximum size")
+
+// maxMsgSize limits the size of a single encoded message
+const maxMsgSize = 64 * 1024 * 1024
+
+// Codec encodes and decodes the messages exchanged by a Conn
+type Codec interface {
+	Marshal(v any) ([]byte, error)
+	Unmarshal(data []byte, v any) error
+}
+
+var (
+	// JSONCodec encodes messages as JSON
+	JSONCodec Codec = jsonCodec{}
+	// ProtoCodec encodes messages, which must be proto.Messages,
+	// in the protobuf wire format
+	ProtoCodec Codec = protoCodec{}
+	// MsgPackCodec encodes messages as MessagePack
+	MsgPackCodec Codec = msgPackCodec{}
+)
+
+type jsonCodec struct{}
+
+func (jsonCodec) Marshal(v any) ([]byte, error)      { return json.Marshal(v) }
+func (jsonCodec) Unmarshal(data []byte, v any) error { return json.Unmarshal(data, v) }
+
+type protoCodec struct{}
+
+func (protoCodec) Marshal(v any) ([]byte, error) {
+	m, ok := v.(proto.Message)
+	if !ok {
+		return nil, fmt.Errorf("%w: %T", errNotProtoMessage, v)
+	}
+	return proto.Marshal(m)
+}
+
+func (protoCodec) Unmarshal(data []byte, v any) error {
+	m, ok := v.(proto.Message)
+	if !ok {
+		return fmt.Errorf("%w: %T", errNotProtoMessage, v)
+	}
+	return proto.Unmarshal(data, m)
+}
+
+type msgPackCodec struct{}
+
+func (msgPackCodec) Marshal(v any) ([]byte, error)      { return msgpack.Marshal(v) }
+func (msgPackCodec) Unmarshal(data []byte, v any) error { return msgpack.Unmarshal(data, v) }
+
+// Conn exchanges messages over a stream, such as the stdin and
+// stdout of a process, with each message encoded by its Codec and
+// preceded by its length as a big endian uint32.  Messages may be sent
+// and received concurrently, with each direction used in turn
+type Conn struct {
+	r     io.Reader
+	w     io.Writer
+	codec Codec
+	rmu   sync.Mutex
+	wmu   sync.Mutex
+}
+
+// New returns a Conn over the stream, which in the parent is the
+// launcher.Launcher, and in the child is launcher.StdioConn
+func New(rw io.ReadWriter, codec Codec) (*Conn, error) {
+	if codec == nil {
+		return nil, errMissingCodec
+	}
+	return &Conn{r: rw, w: rw, codec: codec}, nil
+}
+
+// SendMsg encodes and sends the message
+func (c *Conn) SendMsg(v any) error {
+	data, err := c.codec.Marshal(v)
+	if err != nil {
+		return err
+	}
+	if len(data) > maxMsgSize {
+		return fmt.Errorf("%w: %d bytes", errMsgTooLarge, len(data))
+	}
+
+	frame := make([]byte, 4+len(data))
+	binary.BigEndian.PutUint32(frame, uint32(len(data)))
+	copy(frame[4:], data)
+
+	c.wmu.Lock()
+	defer c.wmu.Unlock()
+
+	_, err = c.w.Write(frame)
+	return err
+}
+
+// RecvMsg receives the next message, decoding it into v.  It returns
+// io.EOF if the stream ends between messages
+func (c *Conn) RecvMsg(v any) error {
+	c.rmu.Lock()
+	defer c.rmu.Unlock()
+
+	var header [4]byte
+	if _, err := io.ReadFull(c.r, header[:]); err != nil {
+		return err
+	}
+	n := binary.BigEndian.Uint32(header[:])
+	if n > maxMsgSize {
+		return fmt.Errorf("%w: %d bytes", errMsgTooLarge, n)
+	}
+
+	data := make([]byte, n)
+	if _, err := io.ReadFull(c.r, data); err != nil {
+		if err == io.EOF {
+			err = io.ErrUnexpectedEOF
+		}
+		return err
+	}
+	return c.codec.Unmarshal(data, v)
+}
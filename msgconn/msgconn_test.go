@@ -0,0 +1,92 @@
+package msgconn
+
+import (
+	"context"
+	"io"
+	"testing"
+	"time"
+
+	"github.com/gford1000-go/launcher"
+	"google.golang.org/protobuf/types/known/durationpb"
+)
+
+type codecMsg struct {
+	Name  string
+	Count int
+}
+
+func TestConnCodecs(t *testing.T) {
+
+	for name, codec := range map[string]Codec{"json": JSONCodec, "msgpack": MsgPackCodec} {
+		t.Run(name, func(t *testing.T) {
+
+			// cat echoes each message
+			l, err := launcher.New(context.Background(), "cat", nil)
+			if err != nil {
+				t.Fatal(err)
+			}
+			defer l.Close()
+			if err := l.Start(); err != nil {
+				t.Fatal(err)
+			}
+
+			c, err := New(l, codec)
+			if err != nil {
+				t.Fatal(err)
+			}
+			want := codecMsg{Name: "foo", Count: 3}
+			if err := c.SendMsg(want); err != nil {
+				t.Fatal(err)
+			}
+			var got codecMsg
+			if err := c.RecvMsg(&got); err != nil {
+				t.Fatal(err)
+			}
+			if got != want {
+				t.Fatalf("expected %v, got %v\n", want, got)
+			}
+
+			l.StdinWriter().Close()
+			if err := c.RecvMsg(&got); err != io.EOF {
+				t.Fatalf("expected io.EOF, got %v\n", err)
+			}
+		})
+	}
+}
+
+func TestConnProto(t *testing.T) {
+
+	l, err := launcher.New(context.Background(), "cat", nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer l.Close()
+	if err := l.Start(); err != nil {
+		t.Fatal(err)
+	}
+
+	c, err := New(l, ProtoCodec)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := c.SendMsg(codecMsg{}); err == nil {
+		t.Fatal("expected error sending a value which is not a proto.Message")
+	}
+	if err := c.SendMsg(durationpb.New(3 * time.Second)); err != nil {
+		t.Fatal(err)
+	}
+	var got durationpb.Duration
+	if err := c.RecvMsg(&got); err != nil {
+		t.Fatal(err)
+	}
+	if got.AsDuration() != 3*time.Second {
+		t.Fatalf("expected 3s, got %v\n", got.AsDuration())
+	}
+}
+
+func TestConnWithNilCodec(t *testing.T) {
+
+	if _, err := New(nil, nil); err != errMissingCodec {
+		t.Fatal(err)
+	}
+}
@@ -0,0 +1,387 @@
+package launcher
+
+import (
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"sync"
+)
+
+var errStreamOpen = errors.New("stream is already open")
+var errMuxClosed = errors.New("multiplexer is closed")
+var errFrameTooLarge = errors.New("frame exceeds the maximum size")
+var errWindowExceeded = errors.New("data exceeds the window of the stream")
+var errInvalidFrame = errors.New("invalid frame")
+var errTooManyStreams = errors.New("too many streams not yet opened")
+
+const (
+	// muxHeaderSize is the size of the header of a frame, being the
+	// stream id, the frame kind and the length of the payload
+	muxHeaderSize = 9
+	// maxMuxPayload limits the payload of a single frame
+	maxMuxPayload = 32 * 1024
+	// muxWindow is the unread data which may be held for a stream, which
+	// the other end may send before waiting for the reader to catch up
+	muxWindow = 256 * 1024
+	// maxUnopenedStreams limits the streams for which data is held before
+	// they are opened locally
+	maxUnopenedStreams = 64
+)
+
+const (
+	// frameData carries data written to a stream
+	frameData byte = iota
+	// frameClose marks the end of the data written to a stream
+	frameClose
+	// frameWindow allows the other end to send more data to a stream
+	frameWindow
+)
+
+// Mux carries multiple logical streams, each identified by an id, over
+// a single connection, so that one process may hold several concurrent
+// conversations over its stdin and stdout.  Each write to a stream is
+// sent as one or more frames, each having a header of the stream id
+// as a big endian uint32, a kind byte of 0 for data, 1 for the end of
+// the stream or 2 for a window update, and the length of the data as a
+// big endian uint32.  Each end may send at most 256KiB to a stream
+// before the reader catches up, with the data read being returned as a
+// big endian uint32 in a window update, so writes block while the
+// other end is not reading.  The same protocol is used at both ends of
+// the connection
+type Mux struct {
+	w        io.Writer
+	wmu      sync.Mutex
+	mu       sync.Mutex
+	cond     *sync.Cond
+	streams  map[uint32]*muxStream
+	unopened int
+	err      error
+	done     chan struct{}
+}
+
+// NewMux returns a Mux reading frames from r and writing them to w.
+// In the parent, both are the Launcher, whose process must be started;
+// in the child, they are os.Stdin and os.Stdout
+func NewMux(r io.Reader, w io.Writer) *Mux {
+	m := &Mux{
+		w:       w,
+		streams: map[uint32]*muxStream{},
+		done:    make(chan struct{}),
+	}
+	m.cond = sync.NewCond(&m.mu)
+	go m.receive(r)
+	return m
+}
+
+// OpenStream returns the stream with the id, which must be opened at
+// both ends of the connection.  Data arriving for a stream before it
+// is opened is held until it is read, for at most 64 streams, beyond
+// which the connection fails.  Closing the stream ends the data sent
+// to the other end, and discards any which is unread
+func (m *Mux) OpenStream(id uint32) (io.ReadWriteCloser, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	if m.err != nil {
+		return nil, m.err
+	}
+	s, ok := m.streams[id]
+	if !ok {
+		s = m.stream(id)
+	} else if s.opened {
+		return nil, fmt.Errorf("%w: %d", errStreamOpen, id)
+	} else {
+		m.unopened--
+	}
+	s.opened = true
+	return s, nil
+}
+
+// Close closes all streams, without closing the connection, and
+// returns the error ending the connection, if any
+func (m *Mux) Close() error {
+	m.mu.Lock()
+	err := m.err
+	if err == nil {
+		m.err = errMuxClosed
+	}
+	streams := m.streams
+	m.streams = map[uint32]*muxStream{}
+	m.unopened = 0
+	m.cond.Broadcast()
+	m.mu.Unlock()
+
+	for _, s := range streams {
+		s.buf.Close()
+	}
+	if err == errMuxClosed || err == io.EOF {
+		return nil
+	}
+	return err
+}
+
+// Done returns a channel which is closed once no more frames
+// can be received, when the connection has ended
+func (m *Mux) Done() <-chan struct{} {
+	return m.done
+}
+
+// stream returns the stream with the id, creating it if
+// necessary, and must be called with the lock held
+func (m *Mux) stream(id uint32) *muxStream {
+	s, ok := m.streams[id]
+	if !ok {
+		s = &muxStream{
+			m:      m,
+			id:     id,
+			buf:    newPipeBuffer(),
+			credit: muxWindow,
+			window: muxWindow,
+		}
+		m.streams[id] = s
+	}
+	return s
+}
+
+// receive reads frames until the connection ends, passing their
+// data to the streams to which they belong
+func (m *Mux) receive(r io.Reader) {
+	defer close(m.done)
+
+	var header [muxHeaderSize]byte
+	var err error
+	for {
+		if _, err = io.ReadFull(r, header[:]); err != nil {
+			break
+		}
+		id := binary.BigEndian.Uint32(header[0:4])
+		kind := header[4]
+		n := binary.BigEndian.Uint32(header[5:9])
+		if n > maxMuxPayload {
+			err = fmt.Errorf("%w: %d bytes", errFrameTooLarge, n)
+			break
+		}
+
+		data := make([]byte, n)
+		if _, err = io.ReadFull(r, data); err != nil {
+			break
+		}
+
+		var update uint32
+		if update, err = m.deliver(id, kind, data); err != nil {
+			break
+		}
+		if update > 0 {
+			m.sendWindow(id, update)
+		}
+	}
+
+	if err == io.ErrUnexpectedEOF {
+		err = io.EOF
+	}
+
+	m.mu.Lock()
+	if m.err == nil {
+		m.err = err
+	}
+	for _, s := range m.streams {
+		s.buf.CloseWithError(err)
+	}
+	m.cond.Broadcast()
+	m.mu.Unlock()
+}
+
+// deliver passes a received frame to its stream, returning the window
+// update to send for any data which is discarded
+func (m *Mux) deliver(id uint32, kind byte, data []byte) (uint32, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	if m.err != nil {
+		return 0, nil
+	}
+
+	s, ok := m.streams[id]
+	if kind > frameWindow {
+		return 0, fmt.Errorf("%w: kind %d", errInvalidFrame, kind)
+	}
+	if kind == frameWindow {
+		if len(data) != 4 {
+			return 0, fmt.Errorf("%w: window update of %d bytes", errInvalidFrame, len(data))
+		}
+		if ok {
+			s.credit += int(binary.BigEndian.Uint32(data))
+			m.cond.Broadcast()
+		}
+		return 0, nil
+	}
+
+	if !ok {
+		if m.unopened >= maxUnopenedStreams {
+			return 0, fmt.Errorf("%w: %d", errTooManyStreams, id)
+		}
+		s = m.stream(id)
+		m.unopened++
+	}
+
+	switch kind {
+	case frameData:
+		if len(data) > s.window {
+			return 0, fmt.Errorf("%w: %d", errWindowExceeded, id)
+		}
+		s.window -= len(data)
+		if s.localClosed {
+			// Data no longer wanted is returned to the window at once
+			s.window += len(data)
+			return uint32(len(data)), nil
+		}
+		s.buf.Write(data)
+	case frameClose:
+		s.buf.CloseWithError(io.EOF)
+		s.remoteClosed = true
+		m.release(s)
+	}
+	return 0, nil
+}
+
+// send writes a frame for the stream
+func (m *Mux) send(id uint32, kind byte, data []byte) error {
+	frame := make([]byte, muxHeaderSize+len(data))
+	binary.BigEndian.PutUint32(frame[0:4], id)
+	frame[4] = kind
+	binary.BigEndian.PutUint32(frame[5:9], uint32(len(data)))
+	copy(frame[muxHeaderSize:], data)
+
+	m.wmu.Lock()
+	defer m.wmu.Unlock()
+
+	_, err := m.w.Write(frame)
+	return err
+}
+
+// sendWindow allows the other end to send n more bytes to the stream
+func (m *Mux) sendWindow(id uint32, n uint32) error {
+	var b [4]byte
+	binary.BigEndian.PutUint32(b[:], n)
+	return m.send(id, frameWindow, b[:])
+}
+
+// release forgets the stream once it is closed at both ends, so that
+// its id may be reused, and must be called with the lock held
+func (m *Mux) release(s *muxStream) {
+	if s.localClosed && s.remoteClosed && m.streams[s.id] == s {
+		delete(m.streams, s.id)
+	}
+}
+
+// muxStream is a logical stream carried by a Mux
+type muxStream struct {
+	m            *Mux
+	id           uint32
+	buf          *pipeBuffer
+	opened       bool
+	localClosed  bool
+	remoteClosed bool
+	// credit is the data which may be sent before the other end reads
+	credit int
+	// window is the data which may be received before it is read, and
+	// unacked is that read but not yet returned in a window update
+	window  int
+	unacked int
+}
+
+// Read reads data sent to the stream by the other end, returning
+// it to the window of the stream once enough has been read
+func (s *muxStream) Read(p []byte) (int, error) {
+	n, err := s.buf.Read(p)
+	if n == 0 {
+		return n, err
+	}
+
+	s.m.mu.Lock()
+	s.unacked += n
+	update := 0
+	if s.unacked >= maxMuxPayload && !s.remoteClosed && s.m.err == nil {
+		update, s.unacked = s.unacked, 0
+		s.window += update
+	}
+	s.m.mu.Unlock()
+
+	if update > 0 {
+		s.m.sendWindow(s.id, uint32(update))
+	}
+	return n, err
+}
+
+// Write sends data to the other end of the stream, in frames no larger
+// than the maximum payload, blocking while its window is exhausted
+func (s *muxStream) Write(p []byte) (int, error) {
+	written := 0
+	for len(p) > 0 {
+		n, err := s.reserve(len(p))
+		if err != nil {
+			return written, err
+		}
+		if err := s.m.send(s.id, frameData, p[:n]); err != nil {
+			return written, err
+		}
+		written += n
+		p = p[n:]
+	}
+	return written, nil
+}
+
+// reserve waits until the stream may send data, and returns
+// how much of the wanted amount it may send in a frame
+func (s *muxStream) reserve(want int) (int, error) {
+	s.m.mu.Lock()
+	defer s.m.mu.Unlock()
+
+	for s.credit == 0 && !s.localClosed && s.m.err == nil {
+		s.m.cond.Wait()
+	}
+	if s.localClosed {
+		return 0, os.ErrClosed
+	}
+	if s.m.err != nil {
+		return 0, s.m.err
+	}
+
+	n := min(want, s.credit, maxMuxPayload)
+	s.credit -= n
+	return n, nil
+}
+
+// Close ends the data sent to the other end, and discards any unread
+func (s *muxStream) Close() error {
+	s.m.mu.Lock()
+	if s.localClosed {
+		s.m.mu.Unlock()
+		return os.ErrClosed
+	}
+	s.localClosed = true
+	s.m.release(s)
+	s.m.cond.Broadcast()
+	failed := s.m.err != nil
+
+	// The unread data is discarded, so is returned to the window
+	update := 0
+	if !s.remoteClosed {
+		update = muxWindow - s.window
+		s.window, s.unacked = muxWindow, 0
+	}
+	s.m.mu.Unlock()
+
+	s.buf.Close()
+	if failed {
+		return nil
+	}
+	if update > 0 {
+		if err := s.m.sendWindow(s.id, uint32(update)); err != nil {
+			return err
+		}
+	}
+	return s.m.send(s.id, frameClose, nil)
+}
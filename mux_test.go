@@ -0,0 +1,257 @@
+package launcher
+
+import (
+	"bytes"
+	"context"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"runtime"
+	"sync"
+	"testing"
+)
+
+func TestMuxOverProcess(t *testing.T) {
+
+	// cat echoes each frame, so each stream receives what it sends
+	l, err := New(context.Background(), "cat", nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer l.Close()
+
+	if err := l.Start(); err != nil {
+		t.Fatal(err)
+	}
+
+	m := NewMux(l, l)
+	defer m.Close()
+
+	var wg sync.WaitGroup
+	for id := uint32(1); id <= 3; id++ {
+		s, err := m.OpenStream(id)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		wg.Add(1)
+		go func(id uint32, s io.ReadWriteCloser) {
+			defer wg.Done()
+
+			want := bytes.Repeat([]byte(fmt.Sprintf("stream %d;", id)), 10000)
+			if _, err := s.Write(want); err != nil {
+				t.Error(err)
+				return
+			}
+			got := make([]byte, len(want))
+			if _, err := io.ReadFull(s, got); err != nil {
+				t.Error(err)
+				return
+			}
+			if !bytes.Equal(got, want) {
+				t.Errorf("stream %d received other data\n", id)
+			}
+		}(id, s)
+	}
+	wg.Wait()
+}
+
+func TestMuxEndToEnd(t *testing.T) {
+
+	parent, child := net.Pipe()
+	defer parent.Close()
+	defer child.Close()
+
+	a := NewMux(parent, parent)
+	b := NewMux(child, child)
+
+	sa, err := a.OpenStream(7)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := a.OpenStream(7); err == nil {
+		t.Fatal("expected error opening stream twice")
+	}
+
+	go func() {
+		sa.Write([]byte("hello"))
+		sa.Close()
+	}()
+
+	sb, err := b.OpenStream(7)
+	if err != nil {
+		t.Fatal(err)
+	}
+	got, err := io.ReadAll(sb)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if string(got) != "hello" {
+		t.Fatalf("expected %q, got %q\n", "hello", got)
+	}
+
+	// The connection ending ends all streams
+	parent.Close()
+	<-b.Done()
+	if _, err := b.OpenStream(8); err == nil {
+		t.Fatal("expected error opening stream once the connection has ended")
+	}
+}
+
+func TestMuxBackpressure(t *testing.T) {
+
+	parent, child := net.Pipe()
+	defer parent.Close()
+	defer child.Close()
+
+	a := NewMux(parent, parent)
+	defer a.Close()
+	b := NewMux(child, child)
+	defer b.Close()
+
+	sa, err := a.OpenStream(1)
+	if err != nil {
+		t.Fatal(err)
+	}
+	sb, err := b.OpenStream(1)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	want := bytes.Repeat([]byte("0123456789abcdef"), muxWindow/4)
+	written := make(chan error, 1)
+	go func() {
+		_, err := sa.Write(want)
+		written <- err
+	}()
+
+	// The writer blocks once the window is exhausted, with only
+	// the window being held for the reader
+	for {
+		a.mu.Lock()
+		credit := sa.(*muxStream).credit
+		a.mu.Unlock()
+		if credit == 0 {
+			break
+		}
+		runtime.Gosched()
+	}
+	select {
+	case err := <-written:
+		t.Fatalf("expected write to block, got %v\n", err)
+	default:
+	}
+	sbuf := sb.(*muxStream).buf
+	for {
+		sbuf.mu.Lock()
+		n := sbuf.buf.Len()
+		sbuf.mu.Unlock()
+		if n == muxWindow {
+			break
+		}
+		if n > muxWindow {
+			t.Fatalf("expected at most %d bytes held, got %d\n", muxWindow, n)
+		}
+		runtime.Gosched()
+	}
+
+	got := make([]byte, len(want))
+	if _, err := io.ReadFull(sb, got); err != nil {
+		t.Fatal(err)
+	}
+	if err := <-written; err != nil {
+		t.Fatal(err)
+	}
+	if !bytes.Equal(got, want) {
+		t.Fatal("received other data")
+	}
+}
+
+// muxFrame returns a raw frame, as sent by a peer
+func muxFrame(id uint32, kind byte, data []byte) []byte {
+	frame := make([]byte, muxHeaderSize+len(data))
+	binary.BigEndian.PutUint32(frame[0:4], id)
+	frame[4] = kind
+	binary.BigEndian.PutUint32(frame[5:9], uint32(len(data)))
+	copy(frame[muxHeaderSize:], data)
+	return frame
+}
+
+func TestMuxWindowExceeded(t *testing.T) {
+
+	parent, child := net.Pipe()
+	defer parent.Close()
+	defer child.Close()
+
+	m := NewMux(child, child)
+
+	// A peer ignoring the window fails the connection
+	go func() {
+		data := make([]byte, maxMuxPayload)
+		for i := 0; i <= muxWindow/maxMuxPayload; i++ {
+			if _, err := parent.Write(muxFrame(1, frameData, data)); err != nil {
+				return
+			}
+		}
+	}()
+
+	<-m.Done()
+	if err := m.Close(); !errors.Is(err, errWindowExceeded) {
+		t.Fatalf("expected %v, got %v\n", errWindowExceeded, err)
+	}
+}
+
+func TestMuxTooManyStreams(t *testing.T) {
+
+	parent, child := net.Pipe()
+	defer parent.Close()
+	defer child.Close()
+
+	m := NewMux(child, child)
+
+	// A peer sending to streams which are never opened fails the connection
+	go func() {
+		for id := uint32(1); id <= maxUnopenedStreams+1; id++ {
+			if _, err := parent.Write(muxFrame(id, frameData, []byte("x"))); err != nil {
+				return
+			}
+		}
+	}()
+
+	<-m.Done()
+	if err := m.Close(); !errors.Is(err, errTooManyStreams) {
+		t.Fatalf("expected %v, got %v\n", errTooManyStreams, err)
+	}
+}
+
+func TestMuxOpenedStreamsNotLimited(t *testing.T) {
+
+	parent, child := net.Pipe()
+	defer parent.Close()
+	defer child.Close()
+
+	m := NewMux(child, child)
+	defer m.Close()
+
+	// Streams opened locally do not count towards the limit
+	for id := uint32(1); id <= maxUnopenedStreams+1; id++ {
+		if _, err := m.OpenStream(id); err != nil {
+			t.Fatal(err)
+		}
+		if _, err := parent.Write(muxFrame(id, frameData, []byte("x"))); err != nil {
+			t.Fatal(err)
+		}
+	}
+	// The last frame is delivered once the next is read
+	if _, err := parent.Write(muxFrame(1, frameWindow, []byte{0, 0, 0, 0})); err != nil {
+		t.Fatal(err)
+	}
+
+	select {
+	case <-m.Done():
+		t.Fatal("expected the connection to remain open")
+	default:
+	}
+}
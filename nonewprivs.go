@@ -0,0 +1,23 @@
+package launcher
+
+import (
+	"errors"
+	"runtime"
+)
+
+var errNoNewPrivsUnsupported = errors.New("no_new_privs is only supported on linux")
+
+// WithNoNewPrivs sets the no_new_privs flag of the process, so that it
+// cannot gain privileges by executing setuid or setgid programs or those
+// with file capabilities.  The flag is set by re-executing the current
+// program as a shim before it execs the target, and is inherited by all
+// descendants of the process
+func WithNoNewPrivs() Option {
+	return func(o *options) error {
+		if runtime.GOOS != "linux" {
+			return errNoNewPrivsUnsupported
+		}
+		o.noNewPrivs = true
+		return nil
+	}
+}
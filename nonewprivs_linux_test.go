@@ -0,0 +1,37 @@
+package launcher
+
+import (
+	"context"
+	"io"
+	"strings"
+	"testing"
+)
+
+func TestNoNewPrivs(t *testing.T) {
+
+	for _, set := range []bool{false, true} {
+		var opts []Option
+		if set {
+			opts = append(opts, WithNoNewPrivs())
+		}
+
+		l, err := NewWithOptions(context.Background(), "grep", nil, []string{"NoNewPrivs", "/proc/self/status"}, opts...)
+		if err != nil {
+			t.Fatal(err)
+		}
+		defer l.Close()
+
+		if err := l.Run(); err != nil {
+			t.Fatal(err)
+		}
+		b, err := io.ReadAll(l.StdOutReader())
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		fields := strings.Fields(string(b))
+		if len(fields) != 2 || (fields[1] == "1") != set {
+			t.Fatalf("expected no_new_privs to be %v, got %q\n", set, b)
+		}
+	}
+}
@@ -0,0 +1,83 @@
+package launcher
+
+import (
+	"io"
+	"io/fs"
+	"net"
+	"os"
+	"time"
+
+	"golang.org/x/text/encoding"
+)
+
+// Option configures the optional behaviour of a Launcher,
+// returning an error if the configuration is invalid
+type Option func(o *options) error
+
+// options holds the optional configuration of a Launcher
+type options struct {
+	readiness        Probe
+	startupTimeout   time.Duration
+	reloadSignal     os.Signal
+	binaryRestart    bool
+	heartbeat        *heartbeat
+	idleTimeout      time.Duration
+	limiter          *Limiter
+	breaker          *Breaker
+	retryable        RetryClassifier
+	lockPath         string
+	pidFile          string
+	detachedStdout   string
+	detachedStderr   string
+	killTree         bool
+	closeGrace       time.Duration
+	redactor         Redactor
+	secretEnv        map[string]bool
+	secretArgs       map[int]bool
+	secretFDs        []secretFD
+	extraFiles       []extraFile
+	listeners        []net.Listener
+	inheritEnv       bool
+	envAllow         []string
+	envDeny          []string
+	policies         []Policy
+	seccomp          *SeccompProfile
+	landlock         *LandlockRules
+	label            string
+	noNewPrivs       bool
+	tempWorkdir      *tempWorkdir
+	stdinBytes       []byte
+	stdinFS          fs.FS
+	stdinName        string
+	resolver         Resolver
+	lookupDirs       []string
+	cache            *ResolverCache
+	expand           bool
+	expansion        func(name string) string
+	lazyLookup       bool
+	dir              string
+	args             []string
+	env              []string
+	maxCaptured      int
+	compressCapture  bool
+	ringLines        int
+	lossyOutput      bool
+	combined         bool
+	sinks            []OutputSink
+	lineTimestamps   bool
+	prefix           string
+	prefixColour     Colour
+	stripANSI        bool
+	encoding         encoding.Encoding
+	discardOutput    bool
+	inheritStdio     bool
+	outputFile       string
+	rotation         RotationPolicy
+	transcript       io.Writer
+	transcriptFormat TranscriptFormat
+	stderrTail       int
+	pipeBufferSize   int
+	correlationID    string
+	correlationEnv   string
+	metrics          MetricsSink
+}
@@ -0,0 +1,327 @@
+package launcher
+
+import (
+	"bytes"
+	"context"
+	"io"
+	"os"
+	"sync"
+)
+
+// lineWriter is an io.Writer that passes each complete line
+// written to it, without its line ending, to emit
+type lineWriter struct {
+	buf  []byte
+	emit func(line string)
+}
+
+func newLineWriter(emit func(line string)) *lineWriter {
+	return &lineWriter{emit: emit}
+}
+
+// Write buffers b, emitting any complete lines
+func (w *lineWriter) Write(b []byte) (int, error) {
+	w.buf = append(w.buf, b...)
+
+	for {
+		i := bytes.IndexByte(w.buf, '\n')
+		if i < 0 {
+			break
+		}
+		w.emit(string(bytes.TrimSuffix(w.buf[:i], []byte("\r"))))
+		w.buf = w.buf[i+1:]
+	}
+	return len(b), nil
+}
+
+// Flush emits any incomplete final line
+func (w *lineWriter) Flush() {
+	if len(w.buf) > 0 {
+		w.emit(string(w.buf))
+		w.buf = nil
+	}
+}
+
+// copyBuffers holds the buffers used to copy the output of processes,
+// which would otherwise be allocated afresh by each copy
+var copyBuffers = sync.Pool{
+	New: func() any {
+		b := make([]byte, 32*1024)
+		return &b
+	},
+}
+
+// pooledCopy copies from r to w in the manner of io.Copy, but always
+// using a pooled buffer, as the WriteTo and ReadFrom methods of an
+// *os.File allocate buffers of their own
+func pooledCopy(w io.Writer, r io.Reader) (int64, error) {
+	b := copyBuffers.Get().(*[]byte)
+	defer copyBuffers.Put(b)
+
+	return io.CopyBuffer(struct{ io.Writer }{w}, struct{ io.Reader }{r}, *b)
+}
+
+// maxUnreadOutput limits the output held for the caller by a Launcher
+// which observes the output itself, as for WithOutputRingBuffer, beyond
+// which the process is blocked until the caller reads, as for a pipe
+const maxUnreadOutput = 4 * 1024 * 1024
+
+// WithLossyOutput discards the oldest output not yet read by the caller,
+// rather than blocking the process, once 4MiB is held by a Launcher
+// which observes the output itself, as for WithOutputRingBuffer.  This
+// allows the output to be observed without being read, with the number
+// of bytes discarded returned by DroppedOutput
+func WithLossyOutput() Option {
+	return func(o *options) error {
+		o.lossyOutput = true
+		return nil
+	}
+}
+
+// DroppedOutput returns the number of bytes of stdout and of stderr
+// discarded as they were not read, as allowed by WithLossyOutput
+func (l *Launcher) DroppedOutput() (stdout, stderr int64) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	if buf, ok := l.cmdStdOut.(*pipeBuffer); ok {
+		stdout = buf.droppedBytes()
+	}
+	if buf, ok := l.cmdStdErr.(*pipeBuffer); ok {
+		stderr = buf.droppedBytes()
+	}
+	return stdout, stderr
+}
+
+// pipeBuffer is an in-memory pipe.  If it has a limit, writes block
+// until the reader has drained the buffer below it, or if lossy, the
+// oldest unread data is discarded to keep within it.  Otherwise the
+// buffer is unbounded, and writes never block
+type pipeBuffer struct {
+	mu      sync.Mutex
+	cond    *sync.Cond
+	buf     bytes.Buffer
+	limit   int
+	lossy   bool
+	dropped int64
+	err     error
+	closed  bool
+}
+
+func newPipeBuffer() *pipeBuffer {
+	p := &pipeBuffer{}
+	p.cond = sync.NewCond(&p.mu)
+	return p
+}
+
+// newOutputBuffer returns a pipeBuffer holding about maxUnreadOutput
+// bytes of the output of a process, discarding the oldest if lossy
+func newOutputBuffer(lossy bool) *pipeBuffer {
+	p := newPipeBuffer()
+	p.limit = maxUnreadOutput
+	p.lossy = lossy
+	return p
+}
+
+// Write appends b to the buffer, waiting for the reader if the buffer
+// is full, and discarding b if the reader is closed
+func (p *pipeBuffer) Write(b []byte) (int, error) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	n := len(b)
+	if p.limit > 0 && !p.lossy {
+		for p.buf.Len() >= p.limit && !p.closed {
+			p.cond.Wait()
+		}
+	}
+	if p.closed {
+		return n, nil
+	}
+	if p.limit > 0 && p.lossy {
+		if len(b) > p.limit {
+			p.dropped += int64(len(b) - p.limit)
+			b = b[len(b)-p.limit:]
+		}
+		if over := p.buf.Len() + len(b) - p.limit; over > 0 {
+			p.dropped += int64(over)
+			p.buf.Next(over)
+		}
+	}
+	p.buf.Write(b)
+	p.cond.Broadcast()
+	return n, nil
+}
+
+// droppedBytes returns the number of bytes discarded as the buffer was full
+func (p *pipeBuffer) droppedBytes() int64 {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	return p.dropped
+}
+
+// CloseWithError marks the end of writing, so that once the buffer is
+// drained, reads return err, or io.EOF if err is nil
+func (p *pipeBuffer) CloseWithError(err error) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	if err == nil {
+		err = io.EOF
+	}
+	if p.err == nil {
+		p.err = err
+	}
+	p.cond.Broadcast()
+}
+
+// Read blocks until data is available or writing has ended
+func (p *pipeBuffer) Read(b []byte) (int, error) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	for p.buf.Len() == 0 && p.err == nil && !p.closed {
+		p.cond.Wait()
+	}
+	if p.closed {
+		return 0, os.ErrClosed
+	}
+	if p.buf.Len() > 0 {
+		p.cond.Broadcast()
+		return p.buf.Read(b)
+	}
+	return 0, p.err
+}
+
+// Close closes the reader, discarding any buffered data
+func (p *pipeBuffer) Close() error {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	p.closed = true
+	p.buf.Reset()
+	p.cond.Broadcast()
+	return nil
+}
+
+// outputFilter transforms the output of the process written to
+// the writer it returns, before passing it on to w
+type outputFilter func(w io.Writer) io.Writer
+
+// outputFilters returns the filters applied to the output
+// of the process, in the order they are applied
+func (l *Launcher) outputFilters() []outputFilter {
+	var filters []outputFilter
+	if l.opts.encoding != nil {
+		filters = append(filters, l.decodeOutput)
+	}
+	if l.opts.stripANSI {
+		filters = append(filters, stripANSI)
+	}
+	if l.opts.lineTimestamps {
+		filters = append(filters, l.timestampLines)
+	}
+	// The prefix is written ahead of any timestamp
+	if l.opts.prefix != "" {
+		filters = append(filters, l.prefixLines)
+	}
+	return filters
+}
+
+// filterChain returns a writer passing its output through each filter
+// to w, along with a function flushing any output held by the filters
+func filterChain(w io.Writer, filters []outputFilter) (io.Writer, func()) {
+	writers := make([]io.Writer, len(filters))
+	for i := len(filters) - 1; i >= 0; i-- {
+		w = filters[i](w)
+		writers[i] = w
+	}
+
+	flush := func() {
+		for _, w := range writers {
+			if f, ok := w.(interface{ Flush() }); ok {
+				f.Flush()
+			}
+		}
+	}
+	return w, flush
+}
+
+// prefixWriter is an io.Writer which passes output to w with
+// the result of prefix written at the start of each line
+type prefixWriter struct {
+	w        io.Writer
+	prefix   func() []byte
+	midLine  bool
+	combined []byte
+}
+
+func (p *prefixWriter) Write(b []byte) (int, error) {
+	out := p.combined[:0]
+	for rest := b; len(rest) > 0; {
+		if !p.midLine {
+			out = append(out, p.prefix()...)
+			p.midLine = true
+		}
+		i := bytes.IndexByte(rest, '\n') + 1
+		if i == 0 {
+			i = len(rest)
+		} else {
+			p.midLine = false
+		}
+		out = append(out, rest[:i]...)
+		rest = rest[i:]
+	}
+	p.combined = out
+
+	if _, err := p.w.Write(out); err != nil {
+		return 0, err
+	}
+	return len(b), nil
+}
+
+// readAvailable returns up to max buffered bytes without blocking,
+// or the error ending writing once the buffer is drained
+func (p *pipeBuffer) readAvailable(max int) ([]byte, error) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	if p.closed {
+		return nil, os.ErrClosed
+	}
+	if p.buf.Len() > 0 {
+		p.cond.Broadcast()
+		return append([]byte{}, p.buf.Next(max)...), nil
+	}
+	return nil, p.err
+}
+
+// readContext is Read, returning the error of the context
+// if it ends before data is available
+func (p *pipeBuffer) readContext(ctx context.Context, b []byte) (int, error) {
+	stop := context.AfterFunc(ctx, func() {
+		p.mu.Lock()
+		p.cond.Broadcast()
+		p.mu.Unlock()
+	})
+	defer stop()
+
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	for p.buf.Len() == 0 && p.err == nil && !p.closed {
+		if err := ctx.Err(); err != nil {
+			return 0, err
+		}
+		p.cond.Wait()
+	}
+	if p.closed {
+		return 0, os.ErrClosed
+	}
+	if p.buf.Len() > 0 {
+		p.cond.Broadcast()
+		return p.buf.Read(b)
+	}
+	return 0, p.err
+}
@@ -0,0 +1,40 @@
+package launcher
+
+import (
+	"io"
+	"strings"
+	"testing"
+)
+
+func TestLineWriter(t *testing.T) {
+
+	lines := []string{}
+	w := newLineWriter(func(line string) {
+		lines = append(lines, line)
+	})
+
+	w.Write([]byte("foo\r\nba"))
+	w.Write([]byte("r\n\nbaz"))
+	w.Flush()
+
+	expected := "foo|bar||baz"
+	if strings.Join(lines, "|") != expected {
+		t.Fatalf("expected %q, got %q\n", expected, strings.Join(lines, "|"))
+	}
+}
+
+func TestPipeBuffer(t *testing.T) {
+
+	p := newPipeBuffer()
+	p.Write([]byte("foo"))
+	p.Write([]byte("bar"))
+	p.CloseWithError(nil)
+
+	b, err := io.ReadAll(p)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if string(b) != "foobar" {
+		t.Fatalf("expected %q, got %q\n", "foobar", b)
+	}
+}
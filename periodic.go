@@ -0,0 +1,70 @@
+package launcher
+
+import (
+	"context"
+	"errors"
+	"time"
+)
+
+var errInvalidInterval = errors.New("interval must be positive")
+
+// RunEvery launches a fresh Launcher from the Spec every interval d,
+// starting with the first immediately, until the context ends.  A run
+// still in progress when the next is due is killed, so that runs never
+// overlap.  The RunRecord of each run is sent on the returned channel,
+// which must be drained and is closed once the final run has finished.
+// The output of the runs is discarded
+func RunEvery(ctx context.Context, d time.Duration, spec Spec) (<-chan RunRecord, error) {
+	if ctx == nil {
+		return nil, ErrMissingContext
+	}
+	if d <= 0 {
+		return nil, errInvalidInterval
+	}
+
+	results := make(chan RunRecord)
+	go func() {
+		defer close(results)
+
+		ticker := time.NewTicker(d)
+		defer ticker.Stop()
+
+		for {
+			runCtx, cancel := context.WithCancel(ctx)
+			done := make(chan RunRecord, 1)
+			go func() {
+				done <- runSpec(runCtx, spec.File, spec, nil, nil, nil)
+			}()
+
+			var rec RunRecord
+			due := false
+			select {
+			case rec = <-done:
+			case <-ticker.C:
+				cancel()
+				rec = <-done
+				due = true
+			case <-ctx.Done():
+				cancel()
+				rec = <-done
+			}
+			cancel()
+
+			select {
+			case results <- rec:
+			case <-ctx.Done():
+				return
+			}
+
+			if !due {
+				select {
+				case <-ticker.C:
+				case <-ctx.Done():
+					return
+				}
+			}
+		}
+	}()
+
+	return results, nil
+}
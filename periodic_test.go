@@ -0,0 +1,69 @@
+package launcher
+
+import (
+	"context"
+	"testing"
+	"time"
+)
+
+func TestRunEvery(t *testing.T) {
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	results, err := RunEvery(ctx, 50*time.Millisecond, Spec{File: "echo", Args: []string{"hi"}})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var pids []int
+	for rec := range results {
+		if rec.Err != nil || rec.ExitCode != 0 {
+			t.Fatalf("unexpected record %+v\n", rec)
+		}
+		pids = append(pids, rec.Pid)
+		if len(pids) == 3 {
+			cancel()
+		}
+	}
+
+	if len(pids) < 3 || pids[0] == pids[1] || pids[1] == pids[2] {
+		t.Fatalf("expected fresh process for each run, got %v\n", pids)
+	}
+}
+
+func TestRunEveryKillsOverrun(t *testing.T) {
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	results, err := RunEvery(ctx, 100*time.Millisecond, Spec{File: "sleep", Args: []string{"10"}})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var recs []RunRecord
+	for rec := range results {
+		recs = append(recs, rec)
+		if len(recs) == 2 {
+			cancel()
+		}
+	}
+
+	if recs[1].Start.Before(recs[0].End) {
+		t.Fatal("runs overlapped")
+	}
+	if recs[0].Err == nil || recs[0].End.Sub(recs[0].Start) > 2*time.Second {
+		t.Fatalf("expected first run to be killed, got %+v\n", recs[0])
+	}
+}
+
+func TestRunEveryInvalid(t *testing.T) {
+
+	if _, err := RunEvery(context.Background(), 0, Spec{File: "echo"}); err != errInvalidInterval {
+		t.Fatalf("expected errInvalidInterval, got %v\n", err)
+	}
+	if _, err := RunEvery(nil, time.Second, Spec{File: "echo"}); err != ErrMissingContext {
+		t.Fatalf("expected ErrMissingContext, got %v\n", err)
+	}
+}
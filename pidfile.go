@@ -0,0 +1,118 @@
+package launcher
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strconv"
+)
+
+// WithPIDFile records the process id of the started process in the file
+// at path, which is removed when the process exits.  The Launcher refuses
+// to start, returning ErrAlreadyRunning, if the file holds the id of a
+// process which is still running, whilst files left by processes which
+// have exited are replaced
+func WithPIDFile(path string) Option {
+	return func(o *options) error {
+		if path == "" {
+			return errMissingPath
+		}
+		o.pidFile = path
+		return nil
+	}
+}
+
+// claimPIDFile creates the file at path holding the id of this process,
+// which is replaced by that of the started process, so that concurrent
+// starts cannot both find no running process.  The file is created with
+// its content by linking a temporary file, which fails if it exists.  A
+// file left by a process which has exited is replaced, whilst one held
+// by a running process, including this one while it is starting another,
+// returns ErrAlreadyRunning.  It returns the function removing the claim
+func claimPIDFile(path string) (func(), error) {
+	tmp, err := writeTempPIDFile(path, os.Getpid())
+	if err != nil {
+		return nil, err
+	}
+	defer os.Remove(tmp)
+
+	for attempt := 0; ; attempt++ {
+		err := os.Link(tmp, path)
+		if err == nil {
+			return removePIDFile(path, os.Getpid()), nil
+		}
+		if !errors.Is(err, os.ErrExist) {
+			return nil, err
+		}
+
+		b, err := os.ReadFile(path)
+		if errors.Is(err, os.ErrNotExist) {
+			continue
+		}
+		if err != nil {
+			return nil, err
+		}
+		if pid, err := strconv.Atoi(string(bytes.TrimSpace(b))); err == nil && pid > 0 && processAlive(pid) {
+			return nil, fmt.Errorf("%w: pid %d in %s", ErrAlreadyRunning, pid, path)
+		}
+		if attempt > 0 {
+			// The file is being replaced by another starter
+			return nil, fmt.Errorf("%w: %s is being claimed", ErrAlreadyRunning, path)
+		}
+
+		// An unreadable file, or one holding the id of a process which has
+		// exited, is stale, and removed unless it has since been replaced
+		if current, err := os.ReadFile(path); err == nil && bytes.Equal(current, b) {
+			if err := os.Remove(path); err != nil && !errors.Is(err, os.ErrNotExist) {
+				return nil, err
+			}
+		}
+	}
+}
+
+// writePIDFile atomically replaces the file at path with one holding
+// the pid, returning the function which removes it
+func writePIDFile(path string, pid int) (func(), error) {
+	tmp, err := writeTempPIDFile(path, pid)
+	if err != nil {
+		return nil, err
+	}
+	if err := os.Rename(tmp, path); err != nil {
+		os.Remove(tmp)
+		return nil, err
+	}
+	return removePIDFile(path, pid), nil
+}
+
+// writeTempPIDFile writes the pid to a temporary file beside path,
+// returning its name
+func writeTempPIDFile(path string, pid int) (string, error) {
+	tmp, err := os.CreateTemp(filepath.Dir(path), filepath.Base(path)+".*")
+	if err != nil {
+		return "", err
+	}
+	_, err = fmt.Fprintf(tmp, "%d\n", pid)
+	if closeErr := tmp.Close(); err == nil {
+		err = closeErr
+	}
+	if err == nil {
+		err = os.Chmod(tmp.Name(), 0o644)
+	}
+	if err != nil {
+		os.Remove(tmp.Name())
+		return "", err
+	}
+	return tmp.Name(), nil
+}
+
+// removePIDFile returns the function removing the file at path,
+// only if it has not since been replaced
+func removePIDFile(path string, pid int) func() {
+	return func() {
+		if b, err := os.ReadFile(path); err == nil && string(bytes.TrimSpace(b)) == strconv.Itoa(pid) {
+			os.Remove(path)
+		}
+	}
+}
@@ -0,0 +1,178 @@
+package launcher
+
+import (
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"testing"
+)
+
+func TestPIDFile(t *testing.T) {
+
+	path := filepath.Join(t.TempDir(), "job.pid")
+
+	l, err := NewWithOptions(context.Background(), "sleep", nil, []string{"10"}, WithPIDFile(path))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer l.Close()
+
+	if err := l.Start(); err != nil {
+		t.Fatal(err)
+	}
+
+	b, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if strings.TrimSpace(string(b)) != strconv.Itoa(l.Pid()) {
+		t.Fatalf("expected pid %v, got %q\n", l.Pid(), b)
+	}
+
+	other, err := NewWithOptions(context.Background(), "echo", nil, nil, WithPIDFile(path))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer other.Close()
+	if err := other.Start(); !errors.Is(err, ErrAlreadyRunning) {
+		t.Fatalf("expected ErrAlreadyRunning, got %v\n", err)
+	}
+
+	l.Cancel()
+	l.Wait()
+
+	if _, err := os.Stat(path); !os.IsNotExist(err) {
+		t.Fatalf("expected pid file to be removed, got %v\n", err)
+	}
+}
+
+func TestPIDFileStale(t *testing.T) {
+
+	path := filepath.Join(t.TempDir(), "job.pid")
+
+	// Find the pid of a process which has exited
+	dead, err := New(context.Background(), "echo", nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := dead.Run(); err != nil {
+		t.Fatal(err)
+	}
+	dead.Close()
+
+	if err := os.WriteFile(path, []byte(strconv.Itoa(dead.Pid())+"\n"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	l, err := NewWithOptions(context.Background(), "sh", nil, []string{"-c", "sleep 0.2; cat " + path}, WithPIDFile(path))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer l.Close()
+
+	var out strings.Builder
+	if err := l.Start(); err != nil {
+		t.Fatal(err)
+	}
+	l.copyOutput(&out, nil)
+	if err := l.Wait(); err != nil {
+		t.Fatal(err)
+	}
+
+	if strings.TrimSpace(out.String()) != strconv.Itoa(l.Pid()) {
+		t.Fatalf("expected stale pid file to be replaced, got %q\n", out.String())
+	}
+}
+
+func TestPIDFileConcurrentStarts(t *testing.T) {
+
+	path := filepath.Join(t.TempDir(), "job.pid")
+
+	const n = 8
+	launchers := make([]*Launcher, n)
+	for i := range launchers {
+		l, err := NewWithOptions(context.Background(), "sleep", nil, []string{"10"}, WithPIDFile(path))
+		if err != nil {
+			t.Fatal(err)
+		}
+		defer l.Close()
+		launchers[i] = l
+	}
+
+	// Only one of the Launchers may claim the file
+	errs := make(chan error, n)
+	for _, l := range launchers {
+		go func(l *Launcher) { errs <- l.Start() }(l)
+	}
+	started := 0
+	for i := 0; i < n; i++ {
+		err := <-errs
+		switch {
+		case err == nil:
+			started++
+		case !errors.Is(err, ErrAlreadyRunning):
+			t.Fatalf("expected ErrAlreadyRunning, got %v\n", err)
+		}
+	}
+	if started != 1 {
+		t.Fatalf("expected one process to start, got %v\n", started)
+	}
+
+	for _, l := range launchers {
+		if l.State() == StateRunning {
+			continue
+		}
+		if l.State() != StateFailed || l.Pid() != 0 {
+			t.Fatalf("expected refused Launchers to have failed, got %v\n", l.State())
+		}
+	}
+}
+
+func TestPIDFileUnwritable(t *testing.T) {
+
+	path := filepath.Join(t.TempDir(), "missing", "job.pid")
+
+	l, err := NewWithOptions(context.Background(), "sleep", nil, []string{"10"}, WithPIDFile(path))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer l.Close()
+
+	if err := l.Start(); err == nil {
+		t.Fatal("expected error when the pid file cannot be written")
+	}
+	if l.State() != StateFailed || l.Pid() != 0 {
+		t.Fatalf("expected no process to start, got %v with pid %v\n", l.State(), l.Pid())
+	}
+}
+
+func TestPIDFileRecordFailure(t *testing.T) {
+
+	l, err := NewWithOptions(context.Background(), "sleep", nil, []string{"10"},
+		WithPIDFile(filepath.Join(t.TempDir(), "missing", "job.pid")))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer l.Close()
+
+	if err := startChild(l.cmd); err != nil {
+		t.Fatal(err)
+	}
+
+	// A process whose pid cannot be recorded is killed and reaped
+	l.mu.Lock()
+	err = l.recordPID()
+	l.mu.Unlock()
+	if err == nil {
+		t.Fatal("expected error when the pid file cannot be written")
+	}
+	if l.cmd.ProcessState == nil || l.cmd.ProcessState.Success() {
+		t.Fatalf("expected the process to be killed, got %v\n", l.cmd.ProcessState)
+	}
+	if l.IsStarted() {
+		t.Fatal("expected the Launcher to be left unstarted")
+	}
+}
@@ -0,0 +1,27 @@
+package launcher
+
+import "os"
+
+// WithPipeBufferSize requests that the kernel buffers of the pipes to the
+// stdin, stdout and stderr of the process hold n bytes, rather than the
+// default of 64KiB on Linux, which greatly improves the throughput of
+// processes streaming large volumes of data.  The kernel may round the
+// size up, and it is limited to /proc/sys/fs/pipe-max-size.  The option
+// has no effect on other platforms
+func WithPipeBufferSize(n int) Option {
+	return func(o *options) error {
+		if n <= 0 {
+			return errInvalidSize
+		}
+		o.pipeBufferSize = n
+		return nil
+	}
+}
+
+// resizePipe applies the size set by WithPipeBufferSize to the pipe
+func (l *Launcher) resizePipe(f *os.File) error {
+	if l.opts.pipeBufferSize == 0 {
+		return nil
+	}
+	return setPipeSize(f, l.opts.pipeBufferSize)
+}
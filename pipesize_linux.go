@@ -0,0 +1,60 @@
+//go:build linux
+
+package launcher
+
+import (
+	"errors"
+	"os"
+	"strconv"
+	"strings"
+
+	"golang.org/x/sys/unix"
+)
+
+// setPipeSize sets the capacity of the pipe, reducing the size
+// to the system limit if it is too large to be permitted
+func setPipeSize(f *os.File, n int) error {
+	if max := maxPipeSize(); max > 0 && n > max {
+		n = max
+	}
+
+	raw, err := f.SyscallConn()
+	if err != nil {
+		return err
+	}
+	var serr error
+	err = raw.Control(func(fd uintptr) {
+		_, serr = unix.FcntlInt(fd, unix.F_SETPIPE_SZ, n)
+	})
+	if errors.Is(serr, unix.EPERM) {
+		// The per-user limit on pipe buffers has been reached,
+		// but the pipe remains usable with its existing size
+		serr = nil
+	}
+	return errors.Join(err, serr)
+}
+
+// pipeSize returns the capacity of the pipe
+func pipeSize(f *os.File) (int, error) {
+	raw, err := f.SyscallConn()
+	if err != nil {
+		return 0, err
+	}
+	var n int
+	var serr error
+	err = raw.Control(func(fd uintptr) {
+		n, serr = unix.FcntlInt(fd, unix.F_GETPIPE_SZ, 0)
+	})
+	return n, errors.Join(err, serr)
+}
+
+// maxPipeSize returns the largest pipe capacity permitted
+// to an unprivileged process, or 0 if it is unknown
+func maxPipeSize() int {
+	b, err := os.ReadFile("/proc/sys/fs/pipe-max-size")
+	if err != nil {
+		return 0
+	}
+	n, _ := strconv.Atoi(strings.TrimSpace(string(b)))
+	return n
+}
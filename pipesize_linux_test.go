@@ -0,0 +1,55 @@
+package launcher
+
+import (
+	"bytes"
+	"context"
+	"io"
+	"os"
+	"testing"
+)
+
+func TestWithPipeBufferSize(t *testing.T) {
+
+	l, err := NewWithOptions(context.Background(), "cat", nil, nil, WithPipeBufferSize(256*1024))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer l.Close()
+
+	expected := min(256*1024, maxPipeSize())
+	for _, f := range []*os.File{l.cmd.Stdin.(*os.File), l.cmd.Stdout.(*os.File), l.cmd.Stderr.(*os.File)} {
+		n, err := pipeSize(f)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if n < expected {
+			t.Fatalf("expected pipe size of at least %v, got %v\n", expected, n)
+		}
+	}
+
+	if err := l.Start(); err != nil {
+		t.Fatal(err)
+	}
+
+	input := bytes.Repeat([]byte("0123456789abcdef"), 65536)
+	go func() {
+		w := l.StdinWriter()
+		w.Write(input)
+		w.Close()
+	}()
+
+	output, err := io.ReadAll(l)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !bytes.Equal(output, input) {
+		t.Fatalf("expected %v bytes, got %v\n", len(input), len(output))
+	}
+}
+
+func TestWithPipeBufferSizeInvalid(t *testing.T) {
+
+	if _, err := NewWithOptions(context.Background(), "cat", nil, nil, WithPipeBufferSize(0)); err == nil {
+		t.Fatal("expected error for zero size")
+	}
+}
@@ -0,0 +1,10 @@
+//go:build !linux
+
+package launcher
+
+import "os"
+
+// setPipeSize is not supported, so the pipe retains its default size
+func setPipeSize(f *os.File, n int) error {
+	return nil
+}
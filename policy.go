@@ -0,0 +1,171 @@
+package launcher
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"os/user"
+	"path/filepath"
+	"strconv"
+	"strings"
+)
+
+// ErrPolicyDenied is matched by the errors returned when
+// a Policy denies the launch of a process
+var ErrPolicyDenied = errors.New("launch denied by policy")
+
+var errMissingPolicy = errors.New("policy must be provided")
+
+// PolicyStage identifies when a Policy is consulted
+type PolicyStage string
+
+const (
+	// PolicyAtNew is the stage at which a Launcher is created
+	PolicyAtNew PolicyStage = "new"
+	// PolicyAtStart is the stage at which its process is started
+	PolicyAtStart PolicyStage = "start"
+)
+
+// PolicyRequest describes a launch for a Policy to allow or deny
+type PolicyRequest struct {
+	Stage PolicyStage
+	// Path is the resolved path of the command
+	Path string
+	// Args and Env hold the real values, including any secrets
+	Args []string
+	Env  []string
+	// User is the name of the user the process will run as,
+	// or their id if the name cannot be determined
+	User string
+}
+
+// Policy decides whether a process may be launched
+type Policy interface {
+	// Check returns an error describing why the launch is denied,
+	// or nil if it is allowed
+	Check(req PolicyRequest) error
+}
+
+// PolicyFunc is a Policy implemented by a function
+type PolicyFunc func(req PolicyRequest) error
+
+// Check calls the function
+func (f PolicyFunc) Check(req PolicyRequest) error {
+	return f(req)
+}
+
+// PolicyError records the denial of a launch by a Policy, for audit.
+// It matches ErrPolicyDenied as well as the error from the Policy
+type PolicyError struct {
+	Stage PolicyStage
+	Path  string
+	// Args are masked as for GetArgs
+	Args []string
+	User string
+	Err  error
+}
+
+func (e *PolicyError) Error() string {
+	return fmt.Sprintf("%v: %s at %s: %v", ErrPolicyDenied, e.Path, e.Stage, e.Err)
+}
+
+func (e *PolicyError) Unwrap() []error {
+	return []error{ErrPolicyDenied, e.Err}
+}
+
+// WithPolicy requires the Policy to allow the launch, both when the
+// Launcher is created and when its process is started.  A denial is
+// returned as a *PolicyError
+func WithPolicy(p Policy) Option {
+	return func(o *options) error {
+		if p == nil {
+			return errMissingPolicy
+		}
+		o.policies = append(o.policies, p)
+		return nil
+	}
+}
+
+// AllOf returns a Policy which allows a launch only if all the policies do
+func AllOf(policies ...Policy) Policy {
+	return PolicyFunc(func(req PolicyRequest) error {
+		for _, p := range policies {
+			if err := p.Check(req); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+}
+
+// AllowPathPrefixes returns a Policy which allows only commands whose
+// resolved path lies within one of the directories
+func AllowPathPrefixes(dirs ...string) Policy {
+	return PolicyFunc(func(req PolicyRequest) error {
+		for _, d := range dirs {
+			rel, err := filepath.Rel(filepath.Clean(d), req.Path)
+			if err == nil && rel != ".." && !strings.HasPrefix(rel, ".."+string(filepath.Separator)) {
+				return nil
+			}
+		}
+		return fmt.Errorf("%s is not within an allowed directory", req.Path)
+	})
+}
+
+// interpreters are the commands denied by DenyInterpreters
+var interpreters = []string{
+	"sh", "bash", "dash", "zsh", "ksh", "csh", "tcsh", "fish",
+	"cmd", "powershell", "pwsh",
+	"python", "python2", "python3", "perl", "ruby", "node", "php", "lua",
+}
+
+// DenyInterpreters returns a Policy which denies shells and scripting
+// language interpreters, including versioned names such as python3.11
+func DenyInterpreters() Policy {
+	return PolicyFunc(func(req PolicyRequest) error {
+		base := strings.ToLower(filepath.Base(req.Path))
+		base = strings.TrimSuffix(base, ".exe")
+		name := strings.TrimRight(base, "0123456789.")
+		for _, i := range interpreters {
+			if base == i || name == i {
+				return fmt.Errorf("%s is an interpreter", req.Path)
+			}
+		}
+		return nil
+	})
+}
+
+// checkPolicies consults the policies of the Launcher for the stage
+func (l *Launcher) checkPolicies(stage PolicyStage) error {
+	if len(l.opts.policies) == 0 {
+		return nil
+	}
+
+	req := PolicyRequest{
+		Stage: stage,
+		Path:  l.path,
+		Args:  l.copyStringArray(l.cmd.Args[1:]),
+		Env:   withoutShimEnv(l.cmd.Env),
+		User:  currentUser(),
+	}
+	for _, p := range l.opts.policies {
+		if err := p.Check(req); err != nil {
+			return &PolicyError{
+				Stage: stage,
+				Path:  l.path,
+				Args:  l.GetArgs(),
+				User:  req.User,
+				Err:   err,
+			}
+		}
+	}
+	return nil
+}
+
+// currentUser returns the name of the user running this process
+func currentUser() string {
+	if u, err := user.Current(); err == nil {
+		return u.Username
+	}
+	return strconv.Itoa(os.Getuid())
+}
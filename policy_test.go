@@ -0,0 +1,80 @@
+package launcher
+
+import (
+	"context"
+	"errors"
+	"path/filepath"
+	"testing"
+)
+
+func TestPolicyDenyInterpreters(t *testing.T) {
+
+	_, err := NewWithOptions(context.Background(), "sh", nil, []string{"-c", "echo"}, WithPolicy(DenyInterpreters()))
+
+	var policyErr *PolicyError
+	if !errors.As(err, &policyErr) || !errors.Is(err, ErrPolicyDenied) {
+		t.Fatalf("expected PolicyError, got %v\n", err)
+	}
+	if policyErr.Stage != PolicyAtNew || filepath.Base(policyErr.Path) != "sh" || policyErr.User == "" {
+		t.Fatalf("unexpected denial %+v\n", policyErr)
+	}
+
+	l, err := NewWithOptions(context.Background(), "echo", nil, nil, WithPolicy(DenyInterpreters()))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer l.Close()
+	if err := l.Run(); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestPolicyAllowPathPrefixes(t *testing.T) {
+
+	l, err := New(context.Background(), "echo", nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	dir := filepath.Dir(l.GetPath())
+	l.Close()
+
+	if _, err := NewWithOptions(context.Background(), "echo", nil, nil, WithPolicy(AllowPathPrefixes(dir))); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := NewWithOptions(context.Background(), "echo", nil, nil, WithPolicy(AllowPathPrefixes("/opt/tools"))); !errors.Is(err, ErrPolicyDenied) {
+		t.Fatalf("expected ErrPolicyDenied, got %v\n", err)
+	}
+}
+
+func TestPolicyAtStart(t *testing.T) {
+
+	errDenied := errors.New("too late in the day")
+	allow := true
+
+	policy := PolicyFunc(func(req PolicyRequest) error {
+		if req.Stage == PolicyAtStart && !allow {
+			return errDenied
+		}
+		return nil
+	})
+
+	l, err := NewWithOptions(context.Background(), "echo", nil, []string{"secret"}, WithPolicy(AllOf(DenyInterpreters(), policy)), WithSecretArgIndexes(0))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer l.Close()
+
+	allow = false
+	err = l.Start()
+
+	var policyErr *PolicyError
+	if !errors.As(err, &policyErr) || !errors.Is(err, errDenied) {
+		t.Fatalf("expected denial at start, got %v\n", err)
+	}
+	if policyErr.Stage != PolicyAtStart || policyErr.Args[0] != "***" {
+		t.Fatalf("unexpected denial %+v\n", policyErr)
+	}
+	if l.State() != StateFailed {
+		t.Fatalf("expected failed, got %v\n", l.State())
+	}
+}
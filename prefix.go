@@ -0,0 +1,66 @@
+package launcher
+
+import (
+	"errors"
+	"fmt"
+	"hash/fnv"
+	"io"
+)
+
+var errInvalidColour = errors.New("colour is not supported")
+
+// Colour is the ANSI colour of an output prefix
+type Colour int
+
+const (
+	// ColourNone leaves the prefix uncoloured
+	ColourNone Colour = iota
+	ColourRed
+	ColourGreen
+	ColourYellow
+	ColourBlue
+	ColourMagenta
+	ColourCyan
+)
+
+// prefixColours are the colours chosen from by AutoColour
+var prefixColours = []Colour{ColourCyan, ColourYellow, ColourGreen, ColourMagenta, ColourBlue, ColourRed}
+
+// AutoColour returns a Colour chosen from the name, so that
+// the processes of a runner are consistently distinguished
+func AutoColour(name string) Colour {
+	h := fnv.New32a()
+	h.Write([]byte(name))
+	return prefixColours[h.Sum32()%uint32(len(prefixColours))]
+}
+
+// WithOutputPrefix writes s at the start of each line of the stdout and
+// stderr of the process, as seen by all readers of the output, so that
+// the interleaved output of several processes may be attributed.  Any
+// separator, such as in "web | ", must be included in s
+func WithOutputPrefix(s string) Option {
+	return func(o *options) error {
+		o.prefix = s
+		return nil
+	}
+}
+
+// WithPrefixColour writes the prefix set by WithOutputPrefix in the colour
+func WithPrefixColour(c Colour) Option {
+	return func(o *options) error {
+		if c < ColourNone || c > ColourCyan {
+			return errInvalidColour
+		}
+		o.prefixColour = c
+		return nil
+	}
+}
+
+// prefixLines is an outputFilter writing the prefix at the start of each line
+func (l *Launcher) prefixLines(w io.Writer) io.Writer {
+	prefix := []byte(l.opts.prefix)
+	if c := l.opts.prefixColour; c != ColourNone {
+		prefix = fmt.Appendf(nil, "\x1b[%dm%s\x1b[0m", 30+int(c), l.opts.prefix)
+	}
+	return &prefixWriter{w: w, prefix: func() []byte { return prefix }}
+}
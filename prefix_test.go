@@ -0,0 +1,69 @@
+package launcher
+
+import (
+	"bytes"
+	"context"
+	"testing"
+)
+
+func TestOutputPrefix(t *testing.T) {
+
+	script := "printf 'one\\ntw'; sleep 0.1; echo o; echo err >&2"
+
+	l, err := NewWithOptions(context.Background(), "sh", nil, []string{"-c", script}, WithOutputPrefix("web | "))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer l.Close()
+
+	var stdout, stderr bytes.Buffer
+	if err := l.Start(); err != nil {
+		t.Fatal(err)
+	}
+	l.copyOutput(&stdout, &stderr)
+
+	if stdout.String() != "web | one\nweb | two\n" {
+		t.Fatalf("unexpected stdout %q\n", stdout.String())
+	}
+	if stderr.String() != "web | err\n" {
+		t.Fatalf("unexpected stderr %q\n", stderr.String())
+	}
+}
+
+func TestOutputPrefixColour(t *testing.T) {
+
+	opts := []Option{WithOutputPrefix("db "), WithPrefixColour(ColourCyan), WithLineTimestamps()}
+	l, err := NewWithOptions(context.Background(), "echo", nil, []string{"foo"}, opts...)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer l.Close()
+
+	var stdout bytes.Buffer
+	if err := l.Start(); err != nil {
+		t.Fatal(err)
+	}
+	l.copyOutput(&stdout, nil)
+
+	// The prefix precedes the timestamp
+	if b := stdout.Bytes(); !bytes.HasPrefix(b, []byte("\x1b[36mdb \x1b[0m[")) || !bytes.HasSuffix(b, []byte("] foo\n")) {
+		t.Fatalf("unexpected stdout %q\n", b)
+	}
+}
+
+func TestPrefixColourInvalid(t *testing.T) {
+
+	if _, err := NewWithOptions(context.Background(), "echo", nil, nil, WithPrefixColour(Colour(99))); err != errInvalidColour {
+		t.Fatalf("expected errInvalidColour, got %v\n", err)
+	}
+}
+
+func TestAutoColour(t *testing.T) {
+
+	if AutoColour("web") != AutoColour("web") {
+		t.Fatal("expected the same colour for the same name\n")
+	}
+	if AutoColour("web") == ColourNone {
+		t.Fatal("expected a colour\n")
+	}
+}
@@ -0,0 +1,126 @@
+package launcher
+
+// CommandPreview describes the command a Launcher would run, with any
+// secret arguments and environment values masked as for GetArgs and
+// GetEnv, and arguments redacted as for String
+type CommandPreview struct {
+	// Path is the resolved path of the command, or the file if it
+	// cannot yet be resolved, as reported by Resolved
+	Path     string   `json:"path"`
+	Resolved bool     `json:"resolved"`
+	Argv     []string `json:"argv"`
+	Env      []string `json:"env"`
+	// Dir is the working directory, or empty for that of the current process
+	Dir string `json:"dir,omitempty"`
+	// Options names the options configured for the Launcher
+	Options []string `json:"options,omitempty"`
+}
+
+// String returns the argv quoted for the platform shell
+func (p CommandPreview) String() string {
+	return Quote(p.Argv...)
+}
+
+// Preview describes the command without starting it, as the basis of
+// a dry run.  References are expanded as they would be by Start
+func (l *Launcher) Preview() CommandPreview {
+	args, env := l.copyStringArray(l.cmd.Args), l.copyStringArray(l.cmd.Env)
+	if l.opts.expand {
+		args, env = l.expanded()
+	}
+
+	path, resolved := l.path, l.path != ""
+	if !resolved {
+		if p, err := l.opts.resolve(l.file); err == nil {
+			path, resolved = p, true
+		} else {
+			path = l.file
+		}
+	}
+
+	argv := l.opts.maskArgs(args[1:])
+	if l.opts.redactor != nil {
+		argv = l.opts.redactor(argv)
+	}
+
+	return CommandPreview{
+		Path:     path,
+		Resolved: resolved,
+		Argv:     append([]string{path}, argv...),
+		Env:      l.opts.maskEnv(withoutShimEnv(env)),
+		Dir:      l.cmd.Dir,
+		Options:  l.opts.names(),
+	}
+}
+
+// names returns the names of the configured options
+func (o *options) names() []string {
+	set := []struct {
+		name string
+		on   bool
+	}{
+		{"readiness", o.readiness != nil},
+		{"reload_signal", o.reloadSignal != nil},
+		{"restart_on_binary_change", o.binaryRestart},
+		{"heartbeat", o.heartbeat != nil},
+		{"idle_timeout", o.idleTimeout > 0},
+		{"startup_timeout", o.startupTimeout > 0},
+		{"limiter", o.limiter != nil},
+		{"breaker", o.breaker != nil},
+		{"retry_classifier", o.retryable != nil},
+		{"exclusive_lock", o.lockPath != ""},
+		{"pid_file", o.pidFile != ""},
+		{"detached_output", o.detachedStdout != "" || o.detachedStderr != ""},
+		{"kill_tree", o.killTree},
+		{"close_grace", o.closeGrace > 0},
+		{"redactor", o.redactor != nil},
+		{"secret_env", len(o.secretEnv) > 0},
+		{"secret_args", len(o.secretArgs) > 0},
+		{"secret_fd", len(o.secretFDs) > 0},
+		{"extra_files", len(o.extraFiles) > 0},
+		{"listeners", len(o.listeners) > 0},
+		{"inherited_env", o.inheritEnv},
+		{"env_allowlist", len(o.envAllow) > 0},
+		{"env_denylist", len(o.envDeny) > 0},
+		{"policy", len(o.policies) > 0},
+		{"seccomp", o.seccomp != nil},
+		{"landlock", o.landlock != nil},
+		{"security_label", o.label != ""},
+		{"no_new_privs", o.noNewPrivs},
+		{"dir", o.dir != ""},
+		{"temp_workdir", o.tempWorkdir != nil},
+		{"stdin_bytes", o.stdinBytes != nil},
+		{"stdin_from_fs", o.stdinFS != nil},
+		{"resolver", o.resolver != nil},
+		{"lookup_path", o.lookupDirs != nil},
+		{"resolver_cache", o.cache != nil},
+		{"lazy_lookup", o.lazyLookup},
+		{"expansion", o.expand},
+		{"max_captured_output", o.maxCaptured > 0},
+		{"compressed_capture", o.compressCapture},
+		{"pipe_buffer_size", o.pipeBufferSize > 0},
+		{"correlation_id", o.correlationID != ""},
+		{"metrics", o.metrics != nil},
+		{"output_ring_buffer", o.ringLines > 0},
+		{"lossy_output", o.lossyOutput},
+		{"combined_stream", o.combined},
+		{"output_sinks", len(o.sinks) > 0},
+		{"line_timestamps", o.lineTimestamps},
+		{"output_prefix", o.prefix != ""},
+		{"strip_ansi", o.stripANSI},
+		{"output_encoding", o.encoding != nil},
+		{"discard_output", o.discardOutput},
+		{"inherit_stdio", o.inheritStdio},
+		{"output_file", o.outputFile != ""},
+		{"transcript", o.transcript != nil},
+		{"stderr_tail", o.stderrTail > 0},
+	}
+
+	var names []string
+	for _, s := range set {
+		if s.on {
+			names = append(names, s.name)
+		}
+	}
+	return names
+}
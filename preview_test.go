@@ -0,0 +1,58 @@
+package launcher
+
+import (
+	"context"
+	"os/exec"
+	"reflect"
+	"testing"
+)
+
+func TestPreview(t *testing.T) {
+
+	echo, err := exec.LookPath("echo")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	l, err := NewWithOptions(context.Background(), "echo", []string{"NAME=world", "TOKEN=abc"}, []string{"hello ${NAME}", "--password", "hunter2"},
+		WithExpansion(nil), WithSecretEnv("TOKEN"), WithRedactor(RedactFlags("password")), WithTempWorkdir("", false))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer l.Close()
+
+	p := l.Preview()
+
+	expected := CommandPreview{
+		Path:     echo,
+		Resolved: true,
+		Argv:     []string{echo, "hello world", "--password", redactedValue},
+		Env:      []string{"NAME=world", "TOKEN=" + redactedValue},
+		Dir:      l.Workdir(),
+		Options:  []string{"redactor", "secret_env", "temp_workdir", "expansion"},
+	}
+	if !reflect.DeepEqual(p, expected) {
+		t.Fatalf("expected %+v, got %+v\n", expected, p)
+	}
+	if p.String() != echo+" 'hello world' --password '***'" {
+		t.Fatalf("unexpected command line %q\n", p.String())
+	}
+
+	if l.IsStarted() || l.GetArgs()[0] != "hello ${NAME}" {
+		t.Fatal("expected preview not to alter the Launcher")
+	}
+}
+
+func TestPreviewUnresolved(t *testing.T) {
+
+	l, err := NewWithOptions(context.Background(), "zzzUnknownzzz", nil, nil, WithLazyLookup())
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer l.Close()
+
+	p := l.Preview()
+	if p.Resolved || p.Path != "zzzUnknownzzz" || p.Dir != "" {
+		t.Fatalf("unexpected preview %+v\n", p)
+	}
+}
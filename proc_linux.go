@@ -0,0 +1,74 @@
+//go:build linux
+
+package launcher
+
+import (
+	"bytes"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strconv"
+)
+
+// procStat holds the fields of /proc/<pid>/stat used by this package
+type procStat struct {
+	pid   int
+	ppid  int
+	state byte
+	comm  string
+	// rss is the resident set size in pages
+	rss int64
+}
+
+// readProcStat parses /proc/<pid>/stat
+func readProcStat(pid int) (procStat, error) {
+	b, err := os.ReadFile(filepath.Join("/proc", strconv.Itoa(pid), "stat"))
+	if err != nil {
+		return procStat{}, err
+	}
+
+	// The command may itself contain spaces and parentheses,
+	// so is delimited by the first ( and the last )
+	open, end := bytes.IndexByte(b, '('), bytes.LastIndexByte(b, ')')
+	if open < 0 || end < open {
+		return procStat{}, fmt.Errorf("malformed stat for pid %d", pid)
+	}
+	fields := bytes.Fields(b[end+1:])
+	if len(fields) < 22 || len(fields[0]) != 1 {
+		return procStat{}, fmt.Errorf("malformed stat for pid %d", pid)
+	}
+
+	s := procStat{
+		pid:   pid,
+		state: fields[0][0],
+		comm:  string(b[open+1 : end]),
+	}
+	if s.ppid, err = strconv.Atoi(string(fields[1])); err != nil {
+		return procStat{}, err
+	}
+	if s.rss, err = strconv.ParseInt(string(fields[21]), 10, 64); err != nil {
+		return procStat{}, err
+	}
+	return s, nil
+}
+
+// listProcs returns the stat of every process, ignoring
+// those which exit while being listed
+func listProcs() ([]procStat, error) {
+	entries, err := os.ReadDir("/proc")
+	if err != nil {
+		return nil, err
+	}
+
+	var procs []procStat
+	for _, e := range entries {
+		pid, err := strconv.Atoi(e.Name())
+		if err != nil {
+			continue
+		}
+		if s, err := readProcStat(pid); err == nil {
+			procs = append(procs, s)
+		}
+	}
+	return procs, nil
+}
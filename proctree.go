@@ -0,0 +1,53 @@
+package launcher
+
+// ProcInfo describes a process in the tree of a Launcher
+type ProcInfo struct {
+	Pid     int
+	PPid    int
+	Command string
+	// RSS is the resident set size in bytes, or 0 if it is unknown
+	RSS int64
+}
+
+// ProcessTree returns the descendants of the launched process, parents
+// before their children.  Descendants which have been re-parented, for
+// example after their parent exited, are not included
+func (l *Launcher) ProcessTree() ([]ProcInfo, error) {
+	if !l.IsStarted() {
+		return nil, l.launchError(StageRuntime, ErrNotStarted)
+	}
+
+	procs, err := listProcesses()
+	if err != nil {
+		return nil, err
+	}
+	return descendants(procs, l.Pid()), nil
+}
+
+// descendants returns the processes descended from root,
+// in breadth first order
+func descendants(procs []ProcInfo, root int) []ProcInfo {
+	children := map[int][]ProcInfo{}
+	for _, p := range procs {
+		if p.Pid != p.PPid {
+			children[p.PPid] = append(children[p.PPid], p)
+		}
+	}
+
+	var tree []ProcInfo
+	queue := []int{root}
+	seen := map[int]bool{root: true}
+	for len(queue) > 0 {
+		pid := queue[0]
+		queue = queue[1:]
+		for _, c := range children[pid] {
+			if seen[c.Pid] {
+				continue
+			}
+			seen[c.Pid] = true
+			tree = append(tree, c)
+			queue = append(queue, c.Pid)
+		}
+	}
+	return tree
+}
@@ -0,0 +1,40 @@
+//go:build linux
+
+package launcher
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"strconv"
+)
+
+// listProcesses returns all processes, using /proc
+func listProcesses() ([]ProcInfo, error) {
+	stats, err := listProcs()
+	if err != nil {
+		return nil, err
+	}
+
+	pageSize := int64(os.Getpagesize())
+	procs := make([]ProcInfo, 0, len(stats))
+	for _, s := range stats {
+		procs = append(procs, ProcInfo{
+			Pid:     s.pid,
+			PPid:    s.ppid,
+			Command: procCommand(s),
+			RSS:     s.rss * pageSize,
+		})
+	}
+	return procs, nil
+}
+
+// procCommand returns the command line of the process, or its
+// name if the command line is unavailable, as for zombies
+func procCommand(s procStat) string {
+	b, err := os.ReadFile(filepath.Join("/proc", strconv.Itoa(s.pid), "cmdline"))
+	if err != nil || len(b) == 0 {
+		return s.comm
+	}
+	return string(bytes.ReplaceAll(bytes.TrimRight(b, "\x00"), []byte{0}, []byte{' '}))
+}
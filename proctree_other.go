@@ -0,0 +1,12 @@
+//go:build !linux && !windows
+
+package launcher
+
+import "errors"
+
+var errProcessTreeUnsupported = errors.New("process tree is not supported on this platform")
+
+// listProcesses is not supported on this platform
+func listProcesses() ([]ProcInfo, error) {
+	return nil, errProcessTreeUnsupported
+}
@@ -0,0 +1,74 @@
+package launcher
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestProcessTree(t *testing.T) {
+
+	l, err := New(context.Background(), "sh", nil, "-c", "sh -c 'sleep 10' & sleep 10")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer l.Close()
+
+	if _, err := l.ProcessTree(); !errors.Is(err, ErrNotStarted) {
+		t.Fatalf("expected ErrNotStarted, got %v\n", err)
+	}
+
+	if err := l.Start(); err != nil {
+		t.Fatal(err)
+	}
+
+	// Allow the shells to start their children
+	var tree []ProcInfo
+	deadline := time.Now().Add(5 * time.Second)
+	for len(tree) < 3 && time.Now().Before(deadline) {
+		time.Sleep(20 * time.Millisecond)
+		if tree, err = l.ProcessTree(); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	sleeps := 0
+	for _, p := range tree {
+		if strings.HasPrefix(p.Command, "sleep") {
+			sleeps++
+			if p.RSS <= 0 {
+				t.Fatalf("expected RSS for %+v\n", p)
+			}
+		}
+	}
+	if len(tree) != 3 || sleeps != 2 {
+		t.Fatalf("expected inner shell and two sleeps, got %+v\n", tree)
+	}
+	if tree[0].PPid != l.Pid() {
+		t.Fatalf("expected children before grandchildren, got %+v\n", tree)
+	}
+}
+
+func TestDescendants(t *testing.T) {
+
+	procs := []ProcInfo{
+		{Pid: 1, PPid: 0},
+		{Pid: 10, PPid: 1},
+		{Pid: 11, PPid: 10},
+		{Pid: 12, PPid: 11},
+		{Pid: 13, PPid: 10},
+		{Pid: 20, PPid: 1},
+	}
+
+	tree := descendants(procs, 10)
+
+	var pids []int
+	for _, p := range tree {
+		pids = append(pids, p.Pid)
+	}
+	if len(pids) != 3 || pids[0] != 11 || pids[1] != 13 || pids[2] != 12 {
+		t.Fatalf("unexpected descendants %v\n", pids)
+	}
+}
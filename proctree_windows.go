@@ -0,0 +1,34 @@
+//go:build windows
+
+package launcher
+
+import (
+	"os"
+	"unsafe"
+
+	"golang.org/x/sys/windows"
+)
+
+// listProcesses returns all processes, using a toolhelp snapshot.
+// The RSS of the processes is not reported
+func listProcesses() ([]ProcInfo, error) {
+	snap, err := windows.CreateToolhelp32Snapshot(windows.TH32CS_SNAPPROCESS, 0)
+	if err != nil {
+		return nil, os.NewSyscallError("CreateToolhelp32Snapshot", err)
+	}
+	defer windows.CloseHandle(snap)
+
+	var procs []ProcInfo
+	entry := windows.ProcessEntry32{Size: uint32(unsafe.Sizeof(windows.ProcessEntry32{}))}
+	for err = windows.Process32First(snap, &entry); err == nil; err = windows.Process32Next(snap, &entry) {
+		procs = append(procs, ProcInfo{
+			Pid:     int(entry.ProcessID),
+			PPid:    int(entry.ParentProcessID),
+			Command: windows.UTF16ToString(entry.ExeFile[:]),
+		})
+	}
+	if err != windows.ERROR_NO_MORE_FILES {
+		return nil, os.NewSyscallError("Process32Next", err)
+	}
+	return procs, nil
+}
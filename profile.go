@@ -0,0 +1,39 @@
+package launcher
+
+import (
+	"context"
+	"runtime/pprof"
+)
+
+// The pprof labels of the goroutines serving a process, so that CPU and
+// goroutine profiles of applications with many processes can attribute
+// them to the command, the launch and the work they are doing
+const (
+	LabelCommand = "launcher.command"
+	LabelRunID   = "launcher.run_id"
+	LabelRole    = "launcher.role"
+)
+
+// goLabelled runs f in a new goroutine, labelled with the command,
+// correlation ID and role for profiling
+func goLabelled(command, runID, role string, f func()) {
+	labels := []string{LabelCommand, command, LabelRole, role}
+	if runID != "" {
+		labels = append(labels, LabelRunID, runID)
+	}
+	go pprof.Do(context.Background(), pprof.Labels(labels...), func(context.Context) {
+		f()
+	})
+}
+
+// goLabelled runs f in a new goroutine, labelled
+// as serving the process in the role
+func (l *Launcher) goLabelled(role string, f func()) {
+	goLabelled(l.file, l.CorrelationID(), role, f)
+}
+
+// goLabelled runs f in a new goroutine, labelled
+// as supervising the process in the role
+func (s *Supervisor) goLabelled(role string, f func()) {
+	goLabelled(s.spec.File, s.correlationID, role, f)
+}
@@ -0,0 +1,45 @@
+package launcher
+
+import (
+	"bytes"
+	"context"
+	"runtime/pprof"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestGoroutineProfileLabels(t *testing.T) {
+
+	ctx := ContextWithCorrelationID(context.Background(), "run-7")
+	l, err := NewWithOptions(ctx, "sleep", nil, []string{"10"}, WithLineTimestamps())
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer l.Close()
+
+	if err := l.Start(); err != nil {
+		t.Fatal(err)
+	}
+
+	// The goroutines may not yet have run, and so be unlabelled
+	var profile string
+	for i := 0; i < 100; i++ {
+		var buf bytes.Buffer
+		if err := pprof.Lookup("goroutine").WriteTo(&buf, 1); err != nil {
+			t.Fatal(err)
+		}
+		profile = buf.String()
+		if strings.Count(profile, `"launcher.run_id":"run-7"`) >= 3 {
+			break
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+
+	for _, role := range []string{"wait", "stdout-pump", "stderr-pump"} {
+		labels := `"launcher.command":"sleep", "launcher.role":"` + role + `", "launcher.run_id":"run-7"`
+		if !strings.Contains(profile, labels) {
+			t.Fatalf("expected goroutine labelled %s in profile\n", labels)
+		}
+	}
+}
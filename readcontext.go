@@ -0,0 +1,58 @@
+package launcher
+
+import (
+	"context"
+	"io"
+)
+
+// ReadStdOutContext reads from the stdout of the process into p in the
+// same way as StdOutReader, returning the error of the context if it
+// ends before any output is available.  As with ReadAvailableStdOut,
+// stdout is then pumped into a buffer
+func (l *Launcher) ReadStdOutContext(ctx context.Context, p []byte) (int, error) {
+	if ctx == nil {
+		return 0, ErrMissingContext
+	}
+	buf := l.bufferOutput(&l.cmdStdOut, &l.stdOutSource)
+	if buf == nil {
+		return 0, io.EOF
+	}
+	return buf.readContext(ctx, p)
+}
+
+// ReadStdErrContext reads from the stderr of the process into p,
+// in the same way as ReadStdOutContext
+func (l *Launcher) ReadStdErrContext(ctx context.Context, p []byte) (int, error) {
+	if ctx == nil {
+		return 0, ErrMissingContext
+	}
+	buf := l.bufferOutput(&l.cmdStdErr, &l.stdErrSource)
+	if buf == nil {
+		return 0, io.EOF
+	}
+	return buf.readContext(ctx, p)
+}
+
+// StdOutReaderContext returns a reader of the stdout of the process
+// whose reads fail once the context ends, such as when its deadline
+// passes, so that it may be given to a bufio.Scanner or json.Decoder
+func (l *Launcher) StdOutReaderContext(ctx context.Context) io.Reader {
+	return readerFunc(func(p []byte) (int, error) {
+		return l.ReadStdOutContext(ctx, p)
+	})
+}
+
+// StdErrReaderContext returns a reader of the stderr of the process
+// whose reads fail once the context ends
+func (l *Launcher) StdErrReaderContext(ctx context.Context) io.Reader {
+	return readerFunc(func(p []byte) (int, error) {
+		return l.ReadStdErrContext(ctx, p)
+	})
+}
+
+// readerFunc is an io.Reader implemented by a function
+type readerFunc func(p []byte) (int, error)
+
+func (f readerFunc) Read(p []byte) (int, error) {
+	return f(p)
+}
@@ -0,0 +1,64 @@
+package launcher
+
+import (
+	"bufio"
+	"context"
+	"errors"
+	"testing"
+	"time"
+)
+
+func TestReadStdOutContext(t *testing.T) {
+
+	l, err := New(context.Background(), "sh", nil, "-c", "echo first; sleep 10")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer l.Close()
+
+	if err := l.Start(); err != nil {
+		t.Fatal(err)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 200*time.Millisecond)
+	defer cancel()
+
+	scanner := bufio.NewScanner(l.StdOutReaderContext(ctx))
+	if !scanner.Scan() || scanner.Text() != "first" {
+		t.Fatalf("expected the first line, got %q\n", scanner.Text())
+	}
+
+	// No further output arrives before the deadline
+	start := time.Now()
+	if scanner.Scan() {
+		t.Fatalf("unexpected line %q\n", scanner.Text())
+	}
+	if !errors.Is(scanner.Err(), context.DeadlineExceeded) {
+		t.Fatalf("expected the deadline to pass, got %v\n", scanner.Err())
+	}
+	if time.Since(start) > 2*time.Second {
+		t.Fatal("read was not bounded by the deadline\n")
+	}
+}
+
+func TestReadStdErrContext(t *testing.T) {
+
+	l, err := New(context.Background(), "sh", nil, "-c", "echo err >&2")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer l.Close()
+
+	if _, err := l.ReadStdErrContext(nil, nil); err != ErrMissingContext {
+		t.Fatalf("expected ErrMissingContext, got %v\n", err)
+	}
+	if err := l.Run(); err != nil {
+		t.Fatal(err)
+	}
+
+	b := make([]byte, 10)
+	n, err := l.ReadStdErrContext(context.Background(), b)
+	if err != nil || string(b[:n]) != "err\n" {
+		t.Fatalf("unexpected stderr %q and %v\n", b[:n], err)
+	}
+}
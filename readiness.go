@@ -0,0 +1,216 @@
+package launcher
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net"
+	"net/http"
+	"os"
+	"os/exec"
+	"regexp"
+	"time"
+)
+
+// ErrStartupTimeout is returned when the readiness Probe has
+// not passed within the startup timeout
+var ErrStartupTimeout = errors.New("process did not become ready within the startup timeout")
+
+var errMissingProbe = errors.New("probe must be provided")
+var errInvalidTimeout = errors.New("timeout must be positive")
+var errExitedBeforeReady = errors.New("process exited before becoming ready")
+var errNotReady = errors.New("process is not ready")
+
+const (
+	// probeInterval is the pause between attempts of a Probe
+	probeInterval = 100 * time.Millisecond
+	// probeTimeout limits the duration of a single attempt of a Probe
+	probeTimeout = time.Second
+)
+
+// Probe checks whether a started process is ready to be used
+type Probe interface {
+	// check returns nil if the process is ready
+	check(ctx context.Context, l *Launcher) error
+}
+
+// preparer is implemented by Probes which must observe
+// the Launcher before its process is started
+type preparer interface {
+	prepare(l *Launcher)
+}
+
+// WithReadiness sets the Probe which must pass before the Launcher
+// considers its process to have started
+func WithReadiness(p Probe) Option {
+	return func(o *options) error {
+		if p == nil {
+			return errMissingProbe
+		}
+		o.readiness = p
+		return nil
+	}
+}
+
+// WithStartupTimeout limits the time allowed for the readiness Probe
+// to pass.  If it has not passed within the timeout, the process is
+// killed and ErrStartupTimeout is returned.  The timeout has no
+// effect unless WithReadiness is also used
+func WithStartupTimeout(d time.Duration) Option {
+	return func(o *options) error {
+		if d <= 0 {
+			return errInvalidTimeout
+		}
+		o.startupTimeout = d
+		return nil
+	}
+}
+
+// waitReady repeatedly checks the readiness Probe, if any, until it
+// passes, the process exits or the context ends.  If the startup
+// timeout expires first, the process is killed
+func (l *Launcher) waitReady(ctx context.Context) error {
+	p := l.opts.readiness
+	if p == nil {
+		return nil
+	}
+
+	if l.opts.startupTimeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeoutCause(ctx, l.opts.startupTimeout, ErrStartupTimeout)
+		defer cancel()
+	}
+
+	err := l.pollReady(ctx, p)
+	if err != nil && context.Cause(ctx) == ErrStartupTimeout {
+		l.CancelWithCause(ErrStartupTimeout)
+		<-l.done
+		return ErrStartupTimeout
+	}
+	return err
+}
+
+// pollReady checks the Probe until it passes, the process
+// exits or the context ends
+func (l *Launcher) pollReady(ctx context.Context, p Probe) error {
+	for {
+		attemptCtx, cancel := context.WithTimeout(ctx, probeTimeout)
+		err := p.check(attemptCtx, l)
+		cancel()
+		if err == nil {
+			return nil
+		}
+
+		select {
+		case <-l.done:
+			return errExitedBeforeReady
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-time.After(probeInterval):
+		}
+	}
+}
+
+// TCPProbe passes once a connection can be made to the address
+func TCPProbe(addr string) Probe {
+	return ProbeFunc(func(ctx context.Context) error {
+		var d net.Dialer
+		conn, err := d.DialContext(ctx, "tcp", addr)
+		if err != nil {
+			return err
+		}
+		return conn.Close()
+	})
+}
+
+// HTTPProbe passes once a GET of the url returns 200 OK
+func HTTPProbe(url string) Probe {
+	return ProbeFunc(func(ctx context.Context) error {
+		req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+		if err != nil {
+			return err
+		}
+		resp, err := http.DefaultClient.Do(req)
+		if err != nil {
+			return err
+		}
+		resp.Body.Close()
+		if resp.StatusCode != http.StatusOK {
+			return fmt.Errorf("%w: %s returned %s", errNotReady, url, resp.Status)
+		}
+		return nil
+	})
+}
+
+// FileProbe passes once the file at path exists
+func FileProbe(path string) Probe {
+	return ProbeFunc(func(ctx context.Context) error {
+		_, err := os.Stat(path)
+		return err
+	})
+}
+
+// ExecProbe passes once running the command exits successfully
+func ExecProbe(file string, args ...string) Probe {
+	return ProbeFunc(func(ctx context.Context) error {
+		cmd := exec.CommandContext(ctx, file, args...)
+		if err := startChild(cmd); err != nil {
+			return err
+		}
+		return waitChild(cmd)
+	})
+}
+
+// ProbeFunc is a Probe which passes once the function returns nil
+type ProbeFunc func(ctx context.Context) error
+
+func (f ProbeFunc) check(ctx context.Context, l *Launcher) error {
+	return f(ctx)
+}
+
+// StdOutProbe passes once a line written to stdout by the
+// process matches the regular expression
+func StdOutProbe(re *regexp.Regexp) Probe {
+	return &stdOutProbe{re: re}
+}
+
+type stdOutProbe struct {
+	re *regexp.Regexp
+}
+
+func (p *stdOutProbe) prepare(l *Launcher) {
+	l.probeMatched = make(chan struct{})
+	l.tapStdOut(newLineWriter(func(line string) {
+		if p.re.MatchString(line) {
+			l.mu.Lock()
+			defer l.mu.Unlock()
+
+			select {
+			case <-l.probeMatched:
+			default:
+				close(l.probeMatched)
+			}
+		}
+	}))
+}
+
+func (p *stdOutProbe) check(ctx context.Context, l *Launcher) error {
+	l.mu.Lock()
+	matched := l.probeMatched
+	l.mu.Unlock()
+
+	select {
+	case <-matched:
+		return nil
+	default:
+		return errNotReady
+	}
+}
+
+// rearm requires a further line to match before the probe passes again
+func (p *stdOutProbe) rearm(l *Launcher) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	l.probeMatched = make(chan struct{})
+}
@@ -0,0 +1,151 @@
+package launcher
+
+import (
+	"context"
+	"errors"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"regexp"
+	"testing"
+	"time"
+)
+
+func TestReadinessStdOutProbe(t *testing.T) {
+
+	l, err := NewWithOptions(context.Background(), "sh", []string{}, []string{"-c", "sleep 0.2; echo listening; exec sleep 10"},
+		WithReadiness(StdOutProbe(regexp.MustCompile("^listening$"))))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer l.Close()
+
+	if err := l.Start(); err != nil {
+		t.Fatal(err)
+	}
+
+	if !l.IsRunning() {
+		t.Fatal("not running")
+	}
+
+	// Output seen by the probe remains available to the caller
+	var b = make([]byte, len("listening"))
+	if _, err := l.StdOutReader().Read(b); err != nil {
+		t.Fatal(err)
+	}
+	if string(b) != "listening" {
+		t.Fatalf("unexpected output %q\n", b)
+	}
+}
+
+func TestReadinessFileProbe(t *testing.T) {
+
+	path := filepath.Join(t.TempDir(), "ready")
+
+	l, err := NewWithOptions(context.Background(), "sh", []string{}, []string{"-c", "sleep 0.2; touch " + path + "; exec sleep 10"},
+		WithReadiness(FileProbe(path)))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer l.Close()
+
+	if err := l.Start(); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestReadinessNetworkProbes(t *testing.T) {
+
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {}))
+	defer srv.Close()
+
+	for _, p := range []Probe{TCPProbe(srv.Listener.Addr().String()), HTTPProbe(srv.URL)} {
+		l, err := NewWithOptions(context.Background(), "sleep", []string{}, []string{"10"}, WithReadiness(p))
+		if err != nil {
+			t.Fatal(err)
+		}
+		defer l.Close()
+
+		if err := l.Start(); err != nil {
+			t.Fatal(err)
+		}
+	}
+}
+
+func TestReadinessExitBeforeReady(t *testing.T) {
+
+	l, err := NewWithOptions(context.Background(), "sh", []string{}, []string{"-c", "exit 1"},
+		WithReadiness(TCPProbe(unusedAddr(t))))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer l.Close()
+
+	if err := l.Start(); !errors.Is(err, errExitedBeforeReady) {
+		t.Fatal(err)
+	}
+}
+
+func TestReadinessStartAndWaitReadyTimeout(t *testing.T) {
+
+	l, err := NewWithOptions(context.Background(), "sleep", []string{}, []string{"10"},
+		WithReadiness(TCPProbe(unusedAddr(t))))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer l.Close()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 300*time.Millisecond)
+	defer cancel()
+
+	if err := l.StartAndWaitReady(ctx); !errors.Is(err, context.DeadlineExceeded) {
+		t.Fatal(err)
+	}
+}
+
+func TestReadinessWithNilProbe(t *testing.T) {
+
+	_, err := NewWithOptions(context.Background(), "sleep", []string{}, []string{"10"}, WithReadiness(nil))
+	if err != errMissingProbe {
+		t.Fatal(err)
+	}
+}
+
+// unusedAddr returns an address on which nothing is listening
+func unusedAddr(t *testing.T) string {
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	addr := ln.Addr().String()
+	ln.Close()
+	return addr
+}
+
+func TestReadinessStartupTimeout(t *testing.T) {
+
+	l, err := NewWithOptions(context.Background(), "sleep", []string{}, []string{"10"},
+		WithReadiness(TCPProbe(unusedAddr(t))),
+		WithStartupTimeout(300*time.Millisecond))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer l.Close()
+
+	if err := l.Start(); !errors.Is(err, ErrStartupTimeout) {
+		t.Fatal(err)
+	}
+
+	if l.IsRunning() {
+		t.Fatal("still running")
+	}
+}
+
+func TestReadinessStartupTimeoutInvalid(t *testing.T) {
+
+	_, err := NewWithOptions(context.Background(), "sleep", []string{}, []string{"10"}, WithStartupTimeout(0))
+	if err != errInvalidTimeout {
+		t.Fatal(err)
+	}
+}
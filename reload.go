@@ -0,0 +1,58 @@
+package launcher
+
+import (
+	"context"
+	"errors"
+	"os"
+	"syscall"
+)
+
+var errMissingSignal = errors.New("signal must be provided")
+
+// rearmer is implemented by Probes which must be reset
+// before they can pass again after a reload
+type rearmer interface {
+	rearm(l *Launcher)
+}
+
+// WithReloadSignal sets the signal sent by Reload,
+// which is SIGHUP by default
+func WithReloadSignal(sig os.Signal) Option {
+	return func(o *options) error {
+		if sig == nil {
+			return errMissingSignal
+		}
+		o.reloadSignal = sig
+		return nil
+	}
+}
+
+// Reload asks the process to reload its configuration,
+// in the manner of many daemons, by sending it the reload signal
+func (l *Launcher) Reload() error {
+	sig := l.opts.reloadSignal
+	if sig == nil {
+		sig = syscall.SIGHUP
+	}
+	return l.Signal(sig)
+}
+
+// ReloadAndWaitReady sends the reload signal, and then waits for any
+// configured readiness Probe to pass again, giving up when the context
+// ends or the process exits.  A StdOutProbe must match a line written
+// after the signal was sent
+func (l *Launcher) ReloadAndWaitReady(ctx context.Context) error {
+	if ctx == nil {
+		return l.launchError(StageRuntime, ErrMissingContext)
+	}
+	if r, ok := l.opts.readiness.(rearmer); ok {
+		r.rearm(l)
+	}
+	if err := l.Reload(); err != nil {
+		return err
+	}
+	if l.opts.readiness == nil {
+		return nil
+	}
+	return l.launchError(StageRuntime, l.pollReady(ctx, l.opts.readiness))
+}
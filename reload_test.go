@@ -0,0 +1,68 @@
+package launcher
+
+import (
+	"context"
+	"errors"
+	"regexp"
+	"strings"
+	"syscall"
+	"testing"
+	"time"
+)
+
+func TestReloadAndWaitReady(t *testing.T) {
+
+	script := "n=0; trap 'n=$((n+1)); echo reloaded $n' INT; echo ready; while true; do sleep 0.05; done"
+	l, err := NewWithOptions(context.Background(), "sh", nil, []string{"-c", script},
+		WithReadiness(StdOutProbe(regexp.MustCompile(`^(ready|reloaded \d+)$`))), WithReloadSignal(syscall.SIGINT), WithOutputRingBuffer(10))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer l.Close()
+
+	if err := l.Start(); err != nil {
+		t.Fatal(err)
+	}
+
+	for i := 0; i < 2; i++ {
+		ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+		err := l.ReloadAndWaitReady(ctx)
+		cancel()
+		if err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	// Close waits for the output to be pumped into the ring buffer
+	l.Close()
+	out := l.LastOutput()
+	if !strings.Contains(strings.Join(out, "\n"), "reloaded 2") {
+		t.Fatalf("expected two reloads, got %q\n", out)
+	}
+}
+
+func TestReloadNotReady(t *testing.T) {
+
+	// The process ignores the reload, so the probe does not pass again
+	script := "trap '' HUP; echo ready; sleep 10"
+	l, err := NewWithOptions(context.Background(), "sh", nil, []string{"-c", script},
+		WithReadiness(StdOutProbe(regexp.MustCompile(`^ready$`))))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer l.Close()
+
+	if err := l.Start(); err != nil {
+		t.Fatal(err)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 300*time.Millisecond)
+	defer cancel()
+	if err := l.ReloadAndWaitReady(ctx); !errors.Is(err, context.DeadlineExceeded) {
+		t.Fatalf("expected deadline exceeded, got %v\n", err)
+	}
+
+	if err := l.ReloadAndWaitReady(nil); !errors.Is(err, ErrMissingContext) {
+		t.Fatalf("expected ErrMissingContext, got %v\n", err)
+	}
+}
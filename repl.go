@@ -0,0 +1,122 @@
+package launcher
+
+import (
+	"context"
+	"errors"
+	"io"
+	"regexp"
+	"strings"
+	"sync"
+)
+
+var errMissingLauncher = errors.New("launcher must be provided")
+var errMissingPrompt = errors.New("prompt must be provided")
+var errExitedBeforePrompt = errors.New("process exited before writing its prompt")
+
+// replReadSize is the size of each read of the output of the process
+const replReadSize = 4096
+
+// REPL drives an interactive interpreter, such as python3 or psql, by
+// sending it input and returning the output written before its next
+// prompt, so that the interpreter may be used as a callable service.
+// A REPL is safe for concurrent use, with each evaluation made in turn
+type REPL struct {
+	l      *Launcher
+	prompt *regexp.Regexp
+	mu     sync.Mutex
+	buf    []byte
+	chunk  []byte
+	synced bool
+}
+
+// NewREPL returns a REPL driving the process of the Launcher, whose
+// prompt is matched by the regular expression.  The process is started
+// if it has not been, and any output before its first prompt, such as
+// a banner, is discarded.  Output and the prompt are read from stdout.
+// Interpreters writing their prompt to stderr, such as python3 -i, must
+// have stderr redirected to stdout, as by sh -c 'exec python3 -i 2>&1',
+// since the order of output across separate pipes is not preserved
+func NewREPL(ctx context.Context, l *Launcher, prompt *regexp.Regexp) (*REPL, error) {
+	if ctx == nil {
+		return nil, ErrMissingContext
+	}
+	if l == nil {
+		return nil, errMissingLauncher
+	}
+	if prompt == nil {
+		return nil, errMissingPrompt
+	}
+
+	if err := l.StartAndWaitReady(ctx); err != nil && !errors.Is(err, ErrAlreadyStarted) {
+		return nil, err
+	}
+
+	r := &REPL{l: l, prompt: prompt, chunk: make([]byte, replReadSize)}
+	if _, err := r.untilPrompt(ctx); err != nil {
+		return nil, err
+	}
+	r.synced = true
+	return r, nil
+}
+
+// Eval sends the input, followed by a newline if it does not end with
+// one, and returns the output written before the next prompt.  If the
+// context ends or the process exits first, the output so far is
+// returned with the error, and the rest of the output of the input is
+// discarded by the next call to Eval
+func (r *REPL) Eval(ctx context.Context, input string) (string, error) {
+	if ctx == nil {
+		return "", ErrMissingContext
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if !r.synced {
+		if _, err := r.untilPrompt(ctx); err != nil {
+			return "", err
+		}
+		r.synced = true
+	}
+
+	if !strings.HasSuffix(input, "\n") {
+		input += "\n"
+	}
+	if _, err := r.l.Write([]byte(input)); err != nil {
+		return "", err
+	}
+
+	r.synced = false
+	out, err := r.untilPrompt(ctx)
+	if err == nil {
+		r.synced = true
+	}
+	return out, err
+}
+
+// untilPrompt reads output until the prompt, returning the output
+// before it, or the output so far if reading fails
+func (r *REPL) untilPrompt(ctx context.Context) (string, error) {
+	for {
+		if loc := r.prompt.FindIndex(r.buf); loc != nil {
+			out := string(r.buf[:loc[0]])
+			r.buf = append(r.buf[:0], r.buf[loc[1]:]...)
+			return out, nil
+		}
+
+		b, err := r.read(ctx)
+		r.buf = append(r.buf, b...)
+		if err != nil {
+			if err == io.EOF {
+				err = errExitedBeforePrompt
+			}
+			return string(r.buf), err
+		}
+	}
+}
+
+// read returns the next output of the process
+func (r *REPL) read(ctx context.Context) ([]byte, error) {
+	n, err := r.l.ReadStdOutContext(ctx, r.chunk)
+	return r.chunk[:n], err
+}
@@ -0,0 +1,93 @@
+package launcher
+
+import (
+	"context"
+	"regexp"
+	"testing"
+	"time"
+)
+
+func TestREPLEval(t *testing.T) {
+
+	l, err := NewWithOptions(context.Background(), "sh", nil, []string{"-c", `echo banner; printf '> '; while read -r line; do eval "$line"; printf '> '; done`})
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer l.Close()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	r, err := NewREPL(ctx, l, regexp.MustCompile(`> $`))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	for _, input := range []string{"echo foo", "echo bar; echo baz"} {
+		out, err := r.Eval(ctx, input)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if want := map[string]string{"echo foo": "foo\n", "echo bar; echo baz": "bar\nbaz\n"}[input]; out != want {
+			t.Fatalf("expected %q, got %q\n", want, out)
+		}
+	}
+
+	if _, err := r.Eval(ctx, "exit"); err != errExitedBeforePrompt {
+		t.Fatalf("expected errExitedBeforePrompt, got %v\n", err)
+	}
+}
+
+func TestREPLPromptOnStderr(t *testing.T) {
+
+	// The prompt is written to stderr, which is redirected to stdout
+	l, err := NewWithOptions(context.Background(), "sh", nil, []string{"-c", `exec 2>&1; printf '> ' >&2; while read -r line; do eval "$line"; printf '> ' >&2; done`})
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer l.Close()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	r, err := NewREPL(ctx, l, regexp.MustCompile(`> $`))
+	if err != nil {
+		t.Fatal(err)
+	}
+	out, err := r.Eval(ctx, "echo foo")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if out != "foo\n" {
+		t.Fatalf("expected %q, got %q\n", "foo\n", out)
+	}
+}
+
+func TestREPLTimeout(t *testing.T) {
+
+	l, err := NewWithOptions(context.Background(), "sh", nil, []string{"-c", `printf '> '; while read -r line; do eval "$line"; printf '> '; done`})
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer l.Close()
+
+	r, err := NewREPL(context.Background(), l, regexp.MustCompile(`> $`))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 200*time.Millisecond)
+	defer cancel()
+	if _, err := r.Eval(ctx, "echo slow; sleep 1; echo done"); err != context.DeadlineExceeded {
+		t.Fatalf("expected context.DeadlineExceeded, got %v\n", err)
+	}
+
+	// The output of the interrupted input is discarded
+	out, err := r.Eval(context.Background(), "echo foo")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if out != "foo\n" {
+		t.Fatalf("expected %q, got %q\n", "foo\n", out)
+	}
+}
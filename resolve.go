@@ -0,0 +1,107 @@
+package launcher
+
+import (
+	"errors"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+)
+
+var errMissingResolver = errors.New("resolver must be provided")
+
+// Resolver returns the path of the executable to be run for the file
+type Resolver func(file string) (string, error)
+
+// WithResolver resolves the file using the Resolver, in place of
+// exec.LookPath, so that versioned tool names, binaries downloaded
+// on demand or the entries of a toolchain manifest may be launched
+func WithResolver(r Resolver) Option {
+	return func(o *options) error {
+		if r == nil {
+			return errMissingResolver
+		}
+		o.resolver = r
+		o.lookupDirs = nil
+		return nil
+	}
+}
+
+// WithLookupPath resolves the file against the directories, in order,
+// in place of the PATH of the current process.  A file containing a
+// path separator is used as given
+func WithLookupPath(dirs ...string) Option {
+	return func(o *options) error {
+		o.resolver = nil
+		o.lookupDirs = append([]string{}, dirs...)
+		return nil
+	}
+}
+
+// lookPathIn returns a Resolver of files against the directories
+func lookPathIn(dirs []string) Resolver {
+	return func(file string) (string, error) {
+		if filepath.Base(file) != file {
+			return exec.LookPath(file)
+		}
+		for _, dir := range dirs {
+			if dir == "" {
+				continue
+			}
+			if path, err := exec.LookPath(filepath.Join(dir, file)); err == nil {
+				return path, nil
+			}
+		}
+		return "", &exec.Error{Name: file, Err: exec.ErrNotFound}
+	}
+}
+
+// resolve returns the path of the executable identified by the file,
+// from the ResolverCache unless the file is resolved by a Resolver
+func (o *options) resolve(file string) (string, error) {
+	if o.resolver != nil {
+		return o.resolver(file)
+	}
+
+	lookup, searchPath := exec.LookPath, os.Getenv("PATH")
+	if o.lookupDirs != nil {
+		lookup = lookPathIn(o.lookupDirs)
+		searchPath = strings.Join(o.lookupDirs, string(os.PathListSeparator))
+	}
+	if o.cache == nil {
+		return lookup(file)
+	}
+	return o.cache.resolve(file, searchPath, lookup)
+}
+
+// WithLazyLookup allows the Launcher to be created when the file cannot
+// yet be resolved, deferring its resolution until the process is started,
+// so that a Launcher may be created before the tool is installed.  Policies
+// are not consulted on creation while the file is unresolved
+func WithLazyLookup() Option {
+	return func(o *options) error {
+		o.lazyLookup = true
+		return nil
+	}
+}
+
+// resolveLazily resolves the file, if it could not be resolved
+// when the Launcher was created
+func (l *Launcher) resolveLazily() error {
+	if l.path != "" {
+		return nil
+	}
+	path, err := l.opts.resolve(l.file)
+	if err != nil {
+		return l.launchError(StageLookup, err)
+	}
+
+	l.path = path
+	l.cmd.Args[0] = path
+	l.cmd.Err = nil
+	// The shim runs the target given by the first argument
+	if l.opts.shimConfig() == nil {
+		l.cmd.Path = path
+	}
+	return nil
+}
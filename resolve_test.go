@@ -0,0 +1,124 @@
+package launcher
+
+import (
+	"context"
+	"errors"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"runtime"
+	"testing"
+)
+
+var errUnknownTool = errors.New("unknown tool")
+
+func TestLookupPath(t *testing.T) {
+
+	empty, tools := t.TempDir(), t.TempDir()
+	tool := filepath.Join(tools, "echo")
+	if err := os.WriteFile(tool, []byte("#!/bin/sh\necho hermetic\n"), 0o755); err != nil {
+		t.Fatal(err)
+	}
+
+	l, err := NewWithOptions(context.Background(), "echo", nil, nil, WithLookupPath(empty, tools))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer l.Close()
+
+	if l.GetPath() != tool {
+		t.Fatalf("expected %v, got %v\n", tool, l.GetPath())
+	}
+
+	_, err = NewWithOptions(context.Background(), "sh", nil, nil, WithLookupPath(empty, tools))
+	if !errors.Is(err, exec.ErrNotFound) {
+		t.Fatalf("expected system binaries not to be found, got %v\n", err)
+	}
+}
+
+func TestResolver(t *testing.T) {
+
+	echo, err := exec.LookPath("echo")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var requested []string
+	resolver := func(file string) (string, error) {
+		requested = append(requested, file)
+		if file == "greet@1.0" {
+			return echo, nil
+		}
+		return "", errUnknownTool
+	}
+
+	l, err := NewWithOptions(context.Background(), "greet@1.0", nil, []string{"hi"}, WithResolver(resolver))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer l.Close()
+
+	if l.GetFile() != "greet@1.0" || l.GetPath() != echo {
+		t.Fatalf("expected greet@1.0 to resolve to %v, got %v\n", echo, l.GetPath())
+	}
+	if err := l.Run(); err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err := NewWithOptions(context.Background(), "other", nil, nil, WithResolver(resolver)); !errors.Is(err, errUnknownTool) {
+		t.Fatal(err)
+	}
+	if len(requested) != 2 {
+		t.Fatalf("expected resolver to be called twice, got %v\n", requested)
+	}
+
+	if _, err := NewWithOptions(context.Background(), "echo", nil, nil, WithResolver(nil)); err != errMissingResolver {
+		t.Fatal(err)
+	}
+}
+
+func TestLazyLookup(t *testing.T) {
+
+	tools := t.TempDir()
+
+	if _, err := NewWithOptions(context.Background(), "provisioned", nil, nil, WithLookupPath(tools)); !errors.Is(err, exec.ErrNotFound) {
+		t.Fatal(err)
+	}
+
+	l, err := NewWithOptions(context.Background(), "provisioned", nil, []string{"foo"}, WithLookupPath(tools), WithLazyLookup())
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer l.Close()
+
+	if l.GetPath() != "" || l.GetArgs()[0] != "foo" {
+		t.Fatalf("expected unresolved path, got %v %v\n", l.GetPath(), l.GetArgs())
+	}
+	if err := l.Start(); !errors.Is(err, exec.ErrNotFound) {
+		t.Fatalf("expected Start to fail before provisioning, got %v\n", err)
+	}
+
+	// Resolution must also apply to processes started by the shim
+	opts := []Option{WithLookupPath(tools), WithLazyLookup()}
+	if runtime.GOOS == "linux" {
+		opts = append(opts, WithNoNewPrivs())
+	}
+
+	l, err = NewWithOptions(context.Background(), "provisioned", nil, []string{"foo"}, opts...)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer l.Close()
+
+	tool := filepath.Join(tools, "provisioned")
+	if err := os.WriteFile(tool, []byte("#!/bin/sh\necho provisioned $1\n"), 0o755); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := l.Run(); err != nil {
+		t.Fatal(err)
+	}
+	if l.GetPath() != tool {
+		t.Fatalf("expected %v, got %v\n", tool, l.GetPath())
+	}
+}
@@ -0,0 +1,84 @@
+package launcher
+
+import (
+	"errors"
+	"hash/fnv"
+	"sync"
+)
+
+var errMissingCache = errors.New("resolver cache must be provided")
+
+// ResolverCache holds the paths to which files have been resolved, keyed
+// by the file and a hash of the directories searched, so that Launchers
+// sharing the cache need not search the directories again.  Failed
+// lookups are not cached.  A ResolverCache is safe for concurrent use
+type ResolverCache struct {
+	mu    sync.RWMutex
+	paths map[resolverKey]string
+}
+
+// resolverKey identifies a file resolved against a search path
+type resolverKey struct {
+	file       string
+	searchPath uint64
+}
+
+// NewResolverCache creates a new, empty ResolverCache
+func NewResolverCache() *ResolverCache {
+	return &ResolverCache{paths: map[resolverKey]string{}}
+}
+
+// WithResolverCache resolves the file from the cache, searching the
+// PATH, or the directories set by WithLookupPath, only if it has not
+// been resolved before.  It has no effect if a Resolver is set
+func WithResolverCache(c *ResolverCache) Option {
+	return func(o *options) error {
+		if c == nil {
+			return errMissingCache
+		}
+		o.cache = c
+		return nil
+	}
+}
+
+// Clear removes all entries, so that files are resolved again,
+// as is necessary if executables are moved or removed
+func (c *ResolverCache) Clear() {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.paths = map[resolverKey]string{}
+}
+
+// Len returns the number of entries in the cache
+func (c *ResolverCache) Len() int {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+
+	return len(c.paths)
+}
+
+// resolve returns the cached path of the file for the search path,
+// calling lookup to resolve it if it is not cached
+func (c *ResolverCache) resolve(file, searchPath string, lookup Resolver) (string, error) {
+	h := fnv.New64a()
+	h.Write([]byte(searchPath))
+	key := resolverKey{file: file, searchPath: h.Sum64()}
+
+	c.mu.RLock()
+	path, ok := c.paths[key]
+	c.mu.RUnlock()
+	if ok {
+		return path, nil
+	}
+
+	path, err := lookup(file)
+	if err != nil {
+		return "", err
+	}
+
+	c.mu.Lock()
+	c.paths[key] = path
+	c.mu.Unlock()
+	return path, nil
+}
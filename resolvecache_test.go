@@ -0,0 +1,85 @@
+package launcher
+
+import (
+	"context"
+	"errors"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"testing"
+)
+
+func TestResolverCache(t *testing.T) {
+
+	tools, other := t.TempDir(), t.TempDir()
+	tool := filepath.Join(tools, "cached")
+	if err := os.WriteFile(tool, []byte("#!/bin/sh\necho cached\n"), 0o755); err != nil {
+		t.Fatal(err)
+	}
+
+	cache := NewResolverCache()
+	newLauncher := func(dirs ...string) (*Launcher, error) {
+		l, err := NewWithOptions(context.Background(), "cached", nil, nil, WithLookupPath(dirs...), WithResolverCache(cache))
+		if err == nil {
+			l.Close()
+		}
+		return l, err
+	}
+
+	if _, err := newLauncher(tools); err != nil {
+		t.Fatal(err)
+	}
+	if cache.Len() != 1 {
+		t.Fatalf("expected a cached entry, got %v\n", cache.Len())
+	}
+
+	// Once cached, the directories are not searched again
+	if err := os.Remove(tool); err != nil {
+		t.Fatal(err)
+	}
+	l, err := newLauncher(tools)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if l.GetPath() != tool {
+		t.Fatalf("expected cached path %v, got %v\n", tool, l.GetPath())
+	}
+
+	// A different search path is resolved independently
+	if _, err := newLauncher(tools, other); !errors.Is(err, exec.ErrNotFound) {
+		t.Fatal(err)
+	}
+
+	cache.Clear()
+	if _, err := newLauncher(tools); !errors.Is(err, exec.ErrNotFound) {
+		t.Fatal(err)
+	}
+	if cache.Len() != 0 {
+		t.Fatalf("expected failed lookups not to be cached, got %v\n", cache.Len())
+	}
+}
+
+func TestResolverCacheUsesPATH(t *testing.T) {
+
+	cache := NewResolverCache()
+	for i := 0; i < 2; i++ {
+		l, err := NewWithOptions(context.Background(), "echo", nil, nil, WithResolverCache(cache))
+		if err != nil {
+			t.Fatal(err)
+		}
+		l.Close()
+	}
+	if cache.Len() != 1 {
+		t.Fatalf("expected a single cached entry, got %v\n", cache.Len())
+	}
+
+	t.Setenv("PATH", t.TempDir()+string(os.PathListSeparator)+os.Getenv("PATH"))
+	l, err := NewWithOptions(context.Background(), "echo", nil, nil, WithResolverCache(cache))
+	if err != nil {
+		t.Fatal(err)
+	}
+	l.Close()
+	if cache.Len() != 2 {
+		t.Fatalf("expected a changed PATH to be cached separately, got %v\n", cache.Len())
+	}
+}
@@ -0,0 +1,148 @@
+package launcher
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math/rand"
+	"time"
+)
+
+var errMissingClassifier = errors.New("classifier must be provided")
+
+// RetryClassifier returns true if a run which failed with the exit code
+// and error is transient, and so worth retrying.  The exit code is -1
+// if the process did not start or was terminated by a signal
+type RetryClassifier func(exitCode int, err error) bool
+
+// WithRetryClassifier sets the function which distinguishes transient
+// failures, which are retried by RunWithRetry and restarted by a
+// Supervisor with RestartOnFailure, from permanent ones, which are not.
+// Without a classifier, all failures are treated as transient
+func WithRetryClassifier(f RetryClassifier) Option {
+	return func(o *options) error {
+		if f == nil {
+			return errMissingClassifier
+		}
+		o.retryable = f
+		return nil
+	}
+}
+
+// WithRetryableExitCodes treats only processes exiting with one of
+// the codes as having failed transiently, such as 75 (EX_TEMPFAIL)
+func WithRetryableExitCodes(codes ...int) Option {
+	retryable := map[int]bool{}
+	for _, c := range codes {
+		retryable[c] = true
+	}
+	return WithRetryClassifier(func(exitCode int, err error) bool {
+		return retryable[exitCode]
+	})
+}
+
+// isRetryable returns true if a run ending with the exit code and
+// error failed transiently
+func (o *options) isRetryable(exitCode int, err error) bool {
+	if err == nil {
+		return false
+	}
+	if o.retryable == nil {
+		return true
+	}
+	return o.retryable(exitCode, err)
+}
+
+// RetryPolicy determines how RunWithRetry reruns a failing command
+type RetryPolicy struct {
+	// MaxAttempts limits the number of runs, with 0 being unlimited
+	MaxAttempts int
+	// BaseDelay is the pause after the first failure, which doubles
+	// after each subsequent failure
+	BaseDelay time.Duration
+	// MaxDelay, if set, limits the pause between runs
+	MaxDelay time.Duration
+	// Jitter, between 0 and 1, is the fraction of each pause which is
+	// randomised, spreading out the retries of competing callers
+	Jitter float64
+}
+
+// delay returns the pause before the run following the attempt,
+// where attempts are numbered from 1
+func (p RetryPolicy) delay(attempt int) time.Duration {
+	d := p.BaseDelay
+	for i := 1; i < attempt && d > 0; i++ {
+		d *= 2
+		if p.MaxDelay > 0 && d >= p.MaxDelay {
+			break
+		}
+	}
+	if p.MaxDelay > 0 && d > p.MaxDelay {
+		d = p.MaxDelay
+	}
+
+	if j := min(max(p.Jitter, 0), 1); j > 0 && d > 0 {
+		d -= time.Duration(j * rand.Float64() * float64(d))
+	}
+	return d
+}
+
+// RetryError is returned by RunWithRetry when the command has not
+// succeeded, recording each of the runs that was attempted
+type RetryError struct {
+	// Attempts holds the record of each run, in order
+	Attempts []RunRecord
+	// Err is the error of the final run, or the cause of the
+	// context ending if it ended first
+	Err error
+}
+
+func (e *RetryError) Error() string {
+	return fmt.Sprintf("failed after %d attempts: %v", len(e.Attempts), e.Err)
+}
+
+func (e *RetryError) Unwrap() error {
+	return e.Err
+}
+
+// RunWithRetry runs a fresh Launcher created from the Spec until it
+// succeeds, pausing between runs as determined by the policy.  If the
+// attempts are exhausted, a run fails permanently as determined by any
+// RetryClassifier of the Spec, or the context ends, a *RetryError is
+// returned.  The output of the runs is discarded
+func RunWithRetry(ctx context.Context, spec Spec, policy RetryPolicy) error {
+	if ctx == nil {
+		return ErrMissingContext
+	}
+	o, err := spec.options()
+	if err != nil {
+		return err
+	}
+
+	var attempts []RunRecord
+	for attempt := 1; ; attempt++ {
+		rec := runSpec(ctx, spec.File, spec, nil, nil, nil)
+		attempts = append(attempts, rec)
+		if rec.Err == nil {
+			return nil
+		}
+
+		if ctx.Err() != nil {
+			return &RetryError{Attempts: attempts, Err: context.Cause(ctx)}
+		}
+		if !o.isRetryable(rec.ExitCode, rec.Err) {
+			return &RetryError{Attempts: attempts, Err: rec.Err}
+		}
+		if policy.MaxAttempts > 0 && attempt >= policy.MaxAttempts {
+			return &RetryError{Attempts: attempts, Err: rec.Err}
+		}
+
+		timer := time.NewTimer(policy.delay(attempt))
+		select {
+		case <-timer.C:
+		case <-ctx.Done():
+			timer.Stop()
+			return &RetryError{Attempts: attempts, Err: context.Cause(ctx)}
+		}
+	}
+}
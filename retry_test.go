@@ -0,0 +1,114 @@
+package launcher
+
+import (
+	"context"
+	"errors"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+func TestRunWithRetrySucceeds(t *testing.T) {
+
+	// The command fails until it has been run three times
+	counter := filepath.Join(t.TempDir(), "count")
+	script := `echo x >> "$0"; [ $(wc -l < "$0") -ge 3 ]`
+
+	err := RunWithRetry(context.Background(), Spec{File: "sh", Args: []string{"-c", script, counter}}, RetryPolicy{
+		MaxAttempts: 5,
+		BaseDelay:   10 * time.Millisecond,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	b, err := os.ReadFile(counter)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(b) != 6 {
+		t.Fatalf("expected 3 runs, got %q\n", b)
+	}
+}
+
+func TestRunWithRetryExhausted(t *testing.T) {
+
+	err := RunWithRetry(context.Background(), Spec{File: "sh", Args: []string{"-c", "exit 3"}}, RetryPolicy{
+		MaxAttempts: 3,
+		BaseDelay:   10 * time.Millisecond,
+		Jitter:      0.5,
+	})
+
+	var retryErr *RetryError
+	if !errors.As(err, &retryErr) {
+		t.Fatalf("expected RetryError, got %v\n", err)
+	}
+	if len(retryErr.Attempts) != 3 {
+		t.Fatalf("expected 3 attempts, got %v\n", len(retryErr.Attempts))
+	}
+	for _, a := range retryErr.Attempts {
+		if a.ExitCode != 3 {
+			t.Fatalf("unexpected attempt %+v\n", a)
+		}
+	}
+
+	var exitErr *exec.ExitError
+	if !errors.As(err, &exitErr) {
+		t.Fatalf("expected ExitError to be unwrapped, got %v\n", err)
+	}
+}
+
+func TestRunWithRetryClassifier(t *testing.T) {
+
+	// Exit code 75 is transient, so is retried until the attempts are exhausted
+	spec := Spec{File: "sh", Args: []string{"-c", "exit 75"}, Options: []Option{WithRetryableExitCodes(75)}}
+	err := RunWithRetry(context.Background(), spec, RetryPolicy{MaxAttempts: 3})
+
+	var retryErr *RetryError
+	if !errors.As(err, &retryErr) || len(retryErr.Attempts) != 3 {
+		t.Fatalf("expected 3 attempts, got %v\n", err)
+	}
+
+	// Any other exit code is permanent, so is not retried
+	spec.Args = []string{"-c", "exit 1"}
+	err = RunWithRetry(context.Background(), spec, RetryPolicy{MaxAttempts: 3})
+	if !errors.As(err, &retryErr) || len(retryErr.Attempts) != 1 {
+		t.Fatalf("expected 1 attempt, got %v\n", err)
+	}
+
+	if _, err := NewWithOptions(context.Background(), "echo", nil, nil, WithRetryClassifier(nil)); err != errMissingClassifier {
+		t.Fatalf("expected errMissingClassifier, got %v\n", err)
+	}
+}
+
+func TestRunWithRetryContext(t *testing.T) {
+
+	ctx, cancel := context.WithTimeout(context.Background(), 100*time.Millisecond)
+	defer cancel()
+
+	err := RunWithRetry(ctx, Spec{File: "sh", Args: []string{"-c", "exit 1"}}, RetryPolicy{BaseDelay: time.Hour})
+	if !errors.Is(err, context.DeadlineExceeded) {
+		t.Fatalf("expected DeadlineExceeded, got %v\n", err)
+	}
+}
+
+func TestRetryPolicyDelay(t *testing.T) {
+
+	p := RetryPolicy{BaseDelay: 100 * time.Millisecond, MaxDelay: time.Second}
+
+	expected := []time.Duration{100 * time.Millisecond, 200 * time.Millisecond, 400 * time.Millisecond, 800 * time.Millisecond, time.Second, time.Second}
+	for i, d := range expected {
+		if got := p.delay(i + 1); got != d {
+			t.Fatalf("attempt %d: expected %v, got %v\n", i+1, d, got)
+		}
+	}
+
+	p.Jitter = 0.5
+	for i := 0; i < 100; i++ {
+		if d := p.delay(1); d < 50*time.Millisecond || d > 100*time.Millisecond {
+			t.Fatalf("jittered delay %v out of range\n", d)
+		}
+	}
+}
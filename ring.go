@@ -0,0 +1,67 @@
+package launcher
+
+import "sync"
+
+// WithOutputRingBuffer retains the last n lines of output, from stdout
+// and stderr in the order they arrived, which are returned by LastOutput
+// regardless of whether the output is otherwise read.  As the output is
+// then pumped by the Launcher, the process is blocked once 4MiB of output
+// is not yet read by the caller, unless WithLossyOutput is used
+func WithOutputRingBuffer(n int) Option {
+	return func(o *options) error {
+		if n <= 0 {
+			return errInvalidSize
+		}
+		o.ringLines = n
+		return nil
+	}
+}
+
+// LastOutput returns the lines retained by WithOutputRingBuffer,
+// oldest first, or nil if it is not set.  Lines may still be added
+// after the process exits, until both its pipes are exhausted
+func (l *Launcher) LastOutput() []string {
+	if l.ring == nil {
+		return nil
+	}
+	return l.ring.lines()
+}
+
+// prepareRing taps the output of the process into a ring, if required
+func (l *Launcher) prepareRing() {
+	if l.opts.ringLines == 0 {
+		return
+	}
+	l.ring = &lineRing{buf: make([]string, l.opts.ringLines)}
+	l.tapStdOut(newLineWriter(l.ring.add))
+	l.tapStdErr(newLineWriter(l.ring.add))
+}
+
+// lineRing holds the most recent lines added to it
+type lineRing struct {
+	mu   sync.Mutex
+	buf  []string
+	next int
+	full bool
+}
+
+func (r *lineRing) add(line string) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	r.buf[r.next] = line
+	r.next = (r.next + 1) % len(r.buf)
+	if r.next == 0 {
+		r.full = true
+	}
+}
+
+func (r *lineRing) lines() []string {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if !r.full {
+		return append([]string{}, r.buf[:r.next]...)
+	}
+	return append(append([]string{}, r.buf[r.next:]...), r.buf[:r.next]...)
+}
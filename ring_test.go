@@ -0,0 +1,177 @@
+package launcher
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"reflect"
+	"runtime"
+	"testing"
+	"time"
+)
+
+func TestOutputRingBuffer(t *testing.T) {
+
+	script := "for i in 1 2 3 4 5; do echo out$i; done; echo err >&2; printf partial"
+
+	l, err := NewWithOptions(context.Background(), "sh", nil, []string{"-c", script}, WithOutputRingBuffer(7))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer l.Close()
+
+	if len(l.LastOutput()) != 0 {
+		t.Fatalf("expected no output before start, got %v\n", l.LastOutput())
+	}
+
+	// The output is not read, but is still retained
+	if err := l.Run(); err != nil {
+		t.Fatal(err)
+	}
+	l.copyOutput(nil, nil)
+
+	// The order of lines across stdout and stderr depends on scheduling,
+	// but that within each is preserved
+	var stdout, stderr []string
+	for _, line := range l.LastOutput() {
+		if line == "err" {
+			stderr = append(stderr, line)
+		} else {
+			stdout = append(stdout, line)
+		}
+	}
+	expected := []string{"out1", "out2", "out3", "out4", "out5", "partial"}
+	if !reflect.DeepEqual(stdout, expected) || len(stderr) != 1 {
+		t.Fatalf("unexpected lines %q\n", l.LastOutput())
+	}
+}
+
+func TestOutputRingBufferLast(t *testing.T) {
+
+	l, err := NewWithOptions(context.Background(), "sh", nil, []string{"-c", "for i in 1 2 3 4 5; do echo out$i; done"}, WithOutputRingBuffer(3))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer l.Close()
+
+	if err := l.Run(); err != nil {
+		t.Fatal(err)
+	}
+	l.copyOutput(nil, nil)
+
+	expected := []string{"out3", "out4", "out5"}
+	if got := l.LastOutput(); !reflect.DeepEqual(got, expected) {
+		t.Fatalf("expected %q, got %q\n", expected, got)
+	}
+}
+
+func TestOutputRingBufferBackpressure(t *testing.T) {
+
+	// 64MB of output, which must all be received
+	const size = 64000000
+	script := fmt.Sprintf("yes $(printf '%%01000d' 0) | head -c %d", size)
+
+	l, err := NewWithOptions(context.Background(), "sh", nil, []string{"-c", script}, WithOutputRingBuffer(5))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer l.Close()
+
+	if err := l.Start(); err != nil {
+		t.Fatal(err)
+	}
+
+	// The process is blocked once the unread output reaches the limit
+	buf := l.cmdStdOut.(*pipeBuffer)
+	for {
+		buf.mu.Lock()
+		n := buf.buf.Len()
+		buf.mu.Unlock()
+		if n >= maxUnreadOutput {
+			break
+		}
+		time.Sleep(time.Millisecond)
+	}
+	time.Sleep(50 * time.Millisecond)
+	select {
+	case <-l.done:
+		t.Fatal("expected the process to be blocked by unread output")
+	default:
+	}
+
+	runtime.GC()
+	var m runtime.MemStats
+	runtime.ReadMemStats(&m)
+	if m.HeapAlloc > 32*1024*1024 {
+		t.Fatalf("expected unread output to be bounded, heap is %v bytes\n", m.HeapAlloc)
+	}
+
+	n, err := io.Copy(io.Discard, l.StdOutReader())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if n != size {
+		t.Fatalf("expected %d bytes of output, got %d\n", size, n)
+	}
+	if err := l.Wait(); err != nil {
+		t.Fatal(err)
+	}
+	if stdout, _ := l.DroppedOutput(); stdout != 0 {
+		t.Fatalf("expected no output to be dropped, got %d bytes\n", stdout)
+	}
+}
+
+func TestOutputRingBufferLossy(t *testing.T) {
+
+	// 64MB of output which is never read
+	const size = 64000000
+	script := fmt.Sprintf("yes $(printf '%%01000d' 0) | head -c %d", size)
+
+	l, err := NewWithOptions(context.Background(), "sh", nil, []string{"-c", script},
+		WithOutputRingBuffer(5), WithLossyOutput())
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer l.Close()
+
+	if err := l.Run(); err != nil {
+		t.Fatal(err)
+	}
+	l.pumps.Wait()
+
+	runtime.GC()
+	var m runtime.MemStats
+	runtime.ReadMemStats(&m)
+	if m.HeapAlloc > 32*1024*1024 {
+		t.Fatalf("expected unread output to be bounded, heap is %v bytes\n", m.HeapAlloc)
+	}
+	if n := len(l.LastOutput()); n != 5 {
+		t.Fatalf("expected 5 retained lines, got %v\n", n)
+	}
+
+	// The output read and that dropped account for all of it
+	n, err := io.Copy(io.Discard, l.StdOutReader())
+	if err != nil {
+		t.Fatal(err)
+	}
+	stdout, _ := l.DroppedOutput()
+	if stdout == 0 || n+stdout != size {
+		t.Fatalf("expected %d bytes read or dropped, got %d read and %d dropped\n", size, n, stdout)
+	}
+}
+
+func TestOutputRingBufferUnset(t *testing.T) {
+
+	l, err := New(context.Background(), "echo", nil, "foo")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer l.Close()
+
+	if err := l.Run(); err != nil {
+		t.Fatal(err)
+	}
+	if l.LastOutput() != nil {
+		t.Fatalf("expected no retained output, got %v\n", l.LastOutput())
+	}
+}
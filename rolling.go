@@ -0,0 +1,106 @@
+package launcher
+
+import (
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+)
+
+var errInvalidMaxUnavailable = errors.New("maxUnavailable must be positive")
+
+var errGroupStopping = errors.New("group is stopping")
+
+// RollingRestart restarts the members of the Group in the order they
+// were added, with at most maxUnavailable restarted at once.  Each
+// process is asked to terminate, and killed if it has not exited within
+// the grace period, before its replacement is started.  The next members
+// are only restarted once the replacements are ready, so that the Group
+// never loses more than maxUnavailable of its members.  If a replacement
+// fails to start, the restart ends, leaving the remaining members
+// untouched, and the failures are returned
+func (g *Group) RollingRestart(maxUnavailable int, grace time.Duration) error {
+	if maxUnavailable <= 0 {
+		return errInvalidMaxUnavailable
+	}
+
+	g.rollMu.Lock()
+	defer g.rollMu.Unlock()
+
+	g.mu.Lock()
+	started := g.started
+	g.mu.Unlock()
+
+	if !started {
+		return ErrNotStarted
+	}
+
+	for i := 0; i < len(g.members); i += maxUnavailable {
+		batch := g.members[i:min(i+maxUnavailable, len(g.members))]
+
+		errs := make([]error, len(batch))
+		var wg sync.WaitGroup
+		for j, m := range batch {
+			wg.Add(1)
+			go func(j int, m *member) {
+				defer wg.Done()
+				if err := g.restartMember(m, grace); err != nil {
+					errs[j] = fmt.Errorf("%s: %w", m.name, err)
+				}
+			}(j, m)
+		}
+		wg.Wait()
+
+		if err := errors.Join(errs...); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// restartMember terminates the process of the member and
+// replaces it, returning once the replacement is ready
+func (g *Group) restartMember(m *member, grace time.Duration) error {
+	g.mu.Lock()
+	if g.stopping {
+		g.mu.Unlock()
+		return errGroupStopping
+	}
+	old, drained := m.l, m.drained
+	replacing := make(chan struct{})
+	m.replacing = replacing
+	g.mu.Unlock()
+
+	defer func() {
+		g.mu.Lock()
+		m.replacing = nil
+		g.mu.Unlock()
+		close(replacing)
+	}()
+
+	if old != nil {
+		old.terminate(old.done, grace, ErrCancelled)
+		<-drained
+		old.Close()
+	}
+
+	l, err := g.launch(m)
+	if err != nil {
+		return err
+	}
+
+	g.mu.Lock()
+	if g.stopping {
+		g.mu.Unlock()
+		l.terminate(l.done, grace, ErrCancelled)
+		l.Close()
+		return errGroupStopping
+	}
+	m.l = l
+	m.drained = make(chan struct{})
+	drained = m.drained
+	g.mu.Unlock()
+
+	g.drain(m, l, drained)
+	return nil
+}
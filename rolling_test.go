@@ -0,0 +1,96 @@
+package launcher
+
+import (
+	"context"
+	"regexp"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestGroupRollingRestart(t *testing.T) {
+
+	g, err := NewGroup(context.Background())
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	worker := Spec{
+		File:    "sh",
+		Args:    []string{"-c", "sleep 0.1; echo ready; exec sleep 10"},
+		Options: []Option{WithReadiness(StdOutProbe(regexp.MustCompile("ready")))},
+	}
+	names := []string{"a", "b", "c"}
+	for _, name := range names {
+		if err := g.Add(name, worker); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := g.Start(); err != nil {
+		t.Fatal(err)
+	}
+	defer g.Stop(time.Second)
+
+	pids := map[string]int{}
+	for _, name := range names {
+		l, _ := g.Launcher(name)
+		pids[name] = l.Pid()
+	}
+
+	// Track the fewest members running at any point in the restart
+	var fewest atomic.Int64
+	fewest.Store(int64(len(names)))
+	done := make(chan struct{})
+	go func() {
+		for {
+			select {
+			case <-done:
+				return
+			case <-time.After(5 * time.Millisecond):
+			}
+			running := int64(0)
+			for _, name := range names {
+				if l, _ := g.Launcher(name); l.IsRunning() {
+					running++
+				}
+			}
+			if running < fewest.Load() {
+				fewest.Store(running)
+			}
+		}
+	}()
+
+	err = g.RollingRestart(1, time.Second)
+	close(done)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	for _, name := range names {
+		l, _ := g.Launcher(name)
+		if !l.IsRunning() || l.Pid() == pids[name] {
+			t.Fatalf("expected %s to be replaced by a running process\n", name)
+		}
+	}
+	if fewest.Load() < int64(len(names)-1) {
+		t.Fatalf("expected at most 1 member unavailable, got %v running\n", fewest.Load())
+	}
+}
+
+func TestGroupRollingRestartInvalid(t *testing.T) {
+
+	g, err := NewGroup(context.Background())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := g.Add("a", Spec{File: "sleep", Args: []string{"10"}}); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := g.RollingRestart(0, time.Second); err != errInvalidMaxUnavailable {
+		t.Fatalf("expected errInvalidMaxUnavailable, got %v\n", err)
+	}
+	if err := g.RollingRestart(1, time.Second); err != ErrNotStarted {
+		t.Fatalf("expected ErrNotStarted, got %v\n", err)
+	}
+}
@@ -0,0 +1,261 @@
+package launcher
+
+import (
+	"compress/gzip"
+	"errors"
+	"io"
+	"os"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+var errInvalidRotation = errors.New("rotation policy values must not be negative")
+
+// RotationPolicy determines when the file written by WithOutputFile is
+// rotated, and how many rotated files are kept.  A zero MaxSize or
+// Interval disables rotation by size or by time respectively
+type RotationPolicy struct {
+	// MaxSize is the size in bytes beyond which the file is rotated
+	MaxSize int64
+	// Interval is the age at which the file is rotated
+	Interval time.Duration
+	// MaxFiles is the number of rotated files kept, or all if zero
+	MaxFiles int
+	// Compress gzips the rotated files
+	Compress bool
+}
+
+// WithOutputFile appends the stdout and stderr of the process, as they
+// arrive, to the file at path, which is rotated according to the policy.
+// Rotated files are named path.1, path.2 and so on, from the most recent,
+// with a ".gz" suffix if compressed
+func WithOutputFile(path string, policy RotationPolicy) Option {
+	return func(o *options) error {
+		if path == "" {
+			return errMissingPath
+		}
+		if policy.MaxSize < 0 || policy.Interval < 0 || policy.MaxFiles < 0 {
+			return errInvalidRotation
+		}
+		o.outputFile = path
+		o.rotation = policy
+		return nil
+	}
+}
+
+// prepareOutputFile taps the output of the process into the file, if required
+func (l *Launcher) prepareOutputFile() error {
+	if l.opts.outputFile == "" {
+		return nil
+	}
+	f, err := openRotatingFile(l.opts.outputFile, l.opts.rotation)
+	if err != nil {
+		return err
+	}
+	l.outputFile = f
+	l.tapStdOut(f.writer())
+	l.tapStdErr(f.writer())
+	return nil
+}
+
+// rotatingFile is a file which is rotated by a RotationPolicy, and
+// which is closed once each of its writers is closed
+type rotatingFile struct {
+	mu       sync.Mutex
+	path     string
+	policy   RotationPolicy
+	f        *os.File
+	size     int64
+	opened   time.Time
+	writers  int
+	compress sync.WaitGroup
+}
+
+func openRotatingFile(path string, policy RotationPolicy) (*rotatingFile, error) {
+	r := &rotatingFile{path: path, policy: policy}
+	if err := r.open(); err != nil {
+		return nil, err
+	}
+	return r, nil
+}
+
+// open opens the file for appending, and must be called with the lock held
+func (r *rotatingFile) open() error {
+	f, err := os.OpenFile(r.path, os.O_WRONLY|os.O_CREATE|os.O_APPEND, 0o644)
+	if err != nil {
+		return err
+	}
+	info, err := f.Stat()
+	if err != nil {
+		f.Close()
+		return err
+	}
+	r.f, r.size, r.opened = f, info.Size(), time.Now()
+	return nil
+}
+
+// writer returns a writer of the file, which must be closed
+func (r *rotatingFile) writer() io.Writer {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	r.writers++
+	return &rotatingFileWriter{r: r}
+}
+
+// write appends b to the file, rotating it first if required.  Output
+// written after the file is closed, or which cannot be written, is
+// discarded so that the output of the process is not held up
+func (r *rotatingFile) write(b []byte) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if r.f == nil {
+		return
+	}
+	// A file which cannot be rotated continues to be written
+	if r.due(len(b)) && r.rotate() != nil && r.f == nil {
+		return
+	}
+	n, _ := r.f.Write(b)
+	r.size += int64(n)
+}
+
+// due returns true if the file should be rotated before writing n bytes
+func (r *rotatingFile) due(n int) bool {
+	if r.size == 0 {
+		return false
+	}
+	if r.policy.MaxSize > 0 && r.size+int64(n) > r.policy.MaxSize {
+		return true
+	}
+	return r.policy.Interval > 0 && time.Since(r.opened) >= r.policy.Interval
+}
+
+// rotate moves the file to path.1, shifting the existing rotated files
+// and removing those beyond MaxFiles, and then reopens the file
+func (r *rotatingFile) rotate() error {
+	r.f.Close()
+	r.f = nil
+
+	// Rotated files are only shifted once compression has finished
+	r.compress.Wait()
+
+	n := 0
+	for r.rotated(n+1) != "" {
+		n++
+	}
+	for i := n; i >= 1; i-- {
+		name := r.rotated(i)
+		if r.policy.MaxFiles > 0 && i >= r.policy.MaxFiles {
+			os.Remove(name)
+			continue
+		}
+		os.Rename(name, r.rotatedName(i+1, strings.HasSuffix(name, ".gz")))
+	}
+
+	name := r.rotatedName(1, false)
+	if err := os.Rename(r.path, name); err != nil {
+		r.open()
+		return err
+	}
+	if r.policy.Compress {
+		r.compress.Add(1)
+		go func() {
+			defer r.compress.Done()
+			compressFile(name)
+		}()
+	}
+	return r.open()
+}
+
+// rotatedName returns the name of the ith rotated file
+func (r *rotatingFile) rotatedName(i int, compressed bool) string {
+	name := r.path + "." + strconv.Itoa(i)
+	if compressed {
+		name += ".gz"
+	}
+	return name
+}
+
+// rotated returns the name of the ith rotated file if it exists
+func (r *rotatingFile) rotated(i int) string {
+	for _, compressed := range []bool{false, true} {
+		name := r.rotatedName(i, compressed)
+		if _, err := os.Stat(name); err == nil {
+			return name
+		}
+	}
+	return ""
+}
+
+// compressFile replaces the file with a gzipped copy
+func compressFile(name string) error {
+	src, err := os.Open(name)
+	if err != nil {
+		return err
+	}
+	defer src.Close()
+
+	dst, err := os.OpenFile(name+".gz", os.O_WRONLY|os.O_CREATE|os.O_TRUNC, 0o644)
+	if err != nil {
+		return err
+	}
+	zw := gzip.NewWriter(dst)
+	_, err = io.Copy(zw, src)
+	if cerr := zw.Close(); err == nil {
+		err = cerr
+	}
+	if cerr := dst.Close(); err == nil {
+		err = cerr
+	}
+	if err != nil {
+		os.Remove(name + ".gz")
+		return err
+	}
+	return os.Remove(name)
+}
+
+// release closes a writer, closing the file once all are closed
+func (r *rotatingFile) release() {
+	r.mu.Lock()
+	r.writers--
+	last := r.writers == 0
+	r.mu.Unlock()
+
+	if last {
+		r.Close()
+	}
+}
+
+// Close closes the file, once any compression has finished
+func (r *rotatingFile) Close() error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	var err error
+	if r.f != nil {
+		err = r.f.Close()
+		r.f = nil
+	}
+	r.compress.Wait()
+	return err
+}
+
+// rotatingFileWriter is a writer of a rotatingFile
+type rotatingFileWriter struct {
+	r    *rotatingFile
+	once sync.Once
+}
+
+func (w *rotatingFileWriter) Write(b []byte) (int, error) {
+	w.r.write(b)
+	return len(b), nil
+}
+
+func (w *rotatingFileWriter) Close() error {
+	w.once.Do(w.r.release)
+	return nil
+}
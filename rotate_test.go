@@ -0,0 +1,98 @@
+package launcher
+
+import (
+	"compress/gzip"
+	"context"
+	"io"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+func TestOutputFile(t *testing.T) {
+
+	path := filepath.Join(t.TempDir(), "out.log")
+
+	l, err := NewWithOptions(context.Background(), "sh", nil, []string{"-c", "echo out; sleep 0.1; echo err >&2"}, WithOutputFile(path, RotationPolicy{}))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := l.Run(); err != nil {
+		t.Fatal(err)
+	}
+	l.Close()
+
+	if b, _ := os.ReadFile(path); string(b) != "out\nerr\n" {
+		t.Fatalf("unexpected file content %q\n", b)
+	}
+}
+
+func TestOutputFileRotation(t *testing.T) {
+
+	path := filepath.Join(t.TempDir(), "out.log")
+
+	r, err := openRotatingFile(path, RotationPolicy{MaxSize: 10, MaxFiles: 2})
+	if err != nil {
+		t.Fatal(err)
+	}
+	w := r.writer()
+	for _, s := range []string{"aaaaaa\n", "bbbbbb\n", "cccccc\n", "dddddd\n"} {
+		w.Write([]byte(s))
+	}
+	w.(io.Closer).Close()
+
+	expected := map[string]string{path: "dddddd\n", path + ".1": "cccccc\n", path + ".2": "bbbbbb\n"}
+	for name, content := range expected {
+		if b, _ := os.ReadFile(name); string(b) != content {
+			t.Fatalf("expected %q in %v, got %q\n", content, name, b)
+		}
+	}
+	if _, err := os.Stat(path + ".3"); !os.IsNotExist(err) {
+		t.Fatalf("expected only %v rotated files\n", 2)
+	}
+}
+
+func TestOutputFileRotationCompressed(t *testing.T) {
+
+	path := filepath.Join(t.TempDir(), "out.log")
+
+	r, err := openRotatingFile(path, RotationPolicy{Interval: 50 * time.Millisecond, Compress: true})
+	if err != nil {
+		t.Fatal(err)
+	}
+	w := r.writer()
+	w.Write([]byte("first\n"))
+	time.Sleep(100 * time.Millisecond)
+	w.Write([]byte("second\n"))
+	w.(io.Closer).Close()
+
+	f, err := os.Open(path + ".1.gz")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer f.Close()
+	zr, err := gzip.NewReader(f)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if b, _ := io.ReadAll(zr); string(b) != "first\n" {
+		t.Fatalf("unexpected rotated content %q\n", b)
+	}
+	if _, err := os.Stat(path + ".1"); !os.IsNotExist(err) {
+		t.Fatal("expected the uncompressed file to be removed\n")
+	}
+	if b, _ := os.ReadFile(path); string(b) != "second\n" {
+		t.Fatalf("unexpected file content %q\n", b)
+	}
+}
+
+func TestOutputFileInvalid(t *testing.T) {
+
+	if _, err := NewWithOptions(context.Background(), "echo", nil, nil, WithOutputFile("", RotationPolicy{})); err != errMissingPath {
+		t.Fatalf("expected errMissingPath, got %v\n", err)
+	}
+	if _, err := NewWithOptions(context.Background(), "echo", nil, nil, WithOutputFile("x", RotationPolicy{MaxFiles: -1})); err != errInvalidRotation {
+		t.Fatalf("expected errInvalidRotation, got %v\n", err)
+	}
+}
@@ -0,0 +1,81 @@
+package launcher
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+)
+
+// FailurePolicy determines how RunAllWithPolicy responds
+// to the failure of one of its launchers
+type FailurePolicy int
+
+const (
+	// CancelOnFailure cancels the remaining launchers as soon as one fails
+	CancelOnFailure FailurePolicy = iota
+	// ContinueOnFailure allows the remaining launchers to run to completion
+	ContinueOnFailure
+)
+
+// RunAll runs the launchers concurrently, cancelling those still
+// running as soon as one fails, and returns the joined errors of
+// the launchers that failed
+func RunAll(ctx context.Context, launchers ...*Launcher) error {
+	return RunAllWithPolicy(ctx, CancelOnFailure, launchers...)
+}
+
+// RunAllWithPolicy runs the launchers concurrently, waiting for all
+// to complete and returning the joined errors of those that failed.
+// Launchers cancelled because of the failure of another, as determined
+// by the policy, do not contribute their errors.  All launchers are
+// cancelled if the context ends, and its error is included
+func RunAllWithPolicy(ctx context.Context, policy FailurePolicy, launchers ...*Launcher) error {
+	if ctx == nil {
+		return ErrMissingContext
+	}
+	runCtx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	errs := make([]error, len(launchers))
+
+	var wg sync.WaitGroup
+	for i, l := range launchers {
+		wg.Add(1)
+		go func(i int, l *Launcher) {
+			defer wg.Done()
+
+			var mu sync.Mutex
+			cancelled := false
+			stop := context.AfterFunc(runCtx, func() {
+				mu.Lock()
+				cancelled = true
+				mu.Unlock()
+				l.CancelWithCause(context.Cause(runCtx))
+			})
+			defer stop()
+
+			err := l.Run()
+			if err == nil {
+				return
+			}
+
+			mu.Lock()
+			defer mu.Unlock()
+			if cancelled {
+				return
+			}
+
+			errs[i] = fmt.Errorf("%s: %w", l.GetFile(), err)
+			if policy == CancelOnFailure {
+				cancel()
+			}
+		}(i, l)
+	}
+	wg.Wait()
+
+	if ctx.Err() != nil {
+		errs = append(errs, context.Cause(ctx))
+	}
+	return errors.Join(errs...)
+}
@@ -0,0 +1,95 @@
+package launcher
+
+import (
+	"context"
+	"errors"
+	"os/exec"
+	"testing"
+	"time"
+)
+
+func TestRunAll(t *testing.T) {
+
+	var launchers []*Launcher
+	for _, s := range []string{"exit 0", "sleep 0.1"} {
+		l, err := New(context.Background(), "sh", []string{}, "-c", s)
+		if err != nil {
+			t.Fatal(err)
+		}
+		defer l.Close()
+		launchers = append(launchers, l)
+	}
+
+	if err := RunAll(context.Background(), launchers...); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestRunAllCancelOnFailure(t *testing.T) {
+
+	sleeper, err := New(context.Background(), "sleep", []string{}, "10")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer sleeper.Close()
+
+	failer, err := New(context.Background(), "sh", []string{}, "-c", "exit 2")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer failer.Close()
+
+	start := time.Now()
+	err = RunAll(context.Background(), sleeper, failer)
+
+	var exitErr *exec.ExitError
+	if !errors.As(err, &exitErr) || exitErr.ExitCode() != 2 {
+		t.Fatal(err)
+	}
+
+	if time.Since(start) > 5*time.Second {
+		t.Fatal("sleeper was not cancelled")
+	}
+
+	if sleeper.IsRunning() {
+		t.Fatal("still running")
+	}
+}
+
+func TestRunAllContinueOnFailure(t *testing.T) {
+
+	var launchers []*Launcher
+	for _, s := range []string{"exit 1", "sleep 0.2; exit 3"} {
+		l, err := New(context.Background(), "sh", []string{}, "-c", s)
+		if err != nil {
+			t.Fatal(err)
+		}
+		defer l.Close()
+		launchers = append(launchers, l)
+	}
+
+	err := RunAllWithPolicy(context.Background(), ContinueOnFailure, launchers...)
+	if err == nil {
+		t.Fatal("expected error")
+	}
+
+	if launchers[1].ExitCode() != 3 {
+		t.Fatalf("expected second launcher to complete, got exit code %v\n", launchers[1].ExitCode())
+	}
+}
+
+func TestRunAllWithCtxCancel(t *testing.T) {
+
+	l, err := New(context.Background(), "sleep", []string{}, "10")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer l.Close()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 100*time.Millisecond)
+	defer cancel()
+
+	if err := RunAll(ctx, l); !errors.Is(err, context.DeadlineExceeded) {
+		t.Fatal(err)
+	}
+}
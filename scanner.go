@@ -0,0 +1,58 @@
+package launcher
+
+import (
+	"bufio"
+	"bytes"
+	"io"
+)
+
+// StdOutScanner returns a bufio.Scanner of the stdout of the process,
+// splitting it with split, or into lines if split is nil, and accepting
+// tokens of up to maxTokenSize bytes, or bufio.MaxScanTokenSize if zero
+func (l *Launcher) StdOutScanner(split bufio.SplitFunc, maxTokenSize int) *bufio.Scanner {
+	return newScanner(l.StdOutReader(), split, maxTokenSize)
+}
+
+// StdErrScanner returns a bufio.Scanner of the stderr of the process,
+// in the same way as StdOutScanner
+func (l *Launcher) StdErrScanner(split bufio.SplitFunc, maxTokenSize int) *bufio.Scanner {
+	return newScanner(l.StdErrReader(), split, maxTokenSize)
+}
+
+func newScanner(r io.Reader, split bufio.SplitFunc, maxTokenSize int) *bufio.Scanner {
+	scanner := bufio.NewScanner(r)
+	if split != nil {
+		scanner.Split(split)
+	}
+	if maxTokenSize > 0 {
+		scanner.Buffer(make([]byte, 0, min(maxTokenSize, 4096)), maxTokenSize)
+	}
+	return scanner
+}
+
+// ScanNUL is a bufio.SplitFunc returning records terminated by a NUL
+// byte, such as those written by find -print0 or xargs -0.  The final
+// record need not be terminated
+func ScanNUL(data []byte, atEOF bool) (int, []byte, error) {
+	if i := bytes.IndexByte(data, 0); i >= 0 {
+		return i + 1, data[:i], nil
+	}
+	if atEOF && len(data) > 0 {
+		return len(data), data, nil
+	}
+	return 0, nil, nil
+}
+
+// ScanBlocks returns a bufio.SplitFunc returning blocks of size bytes,
+// of which only the final block may be shorter
+func ScanBlocks(size int) bufio.SplitFunc {
+	return func(data []byte, atEOF bool) (int, []byte, error) {
+		if len(data) >= size {
+			return size, data[:size], nil
+		}
+		if atEOF && len(data) > 0 {
+			return len(data), data, nil
+		}
+		return 0, nil, nil
+	}
+}
@@ -0,0 +1,65 @@
+package launcher
+
+import (
+	"bufio"
+	"context"
+	"reflect"
+	"testing"
+)
+
+func TestStdOutScanner(t *testing.T) {
+
+	tests := []struct {
+		name     string
+		script   string
+		split    bufio.SplitFunc
+		expected []string
+	}{
+		{"lines", `printf 'a\r\nb\nc'`, nil, []string{"a", "b", "c"}},
+		{"nul", `printf 'x y\0z\0'`, ScanNUL, []string{"x y", "z"}},
+		{"nul-unterminated", `printf 'x\0y'`, ScanNUL, []string{"x", "y"}},
+		{"blocks", `printf abcdefg`, ScanBlocks(3), []string{"abc", "def", "g"}},
+	}
+
+	for _, test := range tests {
+		l, err := New(context.Background(), "sh", nil, "-c", test.script)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if err := l.Start(); err != nil {
+			t.Fatal(err)
+		}
+
+		var tokens []string
+		scanner := l.StdOutScanner(test.split, 0)
+		for scanner.Scan() {
+			tokens = append(tokens, scanner.Text())
+		}
+		l.Close()
+
+		if scanner.Err() != nil || !reflect.DeepEqual(tokens, test.expected) {
+			t.Fatalf("%v: expected %q, got %q (%v)\n", test.name, test.expected, tokens, scanner.Err())
+		}
+	}
+}
+
+func TestStdErrScannerMaxTokenSize(t *testing.T) {
+
+	l, err := New(context.Background(), "sh", nil, "-c", "echo short >&2; echo muchtoolongforthelimit >&2")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer l.Close()
+
+	if err := l.Start(); err != nil {
+		t.Fatal(err)
+	}
+
+	scanner := l.StdErrScanner(nil, 10)
+	if !scanner.Scan() || scanner.Text() != "short" {
+		t.Fatalf("unexpected token %q\n", scanner.Text())
+	}
+	if scanner.Scan() || scanner.Err() != bufio.ErrTooLong {
+		t.Fatalf("expected bufio.ErrTooLong, got %v\n", scanner.Err())
+	}
+}
@@ -0,0 +1,311 @@
+package launcher
+
+import (
+	"context"
+	"errors"
+	"io"
+	"math/rand"
+	"sync"
+	"time"
+)
+
+var errMissingSchedule = errors.New("schedule must be provided")
+
+// OverlapPolicy determines what a Scheduler does when a job is
+// due to run while its previous run is still in progress
+type OverlapPolicy int
+
+const (
+	// OverlapSkip skips the run, recording it as skipped
+	OverlapSkip OverlapPolicy = iota
+	// OverlapQueue runs the job again once the previous run has finished
+	OverlapQueue
+	// OverlapKillPrevious kills the previous run before starting the next
+	OverlapKillPrevious
+)
+
+// Job describes a Spec to be run by a Scheduler
+type Job struct {
+	// Name identifies the job, and must be unique within the Scheduler
+	Name string
+	// Schedule determines when the job runs
+	Schedule Schedule
+	// Spec defines the process run by the job
+	Spec Spec
+	// Overlap determines what happens if the job is due while still running
+	Overlap OverlapPolicy
+	// Jitter, if set, delays each run by a random duration up to Jitter
+	Jitter time.Duration
+}
+
+// RunRecord describes a single run of a scheduled job
+type RunRecord struct {
+	Job      string
+	Start    time.Time
+	End      time.Time
+	Pid      int
+	ExitCode int
+	Err      error
+	// Skipped is true if the run did not happen because
+	// the previous run was still in progress
+	Skipped bool
+}
+
+// scheduledRun is a run of a job which is in progress
+type scheduledRun struct {
+	ctx      context.Context
+	l        *Launcher
+	cancel   context.CancelFunc
+	finished chan struct{}
+}
+
+// scheduledJob is a Job added to a Scheduler
+type scheduledJob struct {
+	Job
+	mu     sync.Mutex
+	active *scheduledRun
+	queued int
+}
+
+// Scheduler runs Jobs according to their Schedules until it is stopped
+type Scheduler struct {
+	// Output, if set, returns the writers receiving the stdout and stderr
+	// of the named job, whose output is otherwise discarded
+	Output func(name string) (stdout, stderr io.Writer)
+	// Records, if set, receives a RunRecord as each run completes or is
+	// skipped.  Records are dropped if the channel is not ready to receive them
+	Records chan<- RunRecord
+
+	ctx    context.Context
+	cancel context.CancelFunc
+	mu     sync.Mutex
+	jobs   map[string]*scheduledJob
+	wg     sync.WaitGroup
+}
+
+// NewScheduler creates a new Scheduler, which stops running
+// jobs when the context is cancelled
+func NewScheduler(ctx context.Context) (*Scheduler, error) {
+	if ctx == nil {
+		return nil, ErrMissingContext
+	}
+	myCtx, cancel := context.WithCancel(ctx)
+
+	return &Scheduler{
+		ctx:    myCtx,
+		cancel: cancel,
+		jobs:   map[string]*scheduledJob{},
+	}, nil
+}
+
+// Add begins running the Job according to its Schedule
+func (s *Scheduler) Add(job Job) error {
+	if job.Schedule == nil {
+		return errMissingSchedule
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if err := s.ctx.Err(); err != nil {
+		return err
+	}
+	if _, ok := s.jobs[job.Name]; ok {
+		return ErrDuplicateName
+	}
+
+	j := &scheduledJob{Job: job}
+	s.jobs[job.Name] = j
+
+	s.wg.Add(1)
+	go s.schedule(j)
+
+	return nil
+}
+
+// Stop ends scheduling, asking any running processes to terminate and
+// killing those which have not exited within the grace period, and
+// returns once all runs have finished
+func (s *Scheduler) Stop(grace time.Duration) error {
+	s.mu.Lock()
+	jobs := make([]*scheduledJob, 0, len(s.jobs))
+	for _, j := range s.jobs {
+		jobs = append(jobs, j)
+	}
+	s.mu.Unlock()
+
+	var wg sync.WaitGroup
+	for _, j := range jobs {
+		j.mu.Lock()
+		j.queued = 0
+		var l *Launcher
+		if j.active != nil {
+			l = j.active.l
+		}
+		j.mu.Unlock()
+
+		if l == nil {
+			continue
+		}
+		wg.Add(1)
+		go func(l *Launcher) {
+			defer wg.Done()
+			l.terminate(l.done, grace, ErrCancelled)
+		}(l)
+	}
+	wg.Wait()
+
+	s.cancel()
+	s.wg.Wait()
+	return nil
+}
+
+// schedule triggers the job each time it is due
+func (s *Scheduler) schedule(j *scheduledJob) {
+	defer s.wg.Done()
+
+	next := j.Schedule.Next(time.Now())
+	for !next.IsZero() {
+		delay := time.Until(next)
+		if j.Jitter > 0 {
+			delay += time.Duration(rand.Int63n(int64(j.Jitter)))
+		}
+
+		timer := time.NewTimer(delay)
+		select {
+		case <-s.ctx.Done():
+			timer.Stop()
+			return
+		case <-timer.C:
+		}
+
+		s.trigger(j)
+
+		// Times missed while the job was being triggered are not caught up
+		now := time.Now()
+		for !next.IsZero() && !next.After(now) {
+			next = j.Schedule.Next(next)
+		}
+	}
+}
+
+// trigger runs the job, applying its OverlapPolicy if it is still running
+func (s *Scheduler) trigger(j *scheduledJob) {
+	j.mu.Lock()
+	prev := j.active
+	if prev != nil {
+		switch j.Overlap {
+		case OverlapSkip:
+			j.mu.Unlock()
+			now := time.Now()
+			s.record(RunRecord{Job: j.Name, Start: now, End: now, ExitCode: -1, Skipped: true})
+			return
+		case OverlapQueue:
+			j.queued++
+			j.mu.Unlock()
+			return
+		}
+	}
+	j.mu.Unlock()
+
+	if prev != nil {
+		prev.cancel()
+		<-prev.finished
+	}
+
+	r := s.newRun()
+	j.mu.Lock()
+	j.active = r
+	j.mu.Unlock()
+
+	s.wg.Add(1)
+	go s.execute(j, r)
+}
+
+// newRun creates a run which can be killed independently of the Scheduler
+func (s *Scheduler) newRun() *scheduledRun {
+	ctx, cancel := context.WithCancel(s.ctx)
+	return &scheduledRun{
+		ctx:      ctx,
+		cancel:   cancel,
+		finished: make(chan struct{}),
+	}
+}
+
+// execute performs the run, followed by any runs queued meanwhile
+func (s *Scheduler) execute(j *scheduledJob, r *scheduledRun) {
+	defer s.wg.Done()
+
+	for {
+		s.record(s.runOnce(j, r))
+		r.cancel()
+
+		j.mu.Lock()
+		if j.queued == 0 || s.ctx.Err() != nil {
+			j.active = nil
+			j.mu.Unlock()
+			close(r.finished)
+			return
+		}
+		j.queued--
+		prev := r
+		r = s.newRun()
+		j.active = r
+		j.mu.Unlock()
+		close(prev.finished)
+	}
+}
+
+// runOnce creates and runs a process from the job's Spec
+func (s *Scheduler) runOnce(j *scheduledJob, r *scheduledRun) RunRecord {
+	var stdout, stderr io.Writer
+	if s.Output != nil {
+		stdout, stderr = s.Output(j.Name)
+	}
+
+	return runSpec(r.ctx, j.Name, j.Spec, stdout, stderr, func(l *Launcher) {
+		j.mu.Lock()
+		r.l = l
+		j.mu.Unlock()
+	})
+}
+
+// runSpec creates and runs a process from the Spec, copying its output
+// to the writers, and calls started, if set, once it has started
+func runSpec(ctx context.Context, name string, spec Spec, stdout, stderr io.Writer, started func(l *Launcher)) (rec RunRecord) {
+	rec = RunRecord{Job: name, Start: time.Now(), ExitCode: -1}
+	defer func() { rec.End = time.Now() }()
+
+	l, err := spec.New(ctx)
+	if err != nil {
+		rec.Err = err
+		return rec
+	}
+	defer l.Close()
+
+	if err := l.Start(); err != nil {
+		rec.Err = err
+		return rec
+	}
+	rec.Pid = l.Pid()
+
+	if started != nil {
+		started(l)
+	}
+	l.copyOutput(stdout, stderr)
+
+	rec.Err = l.Wait()
+	rec.ExitCode = l.ExitCode()
+	return rec
+}
+
+// record sends the RunRecord, if there is a receiver ready for it
+func (s *Scheduler) record(rec RunRecord) {
+	if s.Records == nil {
+		return
+	}
+	select {
+	case s.Records <- rec:
+	default:
+	}
+}
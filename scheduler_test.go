@@ -0,0 +1,150 @@
+package launcher
+
+import (
+	"context"
+	"testing"
+	"time"
+)
+
+func collectRecords(ch <-chan RunRecord, n int, timeout time.Duration) []RunRecord {
+	var recs []RunRecord
+	deadline := time.After(timeout)
+	for len(recs) < n {
+		select {
+		case r := <-ch:
+			recs = append(recs, r)
+		case <-deadline:
+			return recs
+		}
+	}
+	return recs
+}
+
+func TestSchedulerEvery(t *testing.T) {
+
+	s, err := NewScheduler(context.Background())
+	if err != nil {
+		t.Fatal(err)
+	}
+	records := make(chan RunRecord, 10)
+	s.Records = records
+
+	err = s.Add(Job{Name: "echo", Schedule: Every(50 * time.Millisecond), Spec: Spec{File: "echo", Args: []string{"hi"}}})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	recs := collectRecords(records, 3, 5*time.Second)
+	s.Stop(time.Second)
+
+	if len(recs) != 3 {
+		t.Fatalf("expected 3 records, got %v\n", len(recs))
+	}
+	for _, r := range recs {
+		if r.Job != "echo" || r.Err != nil || r.ExitCode != 0 || r.Pid == 0 || r.Skipped || r.End.Before(r.Start) {
+			t.Fatalf("unexpected record %+v\n", r)
+		}
+	}
+}
+
+func TestSchedulerOverlapSkip(t *testing.T) {
+
+	s, err := NewScheduler(context.Background())
+	if err != nil {
+		t.Fatal(err)
+	}
+	records := make(chan RunRecord, 10)
+	s.Records = records
+
+	err = s.Add(Job{Name: "sleep", Schedule: Every(50 * time.Millisecond), Spec: Spec{File: "sleep", Args: []string{"10"}}})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	recs := collectRecords(records, 2, 5*time.Second)
+	s.Stop(time.Second)
+
+	if len(recs) != 2 || !recs[0].Skipped || !recs[1].Skipped {
+		t.Fatalf("expected skipped runs, got %+v\n", recs)
+	}
+}
+
+func TestSchedulerOverlapKillPrevious(t *testing.T) {
+
+	s, err := NewScheduler(context.Background())
+	if err != nil {
+		t.Fatal(err)
+	}
+	records := make(chan RunRecord, 10)
+	s.Records = records
+
+	err = s.Add(Job{Name: "sleep", Schedule: Every(100 * time.Millisecond), Overlap: OverlapKillPrevious, Spec: Spec{File: "sleep", Args: []string{"10"}}})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	recs := collectRecords(records, 2, 5*time.Second)
+	s.Stop(time.Second)
+
+	if len(recs) != 2 {
+		t.Fatalf("expected 2 records, got %v\n", len(recs))
+	}
+	for _, r := range recs {
+		if r.Skipped || r.Err == nil {
+			t.Fatalf("expected killed run, got %+v\n", r)
+		}
+	}
+	if recs[0].Pid == recs[1].Pid {
+		t.Fatal("expected different processes")
+	}
+}
+
+func TestSchedulerOverlapQueue(t *testing.T) {
+
+	s, err := NewScheduler(context.Background())
+	if err != nil {
+		t.Fatal(err)
+	}
+	records := make(chan RunRecord, 10)
+	s.Records = records
+
+	err = s.Add(Job{Name: "sleep", Schedule: Every(50 * time.Millisecond), Overlap: OverlapQueue, Spec: Spec{File: "sleep", Args: []string{"0.2"}}})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	recs := collectRecords(records, 2, 5*time.Second)
+	s.Stop(time.Second)
+
+	if len(recs) != 2 {
+		t.Fatalf("expected 2 records, got %v\n", len(recs))
+	}
+	if recs[1].Start.Before(recs[0].End) {
+		t.Fatal("queued run started before previous run finished")
+	}
+	for _, r := range recs {
+		if r.Skipped || r.Err != nil {
+			t.Fatalf("unexpected record %+v\n", r)
+		}
+	}
+}
+
+func TestSchedulerAdd(t *testing.T) {
+
+	s, err := NewScheduler(context.Background())
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer s.Stop(time.Second)
+
+	job := Job{Name: "a", Schedule: Every(time.Hour), Spec: Spec{File: "echo"}}
+	if err := s.Add(job); err != nil {
+		t.Fatal(err)
+	}
+	if err := s.Add(job); err != ErrDuplicateName {
+		t.Fatalf("expected ErrDuplicateName, got %v\n", err)
+	}
+	if err := s.Add(Job{Name: "b"}); err != errMissingSchedule {
+		t.Fatalf("expected errMissingSchedule, got %v\n", err)
+	}
+}
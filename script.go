@@ -0,0 +1,73 @@
+package launcher
+
+import (
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// scriptArgs returns the suffix of a script file run by the interpreter,
+// and the arguments with which the interpreter runs it
+func scriptArgs(interpreter, file string) (suffix string, args []string) {
+	name := strings.TrimSuffix(strings.ToLower(filepath.Base(interpreter)), ".exe")
+	switch name {
+	case "cmd":
+		return ".cmd", []string{"/C", file}
+	case "powershell", "pwsh":
+		return ".ps1", []string{"-NoProfile", "-File", file}
+	default:
+		return "", []string{file}
+	}
+}
+
+// NewScript creates a new instance of Launcher which runs the script by
+// passing it to the interpreter, such as sh or python3, in a temporary
+// file readable only by the current user.  The file is removed once the
+// process has exited, or by Close if it is never started.  As with New,
+// the environment of the process is empty unless configured by Options
+func NewScript(ctx context.Context, interpreter string, script string, opts ...Option) (*Launcher, error) {
+	if ctx == nil {
+		return nil, ErrMissingContext
+	}
+
+	suffix, _ := scriptArgs(interpreter, "")
+	f, err := os.CreateTemp("", "launcher-script-*"+suffix)
+	if err != nil {
+		return nil, err
+	}
+	file := f.Name()
+
+	err = f.Chmod(0o700)
+	if err == nil {
+		_, err = f.WriteString(script)
+	}
+	if cerr := f.Close(); err == nil {
+		err = cerr
+	}
+	if err != nil {
+		os.Remove(file)
+		return nil, err
+	}
+
+	_, args := scriptArgs(interpreter, file)
+	l, err := NewWithOptions(ctx, interpreter, nil, args, opts...)
+	if err != nil {
+		os.Remove(file)
+		return nil, err
+	}
+	l.script = file
+	return l, nil
+}
+
+// removeScript removes the temporary file of the script, if any
+func (l *Launcher) removeScript() error {
+	if l.script == "" {
+		return nil
+	}
+	if err := os.Remove(l.script); err != nil && !errors.Is(err, os.ErrNotExist) {
+		return err
+	}
+	return nil
+}
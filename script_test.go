@@ -0,0 +1,72 @@
+package launcher
+
+import (
+	"context"
+	"errors"
+	"io"
+	"os"
+	"testing"
+)
+
+func TestNewScript(t *testing.T) {
+
+	script := `
+greeting="hello"
+for name in foo bar; do
+	echo "$greeting $name"
+done
+`
+	l, err := NewScript(context.Background(), "sh", script)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer l.Close()
+
+	file := l.GetArgs()[0]
+	fi, err := os.Stat(file)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if fi.Mode().Perm() != 0o700 {
+		t.Fatalf("expected script to be private, got %v\n", fi.Mode())
+	}
+
+	if err := l.Run(); err != nil {
+		t.Fatal(err)
+	}
+	b, err := io.ReadAll(l.StdOutReader())
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if string(b) != "hello foo\nhello bar\n" {
+		t.Fatalf("unexpected output %q\n", b)
+	}
+	if _, err := os.Stat(file); !errors.Is(err, os.ErrNotExist) {
+		t.Fatalf("expected script to be removed after exit, got %v\n", err)
+	}
+}
+
+func TestNewScriptUnstarted(t *testing.T) {
+
+	l, err := NewScript(context.Background(), "sh", "echo unused")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	file := l.GetArgs()[0]
+	if err := l.Close(); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := os.Stat(file); !errors.Is(err, os.ErrNotExist) {
+		t.Fatalf("expected script to be removed by Close, got %v\n", err)
+	}
+}
+
+func TestNewScriptInvalidInterpreter(t *testing.T) {
+
+	_, err := NewScript(context.Background(), "zzzUnknownzzz", "echo unused")
+	if err == nil {
+		t.Fatal("expected unknown interpreter to fail")
+	}
+}
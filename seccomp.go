@@ -0,0 +1,82 @@
+package launcher
+
+import (
+	"errors"
+	"fmt"
+	"syscall"
+)
+
+//go:generate go run ./internal/gensysnum
+
+var errMissingProfile = errors.New("seccomp profile must be provided")
+
+var errUnknownSyscall = errors.New("unknown syscall")
+
+// SeccompAction is the action taken by a seccomp filter for a syscall
+type SeccompAction uint32
+
+const (
+	// SeccompAllow allows the syscall
+	SeccompAllow SeccompAction = 0x7fff0000
+	// SeccompLog allows the syscall, logging it to the audit log
+	SeccompLog SeccompAction = 0x7ffc0000
+	// SeccompKillProcess kills the process
+	SeccompKillProcess SeccompAction = 0x80000000
+)
+
+// seccompErrno is the action returning an errno, held in the low bits
+const seccompErrno SeccompAction = 0x00050000
+
+// SeccompErrno returns the action failing the syscall with the errno
+func SeccompErrno(errno syscall.Errno) SeccompAction {
+	return seccompErrno | SeccompAction(errno&0xffff)
+}
+
+// SeccompRule applies the action to the syscalls, named as in
+// syscalls(2), such as "openat" or "mkdirat"
+type SeccompRule struct {
+	Syscalls []string      `json:"syscalls"`
+	Action   SeccompAction `json:"action"`
+}
+
+// SeccompProfile is a seccomp filter applying the action of the first
+// rule naming a syscall, or the default action if no rule names it
+type SeccompProfile struct {
+	DefaultAction SeccompAction `json:"default_action"`
+	Rules         []SeccompRule `json:"rules"`
+}
+
+// WithSeccompProfile restricts the syscalls the process may make to
+// those allowed by the profile.  The filter is installed by re-executing
+// the current program as a shim before it execs the target, so the
+// profile must allow the syscalls of execve itself.  Only linux on amd64
+// and arm64 is supported; the filter is applied by the init function of
+// this package, so any init functions of packages it does not depend
+// upon are not run in the shim
+func WithSeccompProfile(p *SeccompProfile) Option {
+	return func(o *options) error {
+		if p == nil {
+			return errMissingProfile
+		}
+		if err := p.validate(); err != nil {
+			return err
+		}
+		o.seccomp = p
+		return nil
+	}
+}
+
+// validate checks that the profile can be installed on this platform
+func (p *SeccompProfile) validate() error {
+	if len(syscallNumbers) == 0 {
+		return errSeccompUnsupported
+	}
+	for _, r := range p.Rules {
+		for _, name := range r.Syscalls {
+			if _, ok := syscallNumbers[name]; !ok {
+				return fmt.Errorf("%w: %q", errUnknownSyscall, name)
+			}
+		}
+	}
+	return nil
+}
@@ -0,0 +1,87 @@
+package launcher
+
+import (
+	"errors"
+	"runtime"
+	"unsafe"
+
+	"golang.org/x/sys/unix"
+)
+
+var errSeccompUnsupported = errors.New("seccomp is not supported on this architecture")
+
+// Offsets within struct seccomp_data
+const (
+	seccompDataNr   = 0
+	seccompDataArch = 4
+)
+
+// x32SyscallBit is set in the numbers of syscalls made through the x32
+// ABI, which shares the x86_64 audit architecture and so is not caught
+// by the architecture check
+const x32SyscallBit = 0x40000000
+
+// program compiles the profile into a classic BPF program, which kills
+// the process if a syscall is made for another architecture or, on
+// amd64, through the x32 ABI
+func (p *SeccompProfile) program() []unix.SockFilter {
+	stmt := func(code uint16, k uint32) unix.SockFilter {
+		return unix.SockFilter{Code: code, K: k}
+	}
+	jump := func(code uint16, k uint32, jt, jf uint8) unix.SockFilter {
+		return unix.SockFilter{Code: code, Jt: jt, Jf: jf, K: k}
+	}
+
+	prog := []unix.SockFilter{
+		stmt(unix.BPF_LD|unix.BPF_W|unix.BPF_ABS, seccompDataArch),
+		jump(unix.BPF_JMP|unix.BPF_JEQ|unix.BPF_K, auditArch, 1, 0),
+		stmt(unix.BPF_RET|unix.BPF_K, uint32(SeccompKillProcess)),
+		stmt(unix.BPF_LD|unix.BPF_W|unix.BPF_ABS, seccompDataNr),
+	}
+	if runtime.GOARCH == "amd64" {
+		prog = append(prog,
+			jump(unix.BPF_JMP|unix.BPF_JGE|unix.BPF_K, x32SyscallBit, 0, 1),
+			stmt(unix.BPF_RET|unix.BPF_K, uint32(SeccompKillProcess)),
+		)
+	}
+
+	seen := map[uint32]bool{}
+	for _, r := range p.Rules {
+		for _, name := range r.Syscalls {
+			nr := syscallNumbers[name]
+			if seen[nr] {
+				continue
+			}
+			seen[nr] = true
+			prog = append(prog,
+				jump(unix.BPF_JMP|unix.BPF_JEQ|unix.BPF_K, nr, 0, 1),
+				stmt(unix.BPF_RET|unix.BPF_K, uint32(r.Action)),
+			)
+		}
+	}
+
+	return append(prog, stmt(unix.BPF_RET|unix.BPF_K, uint32(p.DefaultAction)))
+}
+
+// installSeccomp installs the profile as a filter on all threads of
+// the calling process, which must not be able to gain privileges
+func installSeccomp(p *SeccompProfile) error {
+	if err := p.validate(); err != nil {
+		return err
+	}
+	if err := unix.Prctl(unix.PR_SET_NO_NEW_PRIVS, 1, 0, 0, 0); err != nil {
+		return err
+	}
+
+	prog := p.program()
+	fprog := unix.SockFprog{
+		Len:    uint16(len(prog)),
+		Filter: &prog[0],
+	}
+	_, _, errno := unix.Syscall(unix.SYS_SECCOMP, unix.SECCOMP_SET_MODE_FILTER,
+		unix.SECCOMP_FILTER_FLAG_TSYNC, uintptr(unsafe.Pointer(&fprog)))
+	if errno != 0 {
+		return errno
+	}
+	return nil
+}
@@ -0,0 +1,124 @@
+package launcher
+
+import (
+	"context"
+	"errors"
+	"io"
+	"os"
+	"path/filepath"
+	"runtime"
+	"strings"
+	"syscall"
+	"testing"
+
+	"golang.org/x/sys/unix"
+)
+
+func TestSeccompProfileDenies(t *testing.T) {
+
+	dir := filepath.Join(t.TempDir(), "denied")
+
+	profile := &SeccompProfile{
+		DefaultAction: SeccompAllow,
+		Rules: []SeccompRule{
+			{Syscalls: []string{"mkdirat"}, Action: SeccompErrno(syscall.EPERM)},
+		},
+	}
+	if _, ok := syscallNumbers["mkdir"]; ok {
+		profile.Rules[0].Syscalls = append(profile.Rules[0].Syscalls, "mkdir")
+	}
+
+	l, err := NewWithOptions(context.Background(), "mkdir", nil, []string{dir}, WithSeccompProfile(profile))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer l.Close()
+
+	if err := l.Run(); err == nil {
+		t.Fatal("expected mkdir to fail")
+	}
+	if _, err := os.Stat(dir); !errors.Is(err, os.ErrNotExist) {
+		t.Fatalf("expected directory not to be created, got %v\n", err)
+	}
+
+	for _, kv := range l.GetEnv() {
+		if strings.HasPrefix(kv, shimEnv) {
+			t.Fatalf("expected shim variable to be hidden, got %v\n", l.GetEnv())
+		}
+	}
+}
+
+func TestSeccompProfileAllows(t *testing.T) {
+
+	profile := &SeccompProfile{
+		DefaultAction: SeccompAllow,
+		Rules: []SeccompRule{
+			{Syscalls: []string{"mkdirat"}, Action: SeccompErrno(syscall.EPERM)},
+		},
+	}
+
+	l, err := NewWithOptions(context.Background(), "sh", []string{"XYZ=ABC"}, []string{"-c", "echo $0 $XYZ; env"}, WithSeccompProfile(profile))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer l.Close()
+
+	if err := l.Run(); err != nil {
+		t.Fatal(err)
+	}
+	b, err := io.ReadAll(l.StdOutReader())
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	out := string(b)
+	if !strings.HasPrefix(out, l.GetPath()+" ABC\n") {
+		t.Fatalf("unexpected output %q\n", out)
+	}
+	if strings.Contains(out, shimEnv) {
+		t.Fatalf("expected shim variable to be removed, got %q\n", out)
+	}
+}
+
+func TestSeccompProfileUnknownSyscall(t *testing.T) {
+
+	profile := &SeccompProfile{
+		DefaultAction: SeccompAllow,
+		Rules:         []SeccompRule{{Syscalls: []string{"zzzUnknownzzz"}, Action: SeccompKillProcess}},
+	}
+
+	_, err := NewWithOptions(context.Background(), "echo", nil, nil, WithSeccompProfile(profile))
+	if !errors.Is(err, errUnknownSyscall) {
+		t.Fatal(err)
+	}
+}
+
+func TestSeccompProgramDeniesX32(t *testing.T) {
+
+	if runtime.GOARCH != "amd64" {
+		t.Skip("the x32 ABI exists only on amd64")
+	}
+
+	profile := &SeccompProfile{DefaultAction: SeccompAllow}
+	prog := profile.program()
+
+	// The check must immediately follow the load of the syscall number,
+	// ahead of any rule which could allow it
+	for i, f := range prog {
+		if f.Code != unix.BPF_LD|unix.BPF_W|unix.BPF_ABS || f.K != seccompDataNr {
+			continue
+		}
+		if i+2 >= len(prog) {
+			break
+		}
+		check, ret := prog[i+1], prog[i+2]
+		if check.Code != unix.BPF_JMP|unix.BPF_JGE|unix.BPF_K || check.K != x32SyscallBit || check.Jt != 0 || check.Jf != 1 {
+			t.Fatalf("expected x32 check after loading the syscall number, got %+v\n", check)
+		}
+		if ret.Code != unix.BPF_RET|unix.BPF_K || ret.K != uint32(SeccompKillProcess) {
+			t.Fatalf("expected x32 syscalls to kill the process, got %+v\n", ret)
+		}
+		return
+	}
+	t.Fatalf("expected the program to load the syscall number, got %+v\n", prog)
+}
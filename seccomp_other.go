@@ -0,0 +1,10 @@
+//go:build !linux
+
+package launcher
+
+import "errors"
+
+var errSeccompUnsupported = errors.New("seccomp is only supported on linux")
+
+// syscallNumbers is empty, as seccomp profiles are not supported
+var syscallNumbers map[string]uint32
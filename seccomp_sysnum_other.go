@@ -0,0 +1,9 @@
+//go:build linux && !amd64 && !arm64
+
+package launcher
+
+// auditArch is unknown, as seccomp profiles are not supported
+const auditArch = 0
+
+// syscallNumbers is empty, as seccomp profiles are not supported
+var syscallNumbers map[string]uint32
@@ -0,0 +1,62 @@
+package launcher
+
+import (
+	"errors"
+	"strings"
+)
+
+var errInvalidIndex = errors.New("index must not be negative")
+
+// WithSecretEnv marks the named environment variables as secret, so
+// that their values are masked by GetEnv, String and LogValue, whilst
+// the process still receives the real values
+func WithSecretEnv(keys ...string) Option {
+	return func(o *options) error {
+		if o.secretEnv == nil {
+			o.secretEnv = map[string]bool{}
+		}
+		for _, k := range keys {
+			o.secretEnv[k] = true
+		}
+		return nil
+	}
+}
+
+// WithSecretArgIndexes marks the arguments at the indexes, counted from
+// zero and excluding the command itself, as secret, so that they are
+// masked by GetArgs, String and LogValue, whilst the process still
+// receives the real values
+func WithSecretArgIndexes(indexes ...int) Option {
+	return func(o *options) error {
+		if o.secretArgs == nil {
+			o.secretArgs = map[int]bool{}
+		}
+		for _, i := range indexes {
+			if i < 0 {
+				return errInvalidIndex
+			}
+			o.secretArgs[i] = true
+		}
+		return nil
+	}
+}
+
+// maskArgs replaces the secret arguments in place
+func (o *options) maskArgs(args []string) []string {
+	for i := range args {
+		if o.secretArgs[i] {
+			args[i] = redactedValue
+		}
+	}
+	return args
+}
+
+// maskEnv replaces the values of secret environment variables in place
+func (o *options) maskEnv(env []string) []string {
+	for i, kv := range env {
+		if k, _, ok := strings.Cut(kv, "="); ok && o.secretEnv[k] {
+			env[i] = k + "=" + redactedValue
+		}
+	}
+	return env
+}
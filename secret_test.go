@@ -0,0 +1,50 @@
+package launcher
+
+import (
+	"context"
+	"strings"
+	"testing"
+)
+
+func TestSecretMasking(t *testing.T) {
+
+	l, err := NewWithOptions(context.Background(), "sh", []string{"USER=bob", "TOKEN=abc123"},
+		[]string{"-c", "echo $TOKEN $0", "hunter2"},
+		WithSecretEnv("TOKEN"), WithSecretArgIndexes(2))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer l.Close()
+
+	env := l.GetEnv()
+	if env[0] != "USER=bob" || env[1] != "TOKEN=***" {
+		t.Fatalf("expected masked env, got %v\n", env)
+	}
+	args := l.GetArgs()
+	if args[1] != "echo $TOKEN $0" || args[2] != "***" {
+		t.Fatalf("expected masked args, got %v\n", args)
+	}
+	if s := l.String(); strings.Contains(s, "hunter2") {
+		t.Fatalf("expected masked String, got %q\n", s)
+	}
+
+	// The process receives the real values
+	var out strings.Builder
+	if err := l.Start(); err != nil {
+		t.Fatal(err)
+	}
+	l.copyOutput(&out, nil)
+	if err := l.Wait(); err != nil {
+		t.Fatal(err)
+	}
+	if strings.TrimSpace(out.String()) != "abc123 hunter2" {
+		t.Fatalf("expected real values, got %q\n", out.String())
+	}
+}
+
+func TestSecretArgIndexInvalid(t *testing.T) {
+
+	if _, err := NewWithOptions(context.Background(), "echo", nil, nil, WithSecretArgIndexes(-1)); err != errInvalidIndex {
+		t.Fatalf("expected errInvalidIndex, got %v\n", err)
+	}
+}
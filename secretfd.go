@@ -0,0 +1,60 @@
+package launcher
+
+import (
+	"errors"
+	"fmt"
+	"os"
+)
+
+var errMissingName = errors.New("name must be provided")
+
+// secretFD is secret material delivered to the process over a pipe
+type secretFD struct {
+	name string
+	data []byte
+}
+
+// WithSecretFD delivers the data to the process over a pipe inherited
+// as an extra file descriptor, whose number is set in the environment
+// variable NAME_FD, so that the secret is visible in neither the
+// arguments nor the environment of the process.  The process should
+// read the descriptor to EOF.  Extra descriptors are not supported on
+// Windows
+func WithSecretFD(name string, data []byte) Option {
+	return func(o *options) error {
+		if name == "" {
+			return errMissingName
+		}
+		o.secretFDs = append(o.secretFDs, secretFD{name: name, data: append([]byte{}, data...)})
+		return nil
+	}
+}
+
+// secretPipes creates a pipe for each secret, passing the
+// read ends to the process as extra files
+func (l *Launcher) secretPipes() error {
+	for _, s := range l.opts.secretFDs {
+		pr, pw, err := os.Pipe()
+		if err != nil {
+			return err
+		}
+		l.childFiles = append(l.childFiles, pr)
+		l.secretWriters = append(l.secretWriters, pw)
+
+		l.cmd.ExtraFiles = append(l.cmd.ExtraFiles, pr)
+		fd := 2 + len(l.cmd.ExtraFiles)
+		l.cmd.Env = append(l.cmd.Env, fmt.Sprintf("%s_FD=%d", s.name, fd))
+	}
+	return nil
+}
+
+// writeSecrets writes each secret to its pipe, in the background as the
+// process may not read a secret until it has read those preceding it
+func (l *Launcher) writeSecrets() {
+	for i, pw := range l.secretWriters {
+		go func(pw *os.File, data []byte) {
+			pw.Write(data)
+			pw.Close()
+		}(pw, l.opts.secretFDs[i].data)
+	}
+}
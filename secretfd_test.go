@@ -0,0 +1,55 @@
+package launcher
+
+import (
+	"context"
+	"strings"
+	"testing"
+)
+
+func TestSecretFD(t *testing.T) {
+
+	big := strings.Repeat("x", 100000)
+
+	l, err := NewWithOptions(context.Background(), "sh", nil,
+		[]string{"-c", `cat <&$DB_PASSWORD_FD; echo; wc -c <&$BIG_FD`},
+		WithSecretFD("DB_PASSWORD", []byte("hunter2")), WithSecretFD("BIG", []byte(big)))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer l.Close()
+
+	for _, kv := range l.GetEnv() {
+		if strings.Contains(kv, "hunter2") {
+			t.Fatalf("secret visible in environment %q\n", kv)
+		}
+	}
+
+	var out strings.Builder
+	if err := l.Start(); err != nil {
+		t.Fatal(err)
+	}
+	l.copyOutput(&out, nil)
+	if err := l.Wait(); err != nil {
+		t.Fatal(err)
+	}
+
+	lines := strings.Fields(out.String())
+	if len(lines) != 2 || lines[0] != "hunter2" || lines[1] != "100000" {
+		t.Fatalf("unexpected output %q\n", out.String())
+	}
+}
+
+func TestSecretFDUnstarted(t *testing.T) {
+
+	l, err := NewWithOptions(context.Background(), "cat", nil, nil, WithSecretFD("S", []byte("x")))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := l.Close(); err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err := NewWithOptions(context.Background(), "cat", nil, nil, WithSecretFD("", nil)); err != errMissingName {
+		t.Fatalf("expected errMissingName, got %v\n", err)
+	}
+}
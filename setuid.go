@@ -0,0 +1,23 @@
+package launcher
+
+import (
+	"fmt"
+	"os"
+)
+
+// WithRejectSetuid refuses to launch an executable which has its setuid
+// or setgid bit set, both when the Launcher is created and when its
+// process is started, so that a command resolved from PATH cannot
+// escalate privileges.  A refusal is returned as a *PolicyError
+func WithRejectSetuid() Option {
+	return WithPolicy(PolicyFunc(func(req PolicyRequest) error {
+		fi, err := os.Stat(req.Path)
+		if err != nil {
+			return err
+		}
+		if fi.Mode()&(os.ModeSetuid|os.ModeSetgid) != 0 {
+			return fmt.Errorf("%s has the setuid or setgid bit set", req.Path)
+		}
+		return nil
+	}))
+}
@@ -0,0 +1,39 @@
+package launcher
+
+import (
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestRejectSetuid(t *testing.T) {
+
+	script := filepath.Join(t.TempDir(), "hello")
+	if err := os.WriteFile(script, []byte("#!/bin/sh\necho hello\n"), 0o755); err != nil {
+		t.Fatal(err)
+	}
+
+	l, err := NewWithOptions(context.Background(), script, nil, nil, WithRejectSetuid())
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer l.Close()
+
+	for _, mode := range []os.FileMode{os.ModeSetuid, os.ModeSetgid} {
+		if err := os.Chmod(script, 0o755|mode); err != nil {
+			t.Fatal(err)
+		}
+		if fi, err := os.Stat(script); err != nil || fi.Mode()&mode == 0 {
+			t.Skip("setuid and setgid bits are not supported")
+		}
+
+		if _, err := NewWithOptions(context.Background(), script, nil, nil, WithRejectSetuid()); !errors.Is(err, ErrPolicyDenied) {
+			t.Fatalf("expected %v to be rejected by New, got %v\n", mode, err)
+		}
+		if err := l.Start(); !errors.Is(err, ErrPolicyDenied) {
+			t.Fatalf("expected %v to be rejected by Start, got %v\n", mode, err)
+		}
+	}
+}
@@ -0,0 +1,273 @@
+package launcher
+
+import (
+	"bufio"
+	"context"
+	"errors"
+	"fmt"
+	"hash/fnv"
+	"io"
+	"sync"
+)
+
+var errInvalidWorkers = errors.New("number of workers must be positive")
+var errUnexpectedOutput = errors.New("worker wrote more lines than records received")
+var errMissingOutput = errors.New("worker wrote fewer lines than records received")
+
+// defaultMaxShardLine is the longest line of output from a worker
+// accepted by a ShardedRunner which does not set MaxLineSize
+const defaultMaxShardLine = 1024 * 1024
+
+// MergeMode determines how a ShardedRunner combines the output of its workers
+type MergeMode int
+
+const (
+	// MergeTagged writes each line of output as it arrives, prefixed by
+	// the index of the worker that produced it and a tab.  Workers may
+	// write any number of lines for each record, as for filters
+	MergeTagged MergeMode = iota
+	// MergeOrdered writes output lines in the order of the records that
+	// produced them, which requires each worker to write exactly one
+	// line of output for each record it receives, failing the run if
+	// it writes more or fewer
+	MergeOrdered
+)
+
+// ShardFunc returns the index, in the range [0, n), of the
+// worker which should process the record
+type ShardFunc func(record []byte, n int) int
+
+// HashShard is a ShardFunc which sends identical records to the same worker
+func HashShard(record []byte, n int) int {
+	h := fnv.New32a()
+	h.Write(record)
+	return int(h.Sum32() % uint32(n))
+}
+
+// ShardedRunner splits an input stream into records, distributing them
+// across a set of worker processes, and merges the workers' output.
+// Records are written to the workers one per line
+type ShardedRunner struct {
+	// Spec defines the worker processes
+	Spec Spec
+	// Workers is the number of worker processes
+	Workers int
+	// Split divides the input into records, defaulting to bufio.ScanLines
+	Split bufio.SplitFunc
+	// Shard chooses the worker for each record, defaulting to round-robin
+	Shard ShardFunc
+	// Merge determines how the worker output is combined
+	Merge MergeMode
+	// Stderr receives the stderr of the workers, which is otherwise discarded
+	Stderr io.Writer
+	// MaxLineSize limits the length of a line of output from a worker,
+	// defaulting to 1MiB, beyond which the run fails
+	MaxLineSize int
+}
+
+// shardLine is a line of output from a worker
+type shardLine struct {
+	worker int
+	text   []byte
+}
+
+// Run processes the input, writing the merged output of the workers to
+// out, and returns once all workers have exited.  If any worker fails,
+// or its output cannot be read, the remaining workers are cancelled
+func (r *ShardedRunner) Run(ctx context.Context, in io.Reader, out io.Writer) error {
+	if ctx == nil {
+		return ErrMissingContext
+	}
+	if r.Workers <= 0 {
+		return errInvalidWorkers
+	}
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	workers := make([]*Launcher, r.Workers)
+	for i := range workers {
+		l, err := r.Spec.New(ctx)
+		if err == nil {
+			if err = l.Start(); err != nil {
+				l.Close()
+			}
+		}
+		if err != nil {
+			for _, w := range workers[:i] {
+				w.Close()
+			}
+			return err
+		}
+		defer l.Close()
+		workers[i] = l
+	}
+
+	// pending holds, for each worker, the sequence numbers of the
+	// records it has received but not yet answered, which are only
+	// recorded when output is merged in order
+	var mu sync.Mutex
+	pending := make([][]int, r.Workers)
+
+	maxLine := r.MaxLineSize
+	if maxLine <= 0 {
+		maxLine = defaultMaxShardLine
+	}
+
+	lines := make(chan shardLine)
+	readErrs := make(chan error, r.Workers)
+	var readers sync.WaitGroup
+	for i, l := range workers {
+		readers.Add(2)
+		go func(i int, l *Launcher) {
+			defer readers.Done()
+			scanner := bufio.NewScanner(l.StdOutReader())
+			scanner.Buffer(make([]byte, min(maxLine, 64*1024)), maxLine)
+			for scanner.Scan() {
+				lines <- shardLine{worker: i, text: append([]byte{}, scanner.Bytes()...)}
+			}
+			if err := scanner.Err(); err != nil {
+				readErrs <- fmt.Errorf("worker %d: %w", i, err)
+				cancel()
+			}
+		}(i, l)
+		go func(l *Launcher) {
+			defer readers.Done()
+			w := r.Stderr
+			if w == nil {
+				w = io.Discard
+			}
+			io.Copy(w, l.StdErrReader())
+		}(l)
+	}
+	go func() {
+		readers.Wait()
+		close(lines)
+	}()
+
+	feedErr := make(chan error, 1)
+	go func() {
+		feedErr <- r.feed(in, workers, func(worker, seq int) {
+			if r.Merge != MergeOrdered {
+				return
+			}
+			mu.Lock()
+			pending[worker] = append(pending[worker], seq)
+			mu.Unlock()
+		})
+		for _, l := range workers {
+			l.cmdWriter.Close()
+		}
+	}()
+
+	mergeErr := r.merge(lines, out, func(worker int) (int, bool) {
+		mu.Lock()
+		defer mu.Unlock()
+		if len(pending[worker]) == 0 {
+			return 0, false
+		}
+		seq := pending[worker][0]
+		pending[worker] = pending[worker][1:]
+		return seq, true
+	})
+	if mergeErr != nil {
+		cancel()
+		for range lines {
+		}
+	}
+
+	// The readers have finished once the lines are merged
+	close(readErrs)
+	var readErr error
+	for err := range readErrs {
+		readErr = errors.Join(readErr, err)
+	}
+
+	errs := []error{<-feedErr, mergeErr, readErr}
+
+	// Records remaining unanswered once all output is merged were skipped
+	if mergeErr == nil && readErr == nil {
+		mu.Lock()
+		for i, p := range pending {
+			if len(p) > 0 {
+				errs = append(errs, fmt.Errorf("worker %d: %w", i, errMissingOutput))
+			}
+		}
+		mu.Unlock()
+	}
+	for i, l := range workers {
+		if err := l.Wait(); err != nil && mergeErr == nil && readErr == nil {
+			errs = append(errs, fmt.Errorf("worker %d: %w", i, err))
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// feed splits the input into records, writing each to its worker
+// and recording the sequence number of the record against the worker
+func (r *ShardedRunner) feed(in io.Reader, workers []*Launcher, record func(worker, seq int)) error {
+	split := r.Split
+	if split == nil {
+		split = bufio.ScanLines
+	}
+
+	scanner := bufio.NewScanner(in)
+	scanner.Split(split)
+
+	for seq := 0; scanner.Scan(); seq++ {
+		b := scanner.Bytes()
+
+		worker := seq % len(workers)
+		if r.Shard != nil {
+			worker = r.Shard(b, len(workers))
+		}
+
+		rec := make([]byte, len(b)+1)
+		copy(rec, b)
+		rec[len(b)] = '\n'
+
+		record(worker, seq)
+		if err := workers[worker].SendStdIn(rec); err != nil {
+			return fmt.Errorf("worker %d: %w", worker, err)
+		}
+	}
+	return scanner.Err()
+}
+
+// merge writes the lines to out as required by the MergeMode, using
+// next to find the sequence number of the record answered by a line
+func (r *ShardedRunner) merge(lines <-chan shardLine, out io.Writer, next func(worker int) (int, bool)) error {
+	if r.Merge != MergeOrdered {
+		for line := range lines {
+			if _, err := fmt.Fprintf(out, "%d\t%s\n", line.worker, line.text); err != nil {
+				return err
+			}
+		}
+		return nil
+	}
+
+	results := map[int][]byte{}
+	written := 0
+	for line := range lines {
+		seq, ok := next(line.worker)
+		if !ok {
+			return fmt.Errorf("worker %d: %w", line.worker, errUnexpectedOutput)
+		}
+		results[seq] = line.text
+
+		for {
+			text, ok := results[written]
+			if !ok {
+				break
+			}
+			delete(results, written)
+			written++
+			if _, err := fmt.Fprintf(out, "%s\n", text); err != nil {
+				return err
+			}
+		}
+	}
+	if len(results) > 0 {
+		return fmt.Errorf("record %d: %w", written, errMissingOutput)
+	}
+	return nil
+}
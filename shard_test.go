@@ -0,0 +1,120 @@
+package launcher
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"testing"
+)
+
+func shardInput(n int) string {
+	var b strings.Builder
+	for i := 0; i < n; i++ {
+		fmt.Fprintf(&b, "record %d\n", i)
+	}
+	return b.String()
+}
+
+func TestShardedRunnerOrdered(t *testing.T) {
+
+	r := &ShardedRunner{
+		Spec:    Spec{File: "cat"},
+		Workers: 3,
+		Merge:   MergeOrdered,
+	}
+
+	input := shardInput(500)
+
+	var out bytes.Buffer
+	if err := r.Run(context.Background(), strings.NewReader(input), &out); err != nil {
+		t.Fatal(err)
+	}
+
+	if out.String() != input {
+		t.Fatalf("output not in input order: %q\n", out.String())
+	}
+}
+
+func TestShardedRunnerTaggedHash(t *testing.T) {
+
+	r := &ShardedRunner{
+		Spec:    Spec{File: "cat"},
+		Workers: 4,
+		Shard:   HashShard,
+	}
+
+	input := shardInput(50) + shardInput(50)
+
+	var out bytes.Buffer
+	if err := r.Run(context.Background(), strings.NewReader(input), &out); err != nil {
+		t.Fatal(err)
+	}
+
+	workers := map[string]string{}
+	lines := strings.Split(strings.TrimSuffix(out.String(), "\n"), "\n")
+	if len(lines) != 100 {
+		t.Fatalf("expected 100 lines, got %v\n", len(lines))
+	}
+	for _, line := range lines {
+		worker, record, ok := strings.Cut(line, "\t")
+		if !ok {
+			t.Fatalf("untagged line %q\n", line)
+		}
+		if w, ok := workers[record]; ok && w != worker {
+			t.Fatalf("%q processed by workers %v and %v\n", record, w, worker)
+		}
+		workers[record] = worker
+	}
+}
+
+func TestShardedRunnerWorkerFailure(t *testing.T) {
+
+	r := &ShardedRunner{
+		Spec:    Spec{File: "sh", Args: []string{"-c", "cat; exit 4"}},
+		Workers: 2,
+	}
+
+	if err := r.Run(context.Background(), strings.NewReader(shardInput(10)), &bytes.Buffer{}); err == nil {
+		t.Fatal("expected error from failing workers")
+	}
+
+	r.Workers = 0
+	if err := r.Run(context.Background(), strings.NewReader(""), &bytes.Buffer{}); err != errInvalidWorkers {
+		t.Fatal(err)
+	}
+}
+
+func TestShardedRunnerLongLine(t *testing.T) {
+
+	// The worker answers with a line longer than allowed
+	r := &ShardedRunner{
+		Spec:        Spec{File: "sh", Args: []string{"-c", "head -c 2000 /dev/zero | tr '\\0' a; echo; cat >/dev/null"}},
+		Workers:     2,
+		MaxLineSize: 1000,
+	}
+
+	var out bytes.Buffer
+	err := r.Run(context.Background(), strings.NewReader(shardInput(10)), &out)
+	if !errors.Is(err, bufio.ErrTooLong) {
+		t.Fatalf("expected %v, got %v\n", bufio.ErrTooLong, err)
+	}
+}
+
+func TestShardedRunnerMissingOutput(t *testing.T) {
+
+	// The worker answers every record except the third it receives
+	r := &ShardedRunner{
+		Spec:    Spec{File: "sh", Args: []string{"-c", "i=0; while read -r line; do i=$((i+1)); [ $i -eq 3 ] || echo \"$line\"; done"}},
+		Workers: 2,
+		Merge:   MergeOrdered,
+	}
+
+	var out bytes.Buffer
+	err := r.Run(context.Background(), strings.NewReader(shardInput(10)), &out)
+	if !errors.Is(err, errMissingOutput) {
+		t.Fatalf("expected %v, got %v\n", errMissingOutput, err)
+	}
+}
@@ -0,0 +1,119 @@
+package launcher
+
+import (
+	"context"
+	"runtime"
+	"strings"
+)
+
+// NewShell creates a new instance of Launcher which runs the command
+// line using the platform shell, as sh -c on unix and cmd /C on Windows.
+// Arguments interpolated into the command line should be quoted by Quote.
+// As with New, the environment of the process is empty unless configured
+// by Options
+func NewShell(ctx context.Context, cmdline string, opts ...Option) (*Launcher, error) {
+	file, args := shellCommand(cmdline)
+	l, err := NewWithOptions(ctx, file, nil, args, opts...)
+	if err != nil {
+		return nil, err
+	}
+	l.shellLine = cmdline
+	return l, nil
+}
+
+// Quote returns the arguments quoted for the platform shell and joined
+// by spaces, so that each is passed as a single literal argument.  On
+// Windows the arguments are quoted for cmd, as used by NewShell, with
+// its metacharacters escaped, and so must not be passed to a program
+// other than through cmd
+func Quote(args ...string) string {
+	quote := quotePOSIX
+	if runtime.GOOS == "windows" {
+		quote = quoteCmd
+	}
+
+	quoted := make([]string, len(args))
+	for i, a := range args {
+		quoted[i] = quote(a)
+	}
+	return strings.Join(quoted, " ")
+}
+
+// quotePOSIX quotes the argument for a POSIX shell, using single
+// quotes unless it consists only of characters which need none
+func quotePOSIX(s string) string {
+	if s == "" {
+		return "''"
+	}
+	safe := true
+	for _, r := range s {
+		if !isShellSafe(r) {
+			safe = false
+			break
+		}
+	}
+	if safe {
+		return s
+	}
+	return "'" + strings.ReplaceAll(s, "'", `'\''`) + "'"
+}
+
+// isShellSafe returns true if the rune has no special meaning to a shell
+func isShellSafe(r rune) bool {
+	switch {
+	case r >= 'a' && r <= 'z', r >= 'A' && r <= 'Z', r >= '0' && r <= '9':
+		return true
+	}
+	return strings.ContainsRune("_@%+=:,./-", r)
+}
+
+// cmdMetachars are the characters with special meaning to cmd
+const cmdMetachars = `()%!^"<>&|`
+
+// quoteCmd quotes the argument as parsed by CommandLineToArgvW, and then
+// escapes each cmd metacharacter, including the quotes, with a caret, so
+// that cmd passes the quoted argument on unchanged rather than treating
+// it as an operator, redirection or variable reference
+func quoteCmd(s string) string {
+	q := quoteWindows(s)
+
+	var b strings.Builder
+	for i := 0; i < len(q); i++ {
+		if strings.IndexByte(cmdMetachars, q[i]) >= 0 {
+			b.WriteByte('^')
+		}
+		b.WriteByte(q[i])
+	}
+	return b.String()
+}
+
+// quoteWindows quotes the argument as parsed by CommandLineToArgvW, in
+// which backslashes are literal unless they precede a double quote
+func quoteWindows(s string) string {
+	if s == "" {
+		return `""`
+	}
+	if !strings.ContainsAny(s, " \t\n\v\"") {
+		return s
+	}
+
+	var b strings.Builder
+	b.WriteByte('"')
+	slashes := 0
+	for i := 0; i < len(s); i++ {
+		c := s[i]
+		if c == '\\' {
+			slashes++
+			continue
+		}
+		if c == '"' {
+			slashes = 2*slashes + 1
+		}
+		b.WriteString(strings.Repeat(`\`, slashes))
+		b.WriteByte(c)
+		slashes = 0
+	}
+	b.WriteString(strings.Repeat(`\`, 2*slashes))
+	b.WriteByte('"')
+	return b.String()
+}
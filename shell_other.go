@@ -0,0 +1,10 @@
+//go:build !windows
+
+package launcher
+
+// shellCommand returns the command running the command line with sh
+func shellCommand(cmdline string) (string, []string) {
+	return "sh", []string{"-c", cmdline}
+}
+
+func (l *Launcher) setShellCommandLine() {}
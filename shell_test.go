@@ -0,0 +1,95 @@
+package launcher
+
+import (
+	"context"
+	"io"
+	"os/exec"
+	"runtime"
+	"strings"
+	"testing"
+)
+
+func TestQuotePOSIX(t *testing.T) {
+
+	tests := map[string]string{
+		"":              "''",
+		"foo":           "foo",
+		"a/b-c.d":       "a/b-c.d",
+		"foo bar":       "'foo bar'",
+		"it's":          `'it'\''s'`,
+		"$HOME; rm -rf": "'$HOME; rm -rf'",
+	}
+	for in, expected := range tests {
+		if got := quotePOSIX(in); got != expected {
+			t.Fatalf("quoting %q: expected %s, got %s\n", in, expected, got)
+		}
+	}
+}
+
+func TestQuoteWindows(t *testing.T) {
+
+	tests := map[string]string{
+		"":            `""`,
+		`C:\foo`:      `C:\foo`,
+		"foo bar":     `"foo bar"`,
+		`say "hi"`:    `"say \"hi\""`,
+		`C:\my dir\`:  `"C:\my dir\\"`,
+		`a\\"b c`:     `"a\\\\\"b c"`,
+		`back\slash `: `"back\slash "`,
+	}
+	for in, expected := range tests {
+		if got := quoteWindows(in); got != expected {
+			t.Fatalf("quoting %q: expected %s, got %s\n", in, expected, got)
+		}
+	}
+}
+
+func TestQuoteCmd(t *testing.T) {
+
+	tests := map[string]string{
+		"":          `^"^"`,
+		`C:\foo`:    `C:\foo`,
+		"foo bar":   `^"foo bar^"`,
+		"a&calc":    `a^&calc`,
+		"a|b<c>d":   `a^|b^<c^>d`,
+		"%PATH%":    `^%PATH^%`,
+		"(x)^!":     `^(x^)^^^!`,
+		`say "a&b"`: `^"say \^"a^&b\^"^"`,
+	}
+	for in, expected := range tests {
+		if got := quoteCmd(in); got != expected {
+			t.Fatalf("quoting %q: expected %s, got %s\n", in, expected, got)
+		}
+	}
+}
+
+func TestNewShellQuoted(t *testing.T) {
+
+	if runtime.GOOS == "windows" {
+		t.Skip("test uses POSIX utilities")
+	}
+	if _, err := exec.LookPath("printf"); err != nil {
+		t.Skip("printf is not installed")
+	}
+
+	args := []string{"foo bar", "it's", "$HOME", "; echo injected", ""}
+
+	l, err := NewShell(context.Background(), "printf '[%s]\\n' "+Quote(args...))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer l.Close()
+
+	if err := l.Run(); err != nil {
+		t.Fatal(err)
+	}
+	b, err := io.ReadAll(l.StdOutReader())
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	expected := "[" + strings.Join(args, "]\n[") + "]\n"
+	if string(b) != expected {
+		t.Fatalf("invalid response - expected %q, got %q\n", expected, string(b))
+	}
+}
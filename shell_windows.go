@@ -0,0 +1,29 @@
+package launcher
+
+import (
+	"os"
+	"syscall"
+)
+
+// shellCommand returns the command running the command line with cmd
+func shellCommand(cmdline string) (string, []string) {
+	shell := os.Getenv("ComSpec")
+	if shell == "" {
+		shell = "cmd.exe"
+	}
+	return shell, []string{"/S", "/C", cmdline}
+}
+
+// setShellCommandLine passes the command line of a Launcher created by
+// NewShell to cmd verbatim, as cmd does not parse its arguments as other
+// programs do.  It is set once the shell has been resolved, which may
+// not be until the process is started
+func (l *Launcher) setShellCommandLine() {
+	if l.shellLine == "" {
+		return
+	}
+	if l.cmd.SysProcAttr == nil {
+		l.cmd.SysProcAttr = &syscall.SysProcAttr{}
+	}
+	l.cmd.SysProcAttr.CmdLine = quoteWindows(l.path) + ` /S /C "` + l.shellLine + `"`
+}
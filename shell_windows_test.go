@@ -0,0 +1,61 @@
+package launcher
+
+import (
+	"context"
+	"io"
+	"strings"
+	"testing"
+)
+
+func TestNewShellQuotedCmd(t *testing.T) {
+
+	tests := map[string]string{
+		"a&echo injected":  `"a&echo injected"`,
+		"a|findstr x":      `"a|findstr x"`,
+		"%PATH%":           `%PATH%`,
+		"(x)>out.txt":      `(x)>out.txt`,
+		`say "hi" & exit`:  `"say \"hi\" & exit"`,
+		"a^b":              `a^b`,
+		"no-metacharacter": `no-metacharacter`,
+	}
+	for arg, expected := range tests {
+		l, err := NewShell(context.Background(), "echo "+Quote(arg), WithInheritedEnv())
+		if err != nil {
+			t.Fatal(err)
+		}
+		if err := l.Run(); err != nil {
+			t.Fatal(err)
+		}
+		b, err := io.ReadAll(l.StdOutReader())
+		l.Close()
+		if err != nil {
+			t.Fatal(err)
+		}
+		if got := strings.TrimSpace(string(b)); got != expected {
+			t.Fatalf("echoing %q: expected %s, got %s\n", arg, expected, got)
+		}
+	}
+}
+
+func TestNewShellLazyLookup(t *testing.T) {
+
+	l, err := NewShell(context.Background(), "echo lazy", WithInheritedEnv(), WithLazyLookup())
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer l.Close()
+
+	if err := l.Run(); err != nil {
+		t.Fatal(err)
+	}
+	if !strings.HasPrefix(l.cmd.SysProcAttr.CmdLine, quoteWindows(l.path)+" ") || l.path == "" {
+		t.Fatalf("expected the command line to start with the resolved shell, got %q\n", l.cmd.SysProcAttr.CmdLine)
+	}
+	b, err := io.ReadAll(l.StdOutReader())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := strings.TrimSpace(string(b)); got != "lazy" {
+		t.Fatalf("expected lazy, got %q\n", got)
+	}
+}
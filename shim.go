@@ -0,0 +1,123 @@
+package launcher
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"strconv"
+	"strings"
+	"syscall"
+)
+
+// shimEnv is the environment variable holding the restrictions to be
+// applied by the shim, whose presence causes a program importing this
+// package to act as the shim rather than run normally
+const shimEnv = "GFORD1000_LAUNCHER_SHIM"
+
+// shimExitCode is the exit code of the shim if it cannot apply the
+// restrictions or exec the target
+const shimExitCode = 127
+
+// shimConfig describes the restrictions the shim applies to itself
+// before it execs the target, which then inherits them
+type shimConfig struct {
+	Seccomp  *SeccompProfile `json:"seccomp,omitempty"`
+	Landlock *LandlockRules  `json:"landlock,omitempty"`
+	Label    string          `json:"label,omitempty"`
+	// NoNewPrivs is implied by Landlock and Seccomp
+	NoNewPrivs bool `json:"no_new_privs,omitempty"`
+	// ListenPID sets LISTEN_PID to the pid of the target, for which
+	// the shim is needed as the pid is unknown until the fork
+	ListenPID bool `json:"listen_pid,omitempty"`
+}
+
+// restricts returns true if the shim restricts the target
+func (cfg *shimConfig) restricts() bool {
+	return cfg.Seccomp != nil || cfg.Landlock != nil || cfg.Label != "" || cfg.NoNewPrivs
+}
+
+// Restrictions that cannot be applied from the parent are applied by
+// re-executing the current program as a shim, which restricts itself
+// and then execs the target, so the shim runs before the main function
+// of any program importing this package
+func init() {
+	enc, ok := os.LookupEnv(shimEnv)
+	if !ok {
+		return
+	}
+	if err := runShim(enc); err != nil {
+		fmt.Fprintf(os.Stderr, "launcher: %s: %v\n", os.Args[0], err)
+		os.Exit(shimExitCode)
+	}
+}
+
+// runShim applies the encoded restrictions and execs the target, which
+// is the first argument, and so only returns if it fails
+func runShim(enc string) error {
+	var cfg shimConfig
+	if err := json.Unmarshal([]byte(enc), &cfg); err != nil {
+		return err
+	}
+	if cfg.restricts() {
+		if err := applyShim(&cfg); err != nil {
+			return err
+		}
+	}
+
+	env := withoutShimEnv(os.Environ())
+	if cfg.ListenPID {
+		env = setEnv(env, []string{"LISTEN_PID"}, strconv.Itoa(os.Getpid()))
+	}
+	return syscall.Exec(os.Args[0], os.Args, env)
+}
+
+// shimConfig returns the configuration of the shim,
+// or nil if it is not needed
+func (o *options) shimConfig() *shimConfig {
+	cfg := &shimConfig{
+		Seccomp:    o.seccomp,
+		Landlock:   o.landlock,
+		Label:      o.label,
+		NoNewPrivs: o.noNewPrivs,
+		ListenPID:  len(o.listeners) > 0,
+	}
+	if !cfg.restricts() && !cfg.ListenPID {
+		return nil
+	}
+	return cfg
+}
+
+// useShim arranges for the process to be started by the shim,
+// if the options require it
+func (l *Launcher) useShim() error {
+	cfg := l.opts.shimConfig()
+	if cfg == nil {
+		return nil
+	}
+
+	exe, err := os.Executable()
+	if err != nil {
+		return err
+	}
+	enc, err := json.Marshal(cfg)
+	if err != nil {
+		return err
+	}
+
+	// The arguments are unchanged, so the shim receives the target as
+	// its first argument
+	l.cmd.Path = exe
+	l.cmd.Env = append(l.cmd.Env, shimEnv+"="+string(enc))
+	return nil
+}
+
+// withoutShimEnv returns the environment without the shim variable
+func withoutShimEnv(env []string) []string {
+	out := make([]string, 0, len(env))
+	for _, kv := range env {
+		if !strings.HasPrefix(kv, shimEnv+"=") {
+			out = append(out, kv)
+		}
+	}
+	return out
+}
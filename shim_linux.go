@@ -0,0 +1,38 @@
+package launcher
+
+import (
+	"runtime"
+
+	"golang.org/x/sys/unix"
+)
+
+// applyShim restricts the calling thread, which remains locked so that
+// the restrictions are inherited by the target when it is exec'd
+func applyShim(cfg *shimConfig) error {
+	runtime.LockOSThread()
+
+	if cfg.Label != "" {
+		if err := installLabel(cfg.Label); err != nil {
+			return err
+		}
+	}
+	if cfg.NoNewPrivs {
+		if err := unix.Prctl(unix.PR_SET_NO_NEW_PRIVS, 1, 0, 0, 0); err != nil {
+			return err
+		}
+	}
+	if cfg.Landlock != nil {
+		if err := installLandlock(cfg.Landlock); err != nil {
+			return err
+		}
+	}
+
+	// The seccomp filter is installed last, as it may deny the
+	// syscalls needed to apply the other restrictions
+	if cfg.Seccomp != nil {
+		if err := installSeccomp(cfg.Seccomp); err != nil {
+			return err
+		}
+	}
+	return nil
+}
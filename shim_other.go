@@ -0,0 +1,11 @@
+//go:build !linux
+
+package launcher
+
+import "errors"
+
+var errShimUnsupported = errors.New("process restrictions are only supported on linux")
+
+func applyShim(cfg *shimConfig) error {
+	return errShimUnsupported
+}
@@ -0,0 +1,70 @@
+package launcher
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"os/exec"
+	"strings"
+)
+
+var errMissingVerifier = errors.New("signature verifier must be provided")
+
+var errCodeSigningUnsupported = errors.New("code signing verification is only supported on macOS and Windows")
+
+// SignatureVerifier returns an error if the executable at
+// the path is not signed by a trusted signer
+type SignatureVerifier func(path string) error
+
+// WithSignatureVerifier requires the verifier to accept the resolved
+// executable before the process is started.  A rejection is returned
+// as a *PolicyError
+func WithSignatureVerifier(v SignatureVerifier) Option {
+	return func(o *options) error {
+		if v == nil {
+			return errMissingVerifier
+		}
+		return WithPolicy(PolicyFunc(func(req PolicyRequest) error {
+			if req.Stage != PolicyAtStart {
+				return nil
+			}
+			return v(req.Path)
+		}))(o)
+	}
+}
+
+// GPGDetachedSignature returns a SignatureVerifier which requires a
+// detached signature of the executable, in the file of the same name
+// with the suffix .sig, to be made by a key in the keyring.  Signatures
+// are checked using gpgv, which must be installed
+func GPGDetachedSignature(keyring string) SignatureVerifier {
+	return func(path string) error {
+		return runVerifier("gpgv", "--keyring", keyring, path+".sig", path)
+	}
+}
+
+// CodeSignature returns a SignatureVerifier which requires the executable
+// to have a valid platform code signature, checked by codesign on macOS
+// and by WinVerifyTrust on Windows
+func CodeSignature() SignatureVerifier {
+	return verifyCodeSignature
+}
+
+// runVerifier runs the command, returning its output as the
+// error if it does not succeed
+func runVerifier(file string, args ...string) error {
+	var out bytes.Buffer
+	cmd := exec.Command(file, args...)
+	cmd.Stdout = &out
+	cmd.Stderr = &out
+	if err := startChild(cmd); err != nil {
+		return err
+	}
+	if err := waitChild(cmd); err != nil {
+		if msg := strings.TrimSpace(out.String()); msg != "" {
+			return fmt.Errorf("%s: %w: %s", file, err, msg)
+		}
+		return fmt.Errorf("%s: %w", file, err)
+	}
+	return nil
+}
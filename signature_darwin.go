@@ -0,0 +1,5 @@
+package launcher
+
+func verifyCodeSignature(path string) error {
+	return runVerifier("codesign", "--verify", "--strict", path)
+}
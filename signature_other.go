@@ -0,0 +1,7 @@
+//go:build !darwin && !windows
+
+package launcher
+
+func verifyCodeSignature(path string) error {
+	return errCodeSigningUnsupported
+}
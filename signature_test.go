@@ -0,0 +1,93 @@
+package launcher
+
+import (
+	"context"
+	"errors"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"testing"
+)
+
+func TestSignatureVerifierAtStart(t *testing.T) {
+
+	var called []string
+	reject := errors.New("unsigned")
+	verifier := func(path string) error {
+		called = append(called, path)
+		return reject
+	}
+
+	l, err := NewWithOptions(context.Background(), "echo", nil, nil, WithSignatureVerifier(verifier))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer l.Close()
+
+	if len(called) != 0 {
+		t.Fatalf("expected verifier not to be called by New, got %v\n", called)
+	}
+
+	err = l.Start()
+	if !errors.Is(err, ErrPolicyDenied) || !errors.Is(err, reject) {
+		t.Fatal(err)
+	}
+	if len(called) != 1 || called[0] != l.GetPath() {
+		t.Fatalf("expected verifier to be called with %v, got %v\n", l.GetPath(), called)
+	}
+}
+
+func TestGPGDetachedSignature(t *testing.T) {
+
+	if _, err := exec.LookPath("gpgv"); err != nil {
+		t.Skip("gpgv is not installed")
+	}
+
+	dir := t.TempDir()
+	home := filepath.Join(dir, "gnupg")
+	if err := os.Mkdir(home, 0o700); err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { exec.Command("gpgconf", "--homedir", home, "--kill", "all").Run() })
+
+	gpg := func(args ...string) {
+		cmd := exec.Command("gpg", append([]string{"--homedir", home, "--batch", "--pinentry-mode", "loopback", "--passphrase", ""}, args...)...)
+		if out, err := cmd.CombinedOutput(); err != nil {
+			t.Skipf("gpg failed: %v: %s\n", err, out)
+		}
+	}
+
+	script := filepath.Join(dir, "hello")
+	if err := os.WriteFile(script, []byte("#!/bin/sh\necho hello\n"), 0o755); err != nil {
+		t.Fatal(err)
+	}
+	keyring := filepath.Join(dir, "trusted.gpg")
+
+	gpg("--quick-gen-key", "launcher-test", "ed25519", "sign", "never")
+	gpg("--output", script+".sig", "--detach-sign", script)
+	gpg("--output", keyring, "--export", "launcher-test")
+
+	l, err := NewWithOptions(context.Background(), script, nil, nil, WithSignatureVerifier(GPGDetachedSignature(keyring)))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer l.Close()
+
+	if err := l.Run(); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := os.WriteFile(script, []byte("#!/bin/sh\necho tampered\n"), 0o755); err != nil {
+		t.Fatal(err)
+	}
+
+	l, err = NewWithOptions(context.Background(), script, nil, nil, WithSignatureVerifier(GPGDetachedSignature(keyring)))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer l.Close()
+
+	if err := l.Run(); !errors.Is(err, ErrPolicyDenied) {
+		t.Fatal(err)
+	}
+}
@@ -0,0 +1,216 @@
+package launcher
+
+import (
+	"errors"
+	"io"
+	"os"
+	"sync"
+	"time"
+)
+
+var errMissingOutputSink = errors.New("output sink must be provided")
+
+// OutputSink is a destination for the output of a process, such as a
+// database or message queue.  WriteChunk is called for each chunk of
+// output, from either pipe, in the order in which it arrived, and never
+// concurrently.  The chunk may be retained.  Close is called once both
+// pipes are exhausted.  A sink which returns an error from WriteChunk
+// receives no further output, and is closed
+type OutputSink interface {
+	WriteChunk(c OutputChunk) error
+	Close() error
+}
+
+// WithOutputSink passes the output of the process to the sink, as well
+// as to StdOutReader and StdErrReader.  The output of the process is
+// held up whilst the sink is writing
+func WithOutputSink(sink OutputSink) Option {
+	return func(o *options) error {
+		if sink == nil {
+			return errMissingOutputSink
+		}
+		o.sinks = append(o.sinks, sink)
+		return nil
+	}
+}
+
+// prepareSinks taps the output of the process into the sinks
+func (l *Launcher) prepareSinks() {
+	for _, sink := range l.opts.sinks {
+		l.tapSink(sink)
+	}
+}
+
+// tapSink taps the stdout and stderr of the process into the sink,
+// and must be called during initialise
+func (l *Launcher) tapSink(sink OutputSink) {
+	s := &sinkStream{sink: sink, remaining: 2}
+	l.tapStdOut(&sinkTap{stream: s, source: SourceStdout})
+	l.tapStdErr(&sinkTap{stream: s, source: SourceStderr})
+}
+
+// sinkStream passes the chunks written to its taps to the sink,
+// closing it once each tap is closed
+type sinkStream struct {
+	mu        sync.Mutex
+	sink      OutputSink
+	remaining int
+	failed    bool
+}
+
+// write timestamps and writes the chunk, holding the lock so that
+// the chunks are written in order
+func (s *sinkStream) write(source OutputSource, b []byte) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.failed {
+		return
+	}
+	c := OutputChunk{Source: source, Time: time.Now(), Data: append([]byte{}, b...)}
+	if err := s.sink.WriteChunk(c); err != nil {
+		s.failed = true
+		s.sink.Close()
+	}
+}
+
+func (s *sinkStream) close() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.remaining--
+	if s.remaining == 0 && !s.failed {
+		s.sink.Close()
+	}
+}
+
+// sinkTap writes the output from one source to a sink stream.  Errors
+// are not returned, which would end the pumping of the output
+type sinkTap struct {
+	stream *sinkStream
+	source OutputSource
+}
+
+func (t *sinkTap) Write(b []byte) (int, error) {
+	t.stream.write(t.source, b)
+	return len(b), nil
+}
+
+func (t *sinkTap) Close() error {
+	t.stream.close()
+	return nil
+}
+
+// WriterSink returns an OutputSink writing the output from both pipes
+// to w, which is not closed by the sink
+func WriterSink(w io.Writer) OutputSink {
+	return &writerSink{w: w}
+}
+
+type writerSink struct {
+	w io.Writer
+}
+
+func (s *writerSink) WriteChunk(c OutputChunk) error {
+	_, err := s.w.Write(c.Data)
+	return err
+}
+
+func (s *writerSink) Close() error {
+	return nil
+}
+
+// FileSink returns an OutputSink appending the output from both pipes
+// to the file, which is created if necessary and closed with the sink
+func FileSink(path string) (OutputSink, error) {
+	f, err := os.OpenFile(path, os.O_CREATE|os.O_WRONLY|os.O_APPEND, 0o644)
+	if err != nil {
+		return nil, err
+	}
+	return &fileSink{writerSink{w: f}, f}, nil
+}
+
+type fileSink struct {
+	writerSink
+	f *os.File
+}
+
+func (s *fileSink) Close() error {
+	return s.f.Close()
+}
+
+// ChannelSink returns an OutputSink sending each chunk to ch, which is
+// closed with the sink.  The channel must be drained, as the output of
+// the process is held up whilst it is full
+func ChannelSink(ch chan<- OutputChunk) OutputSink {
+	return &channelSink{ch: ch}
+}
+
+// channelSink sends chunks to a channel, giving up on a chunk
+// which cannot be sent once done is closed, if set
+type channelSink struct {
+	ch   chan<- OutputChunk
+	done <-chan struct{}
+}
+
+func (s *channelSink) WriteChunk(c OutputChunk) error {
+	select {
+	case s.ch <- c:
+	default:
+		select {
+		case s.ch <- c:
+		case <-s.done:
+		}
+	}
+	return nil
+}
+
+func (s *channelSink) Close() error {
+	close(s.ch)
+	return nil
+}
+
+// RingSink is an OutputSink retaining the most recent chunks of output
+type RingSink struct {
+	mu     sync.Mutex
+	chunks []OutputChunk
+	next   int
+	full   bool
+}
+
+// NewRingSink creates a RingSink retaining the most recent n chunks
+func NewRingSink(n int) (*RingSink, error) {
+	if n <= 0 {
+		return nil, errInvalidSize
+	}
+	return &RingSink{chunks: make([]OutputChunk, n)}, nil
+}
+
+// WriteChunk retains the chunk, discarding the oldest if the sink is full
+func (r *RingSink) WriteChunk(c OutputChunk) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	r.chunks[r.next] = c
+	r.next = (r.next + 1) % len(r.chunks)
+	if r.next == 0 {
+		r.full = true
+	}
+	return nil
+}
+
+// Close has no effect, as the chunks remain available
+func (r *RingSink) Close() error {
+	return nil
+}
+
+// Chunks returns the retained chunks, oldest first
+func (r *RingSink) Chunks() []OutputChunk {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if !r.full {
+		return append([]OutputChunk{}, r.chunks[:r.next]...)
+	}
+	return append(append([]OutputChunk{}, r.chunks[r.next:]...), r.chunks[:r.next]...)
+}
@@ -0,0 +1,95 @@
+package launcher
+
+import (
+	"context"
+	"errors"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"testing"
+)
+
+// failingSink fails after accepting its first chunk
+type failingSink struct {
+	mu     sync.Mutex
+	chunks int
+	closed int
+}
+
+func (f *failingSink) WriteChunk(OutputChunk) error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.chunks++
+	return errors.New("sink failed")
+}
+
+func (f *failingSink) Close() error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.closed++
+	return nil
+}
+
+func TestWithOutputSink(t *testing.T) {
+
+	var sb strings.Builder
+	path := filepath.Join(t.TempDir(), "out.log")
+	file, err := FileSink(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	ring, err := NewRingSink(2)
+	if err != nil {
+		t.Fatal(err)
+	}
+	ch := make(chan OutputChunk, 10)
+	failing := &failingSink{}
+
+	script := "echo one; sleep 0.05; echo two >&2; sleep 0.05; echo three"
+	l, err := NewWithOptions(context.Background(), "sh", nil, []string{"-c", script},
+		WithOutputSink(WriterSink(&sb)), WithOutputSink(file), WithOutputSink(ring),
+		WithOutputSink(ChannelSink(ch)), WithOutputSink(failing))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer l.Close()
+
+	if err := l.Start(); err != nil {
+		t.Fatal(err)
+	}
+	var received []string
+	for c := range ch {
+		received = append(received, c.Source.String()+":"+string(c.Data))
+	}
+	l.Wait()
+
+	expected := "stdout:one\n,stderr:two\n,stdout:three\n"
+	if strings.Join(received, ",") != expected {
+		t.Fatalf("expected %q, got %q\n", expected, received)
+	}
+	if sb.String() != "one\ntwo\nthree\n" {
+		t.Fatalf("unexpected writer output %q\n", sb.String())
+	}
+	b, err := os.ReadFile(path)
+	if err != nil || string(b) != "one\ntwo\nthree\n" {
+		t.Fatalf("unexpected file output %q (%v)\n", b, err)
+	}
+	if chunks := ring.Chunks(); len(chunks) != 2 || string(chunks[0].Data) != "two\n" || string(chunks[1].Data) != "three\n" {
+		t.Fatalf("unexpected ring chunks %v\n", chunks)
+	}
+	if failing.chunks != 1 || failing.closed != 1 {
+		t.Fatalf("expected failed sink to be closed after one chunk, got %+v\n", failing)
+	}
+
+	// A failed sink does not affect the readers of the output
+	out, err := io.ReadAll(l.StdOutReader())
+	if err != nil || string(out) != "one\nthree\n" {
+		t.Fatalf("unexpected stdout %q (%v)\n", out, err)
+	}
+
+	if _, err := NewWithOptions(context.Background(), "true", nil, nil, WithOutputSink(nil)); err == nil {
+		t.Fatal("expected error for nil sink")
+	}
+}
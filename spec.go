@@ -0,0 +1,28 @@
+package launcher
+
+import "context"
+
+// Spec describes a command from which any number of Launcher
+// instances can be created
+type Spec struct {
+	File    string
+	Env     []string
+	Args    []string
+	Options []Option
+}
+
+// New creates a new, unstarted Launcher from the Spec
+func (s Spec) New(ctx context.Context) (*Launcher, error) {
+	return NewWithOptions(ctx, s.File, s.Env, s.Args, s.Options...)
+}
+
+// options returns the configuration resulting from the Spec's Options
+func (s Spec) options() (options, error) {
+	var o options
+	for _, opt := range s.Options {
+		if err := opt(&o); err != nil {
+			return o, err
+		}
+	}
+	return o, nil
+}
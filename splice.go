@@ -0,0 +1,36 @@
+package launcher
+
+import (
+	"io"
+	"os"
+)
+
+// copyStream copies from r to w until EOF, in the manner of io.Copy.
+// Output from a pipe to a regular file is spliced by the kernel where
+// the platform supports it, so that it is not copied through the memory
+// of the parent, greatly reducing the CPU used to archive large outputs
+func copyStream(w io.Writer, r io.Reader) (int64, error) {
+	dst, ok := w.(*os.File)
+	if !ok {
+		return pooledCopy(w, r)
+	}
+	// The pipes of the process are wrapped to count their content
+	c, ok := r.(*countingReader)
+	if !ok {
+		return pooledCopy(w, r)
+	}
+	src, ok := c.file()
+	if !ok {
+		return pooledCopy(w, r)
+	}
+	if fi, err := dst.Stat(); err != nil || !fi.Mode().IsRegular() {
+		return pooledCopy(w, r)
+	}
+
+	n, handled, err := spliceFile(dst, src)
+	if handled {
+		c.n.Add(n)
+		return n, err
+	}
+	return pooledCopy(w, r)
+}
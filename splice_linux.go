@@ -0,0 +1,57 @@
+//go:build linux
+
+package launcher
+
+import (
+	"errors"
+	"os"
+
+	"golang.org/x/sys/unix"
+)
+
+// spliceChunk is the most requested of each call to splice,
+// which the kernel limits to the content of the pipe
+const spliceChunk = 1 << 20
+
+// spliceFile moves the content of the pipe src to the file dst until
+// EOF, reporting whether the copy was handled.  A copy which fails
+// before any data is moved is not handled, so may be retried by other
+// means, as when dst was opened with O_APPEND, which splice rejects
+func spliceFile(dst, src *os.File) (written int64, handled bool, err error) {
+	sc, err := src.SyscallConn()
+	if err != nil {
+		return 0, false, nil
+	}
+	dc, err := dst.SyscallConn()
+	if err != nil {
+		return 0, false, nil
+	}
+
+	for {
+		var n int64
+		var serr error
+		err := sc.Read(func(sfd uintptr) bool {
+			cerr := dc.Control(func(dfd uintptr) {
+				n, serr = unix.Splice(int(sfd), nil, int(dfd), nil, spliceChunk, unix.SPLICE_F_MOVE|unix.SPLICE_F_NONBLOCK)
+			})
+			if cerr != nil {
+				serr = cerr
+			}
+			// Wait for the pipe to become readable if it is empty
+			return !errors.Is(serr, unix.EAGAIN)
+		})
+		if err == nil {
+			err = serr
+		}
+
+		switch {
+		case err != nil && written == 0:
+			return 0, false, nil
+		case err != nil:
+			return written, true, os.NewSyscallError("splice", err)
+		case n == 0:
+			return written, true, nil
+		}
+		written += n
+	}
+}
@@ -0,0 +1,80 @@
+package launcher
+
+import (
+	"bytes"
+	"context"
+	"io"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestSpliceFile(t *testing.T) {
+
+	input := bytes.Repeat([]byte("0123456789abcdef"), 200000)
+
+	pr, pw, err := os.Pipe()
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer pr.Close()
+	go func() {
+		pw.Write(input)
+		pw.Close()
+	}()
+
+	path := filepath.Join(t.TempDir(), "out")
+	f, err := os.Create(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer f.Close()
+
+	n, handled, err := spliceFile(f, pr)
+	if err != nil || !handled || n != int64(len(input)) {
+		t.Fatalf("unexpected splice result %v, %v, %v\n", n, handled, err)
+	}
+
+	b, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !bytes.Equal(b, input) {
+		t.Fatalf("expected %v bytes, got %v\n", len(input), len(b))
+	}
+}
+
+func TestCopyStreamToFile(t *testing.T) {
+
+	// Splice rejects files opened with O_APPEND, so these use the fallback
+	for _, flag := range []int{os.O_TRUNC, os.O_APPEND} {
+		l, err := New(context.Background(), "sh", nil, "-c", "head -c 3000000 /dev/zero | tr '\\0' 'x'")
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		path := filepath.Join(t.TempDir(), "out")
+		f, err := os.OpenFile(path, os.O_CREATE|os.O_WRONLY|flag, 0o600)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		if err := l.Start(); err != nil {
+			t.Fatal(err)
+		}
+		n, err := io.Copy(f, l)
+		f.Close()
+		l.Close()
+		if err != nil || n != 3000000 {
+			t.Fatalf("unexpected copy result %v, %v\n", n, err)
+		}
+
+		b, err := os.ReadFile(path)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if !bytes.Equal(b, bytes.Repeat([]byte("x"), 3000000)) {
+			t.Fatalf("unexpected file content of %v bytes\n", len(b))
+		}
+	}
+}
@@ -0,0 +1,10 @@
+//go:build !linux
+
+package launcher
+
+import "os"
+
+// spliceFile is not supported, so the copy is never handled
+func spliceFile(dst, src *os.File) (written int64, handled bool, err error) {
+	return 0, false, nil
+}
@@ -0,0 +1,86 @@
+package launcher
+
+import "time"
+
+// State is the stage reached in the lifecycle of a Launcher
+type State int
+
+const (
+	// StateCreated is the state of a Launcher which has not been started
+	StateCreated State = iota
+	// StateStarting is the state while the process is being launched
+	StateStarting
+	// StateRunning is the state from the process being launched until it
+	// has exited and been reaped, including while it is being killed
+	StateRunning
+	// StateExited is the state once the process has exited successfully
+	StateExited
+	// StateFailed is the state once the process has exited unsuccessfully,
+	// or if it could not be launched
+	StateFailed
+	// StateKilled is the state once the process has been terminated by
+	// a signal or by the cancellation of the Launcher
+	StateKilled
+	// StateClosed is the state once Close has been called
+	StateClosed
+)
+
+var stateNames = map[State]string{
+	StateCreated:  "created",
+	StateStarting: "starting",
+	StateRunning:  "running",
+	StateExited:   "exited",
+	StateFailed:   "failed",
+	StateKilled:   "killed",
+	StateClosed:   "closed",
+}
+
+func (s State) String() string {
+	if name, ok := stateNames[s]; ok {
+		return name
+	}
+	return "unknown"
+}
+
+// State returns the current State of the Launcher
+func (l *Launcher) State() State {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	return l.state
+}
+
+// canStart returns true if a process may be launched from the
+// state, which is only possible if no process has been launched
+func (s State) canStart() bool {
+	return s == StateCreated || s == StateFailed
+}
+
+// setState moves the Launcher to the state, unless it has been closed,
+// and must be called with the lock held
+func (l *Launcher) setState(s State) {
+	if l.state != StateClosed {
+		l.state = s
+	}
+}
+
+// exited records the final State of the reaped process,
+// and the time at which it finished
+func (l *Launcher) exited(err error) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	l.finishedAt = time.Now()
+	switch {
+	case l.ctx.Err() != nil:
+		l.setState(StateKilled)
+	case l.adopted:
+		l.setState(StateExited)
+	case l.cmd.ProcessState != nil && l.cmd.ProcessState.ExitCode() == -1:
+		l.setState(StateKilled)
+	case err != nil:
+		l.setState(StateFailed)
+	default:
+		l.setState(StateExited)
+	}
+}
@@ -0,0 +1,74 @@
+package launcher
+
+import (
+	"context"
+	"errors"
+	"testing"
+)
+
+func TestStateTransitions(t *testing.T) {
+
+	l, err := New(context.Background(), "sh", nil, "-c", "exit 0")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if l.State() != StateCreated {
+		t.Fatalf("expected created, got %v\n", l.State())
+	}
+	if err := l.Run(); err != nil {
+		t.Fatal(err)
+	}
+	if l.State() != StateExited {
+		t.Fatalf("expected exited, got %v\n", l.State())
+	}
+	if err := l.Start(); !errors.Is(err, ErrAlreadyStarted) {
+		t.Fatalf("expected ErrAlreadyStarted, got %v\n", err)
+	}
+
+	l.Close()
+	if l.State() != StateClosed {
+		t.Fatalf("expected closed, got %v\n", l.State())
+	}
+}
+
+func TestStateFailedAndKilled(t *testing.T) {
+
+	failed, err := New(context.Background(), "sh", nil, "-c", "exit 1")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer failed.Close()
+
+	if err := failed.Run(); err == nil {
+		t.Fatal("expected failure")
+	}
+	if failed.State() != StateFailed {
+		t.Fatalf("expected failed, got %v\n", failed.State())
+	}
+
+	killed, err := New(context.Background(), "sleep", nil, "10")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer killed.Close()
+
+	if err := killed.Start(); err != nil {
+		t.Fatal(err)
+	}
+	if killed.State() != StateRunning {
+		t.Fatalf("expected running, got %v\n", killed.State())
+	}
+	killed.Cancel()
+	killed.Wait()
+	if killed.State() != StateKilled {
+		t.Fatalf("expected killed, got %v\n", killed.State())
+	}
+}
+
+func TestStateString(t *testing.T) {
+
+	if StateRunning.String() != "running" || State(99).String() != "unknown" {
+		t.Fatal("unexpected state names")
+	}
+}
@@ -0,0 +1,92 @@
+package launcher
+
+import (
+	"context"
+	"errors"
+	"io/fs"
+)
+
+var errMissingFS = errors.New("file system must be provided")
+
+var errStdinConflict = errors.New("stdin may only be supplied by one option")
+
+// WithStdinBytes passes the bytes to the stdin of the process once it
+// has started, and then closes stdin so that the process sees EOF
+func WithStdinBytes(b []byte) Option {
+	return func(o *options) error {
+		o.stdinBytes = append([]byte{}, b...)
+		return nil
+	}
+}
+
+// WithStdinFromFS streams the named file of the file system, such as
+// an embed.FS holding scripts or configuration, to the stdin of the
+// process once it has started, and then closes stdin, without the file
+// first being copied to disk.  The file must exist when the Launcher is
+// created.  It should not be combined with WithStdinBytes
+func WithStdinFromFS(fsys fs.FS, name string) Option {
+	return func(o *options) error {
+		if fsys == nil {
+			return errMissingFS
+		}
+		if name == "" {
+			return errMissingName
+		}
+		o.stdinFS = fsys
+		o.stdinName = name
+		return nil
+	}
+}
+
+// RunWithInput launches the underlying process, passes the bytes to its
+// stdin followed by EOF, and waits until it completes.  The process is
+// cancelled if the context ends first.  It should not be combined with
+// WithStdinBytes
+func (l *Launcher) RunWithInput(ctx context.Context, b []byte) error {
+	if ctx == nil {
+		return l.launchError(StageStart, ErrMissingContext)
+	}
+	if err := l.StartAndWaitReady(ctx); err != nil {
+		return err
+	}
+	l.goLabelled("stdin-feed", func() { l.feedStdIn(b) })
+
+	stop := context.AfterFunc(ctx, l.Cancel)
+	defer stop()
+
+	return l.Wait()
+}
+
+// feedStdIn writes the bytes to stdin and closes it.  Errors are
+// ignored, as the process may exit without reading all of its input
+func (l *Launcher) feedStdIn(b []byte) {
+	l.cmdWriter.Write(b)
+	l.cmdWriter.Close()
+}
+
+// feedStdInFS copies the file set by WithStdinFromFS to stdin and
+// closes it, ignoring errors in the same way as feedStdIn
+func (l *Launcher) feedStdInFS() {
+	defer l.cmdWriter.Close()
+
+	f, err := l.opts.stdinFS.Open(l.opts.stdinName)
+	if err != nil {
+		return
+	}
+	defer f.Close()
+
+	pooledCopy(l.cmdWriter, f)
+}
+
+// checkStdinSource ensures that stdin is supplied by a single option,
+// and that any file to be streamed to it exists
+func (o *options) checkStdinSource() error {
+	if o.stdinFS == nil {
+		return nil
+	}
+	if o.stdinBytes != nil {
+		return errStdinConflict
+	}
+	_, err := fs.Stat(o.stdinFS, o.stdinName)
+	return err
+}
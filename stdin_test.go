@@ -0,0 +1,116 @@
+package launcher
+
+import (
+	"context"
+	"errors"
+	"io"
+	"io/fs"
+	"strings"
+	"testing"
+	"testing/fstest"
+	"time"
+)
+
+func TestStdinBytes(t *testing.T) {
+
+	input := "foo bar\nbaz"
+
+	l, err := NewWithOptions(context.Background(), "cat", nil, nil, WithStdinBytes([]byte(input)))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer l.Close()
+
+	if err := l.Run(); err != nil {
+		t.Fatal(err)
+	}
+	b, err := io.ReadAll(l.StdOutReader())
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if string(b) != input {
+		t.Fatalf("invalid response - expected %q, got %q\n", input, string(b))
+	}
+}
+
+func TestRunWithInput(t *testing.T) {
+
+	l, err := New(context.Background(), "sh", nil, "-c", "wc -l")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer l.Close()
+
+	if err := l.RunWithInput(context.Background(), []byte("a\nb\nc\n")); err != nil {
+		t.Fatal(err)
+	}
+	b, err := io.ReadAll(l.StdOutReader())
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if string(b) != "3\n" && string(b) != "       3\n" {
+		t.Fatalf("unexpected output %q\n", b)
+	}
+}
+
+func TestRunWithInputCancel(t *testing.T) {
+
+	l, err := New(context.Background(), "sh", nil, "-c", "cat; sleep 10")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer l.Close()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 100*time.Millisecond)
+	defer cancel()
+
+	start := time.Now()
+	if err := l.RunWithInput(ctx, nil); err == nil {
+		t.Fatal("expected cancelled process to fail")
+	}
+	if time.Since(start) > 5*time.Second {
+		t.Fatal("expected process to be cancelled")
+	}
+
+	if err := l.RunWithInput(nil, nil); !errors.Is(err, ErrMissingContext) {
+		t.Fatal(err)
+	}
+}
+
+func TestWithStdinFromFS(t *testing.T) {
+
+	script := strings.Repeat("echo line\n", 10000) + "echo done\n"
+	fsys := fstest.MapFS{"scripts/run.sh": {Data: []byte(script)}}
+
+	l, err := NewWithOptions(context.Background(), "sh", nil, nil, WithStdinFromFS(fsys, "scripts/run.sh"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer l.Close()
+
+	res, err := l.RunCaptured()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if string(res.Stdout) != strings.Repeat("line\n", 10000)+"done\n" {
+		t.Fatalf("unexpected output of %v bytes\n", len(res.Stdout))
+	}
+
+	tests := []struct {
+		name string
+		opts []Option
+		err  error
+	}{
+		{"missing file", []Option{WithStdinFromFS(fsys, "missing.sh")}, fs.ErrNotExist},
+		{"conflict", []Option{WithStdinFromFS(fsys, "scripts/run.sh"), WithStdinBytes([]byte("x"))}, errStdinConflict},
+		{"inherited", []Option{WithStdinFromFS(fsys, "scripts/run.sh"), WithInheritStdio()}, errStdinInherited},
+		{"nil fs", []Option{WithStdinFromFS(nil, "x")}, errMissingFS},
+	}
+	for _, test := range tests {
+		if _, err := NewWithOptions(context.Background(), "sh", nil, nil, test.opts...); !errors.Is(err, test.err) {
+			t.Fatalf("%v: expected %v, got %v\n", test.name, test.err, err)
+		}
+	}
+}
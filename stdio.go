@@ -0,0 +1,77 @@
+package launcher
+
+import (
+	"errors"
+	"io"
+	"os"
+)
+
+var errOutputNotPiped = errors.New("output is not piped, so cannot be observed")
+var errStdinInherited = errors.New("stdin is inherited from the parent")
+
+// WithDiscardOutput connects the stdout and stderr of the process to
+// the null device rather than to pipes, so that a process whose output
+// is never read cannot block once the pipes are full.  The readers of
+// the output return io.EOF, and options which observe the output, such
+// as WithOutputRingBuffer or a stdout readiness Probe, are rejected
+func WithDiscardOutput() Option {
+	return func(o *options) error {
+		o.discardOutput = true
+		return nil
+	}
+}
+
+// WithInheritStdio connects the process directly to the stdin, stdout
+// and stderr of the parent rather than to pipes, so that it can detect
+// and interact with a terminal, as when wrapping another command line
+// tool.  The readers of the output return io.EOF, SendStdIn fails, and
+// options which observe the output or write to stdin are rejected
+func WithInheritStdio() Option {
+	return func(o *options) error {
+		o.inheritStdio = true
+		return nil
+	}
+}
+
+// inputPipe connects the stdin of the process to the writer
+// used by SendStdIn
+func (l *Launcher) inputPipe() error {
+	if err := l.opts.checkStdinSource(); err != nil {
+		return err
+	}
+	if l.opts.inheritStdio {
+		if l.opts.stdinBytes != nil || l.opts.stdinFS != nil {
+			return errStdinInherited
+		}
+		l.cmd.Stdin = os.Stdin
+		l.cmdWriter = inheritedStdin{}
+		return nil
+	}
+
+	pw, err := l.cmd.StdinPipe()
+	if err != nil {
+		return err
+	}
+	l.cmdWriter = pw
+	// StdinPipe sets the read end of the pipe as the stdin of the process
+	return l.resizePipe(l.cmd.Stdin.(*os.File))
+}
+
+// inheritedStdin is the writer used by SendStdIn
+// when stdin is inherited from the parent
+type inheritedStdin struct{}
+
+func (inheritedStdin) Write([]byte) (int, error) {
+	return 0, errStdinInherited
+}
+
+func (inheritedStdin) Close() error {
+	return nil
+}
+
+// eofReader is an io.Reader with no content
+type eofReader struct{}
+
+func (eofReader) Read([]byte) (int, error) {
+	return 0, io.EOF
+}
@@ -0,0 +1,96 @@
+package launcher
+
+import (
+	"context"
+	"errors"
+	"io"
+	"os"
+	"testing"
+	"time"
+)
+
+func TestDiscardOutput(t *testing.T) {
+
+	// The output far exceeds the capacity of a pipe, which is never read
+	script := "head -c 1048576 /dev/zero; head -c 1048576 /dev/zero >&2"
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	l, err := NewWithOptions(ctx, "sh", nil, []string{"-c", script}, WithDiscardOutput())
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer l.Close()
+
+	if err := l.Run(); err != nil {
+		t.Fatal(err)
+	}
+
+	for _, r := range []io.Reader{l.StdOutReader(), l.StdErrReader()} {
+		if n, err := r.Read(make([]byte, 10)); n != 0 || err != io.EOF {
+			t.Fatalf("expected io.EOF, got %v bytes and %v\n", n, err)
+		}
+	}
+}
+
+func TestDiscardOutputObserved(t *testing.T) {
+
+	_, err := NewWithOptions(context.Background(), "echo", nil, nil, WithDiscardOutput(), WithOutputRingBuffer(5))
+	if !errors.Is(err, errOutputNotPiped) {
+		t.Fatalf("expected errOutputNotPiped, got %v\n", err)
+	}
+}
+
+func TestInheritStdio(t *testing.T) {
+
+	dir := t.TempDir()
+	stdin, err := os.CreateTemp(dir, "stdin")
+	if err != nil {
+		t.Fatal(err)
+	}
+	stdin.WriteString("input\n")
+	stdin.Seek(0, io.SeekStart)
+	stdout, _ := os.CreateTemp(dir, "stdout")
+	stderr, _ := os.CreateTemp(dir, "stderr")
+
+	// The parent's stdio is replaced whilst the Launcher is created
+	origIn, origOut, origErr := os.Stdin, os.Stdout, os.Stderr
+	os.Stdin, os.Stdout, os.Stderr = stdin, stdout, stderr
+	l, err := NewWithOptions(context.Background(), "sh", nil, []string{"-c", "cat; echo err >&2"}, WithInheritStdio())
+	os.Stdin, os.Stdout, os.Stderr = origIn, origOut, origErr
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer l.Close()
+
+	if err := l.SendStdIn([]byte("foo")); err != errStdinInherited {
+		t.Fatalf("expected errStdinInherited, got %v\n", err)
+	}
+	if err := l.Run(); err != nil {
+		t.Fatal(err)
+	}
+
+	if b, _ := os.ReadFile(stdout.Name()); string(b) != "input\n" {
+		t.Fatalf("unexpected stdout %q\n", b)
+	}
+	if b, _ := os.ReadFile(stderr.Name()); string(b) != "err\n" {
+		t.Fatalf("unexpected stderr %q\n", b)
+	}
+	if n, err := l.StdOutReader().Read(make([]byte, 10)); n != 0 || err != io.EOF {
+		t.Fatalf("expected io.EOF, got %v bytes and %v\n", n, err)
+	}
+}
+
+func TestInheritStdioRejected(t *testing.T) {
+
+	_, err := NewWithOptions(context.Background(), "cat", nil, nil, WithInheritStdio(), WithStdinBytes([]byte("foo")))
+	if !errors.Is(err, errStdinInherited) {
+		t.Fatalf("expected errStdinInherited, got %v\n", err)
+	}
+
+	_, err = NewWithOptions(context.Background(), "cat", nil, nil, WithInheritStdio(), WithCombinedStream())
+	if !errors.Is(err, errOutputNotPiped) {
+		t.Fatalf("expected errOutputNotPiped, got %v\n", err)
+	}
+}
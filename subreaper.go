@@ -0,0 +1,82 @@
+package launcher
+
+import (
+	"errors"
+	"os/exec"
+	"sync"
+)
+
+var errSubreaperEnabled = errors.New("subreaper has already been enabled")
+
+// spawnMu is held for reading while child processes are started, and for
+// writing while the Subreaper reaps, so that a child is always recorded as
+// owned before the Subreaper can see it
+var spawnMu sync.RWMutex
+
+// owned records the children started by this package, which are waited
+// upon by their exec.Cmd and so must not be reaped by the Subreaper
+var owned = struct {
+	sync.Mutex
+	pids map[int]bool
+}{pids: map[int]bool{}}
+
+// startChild starts the command, recording the child as owned
+func startChild(cmd *exec.Cmd) error {
+	spawnMu.RLock()
+	defer spawnMu.RUnlock()
+
+	if err := cmd.Start(); err != nil {
+		return err
+	}
+
+	owned.Lock()
+	owned.pids[cmd.Process.Pid] = true
+	owned.Unlock()
+	return nil
+}
+
+// waitChild waits for a child started by startChild,
+// which is no longer owned once it has been reaped
+func waitChild(cmd *exec.Cmd) error {
+	err := cmd.Wait()
+
+	owned.Lock()
+	delete(owned.pids, cmd.Process.Pid)
+	owned.Unlock()
+	return err
+}
+
+// isOwned returns true if the child was started by this package
+func isOwned(pid int) bool {
+	owned.Lock()
+	defer owned.Unlock()
+
+	return owned.pids[pid]
+}
+
+// Subreaper adopts the orphaned descendants of the processes started
+// by this package, reaping them as they exit so that zombies do not
+// accumulate in long-lived supervisors.  Children started other than
+// through this package, such as by calling os/exec directly, are
+// treated as orphans once they exit, so should not be used whilst a
+// Subreaper is enabled
+type Subreaper struct {
+	// KillOnClose kills the orphans still running when Close is called
+	KillOnClose bool
+
+	stop chan struct{}
+	done chan struct{}
+	once sync.Once
+}
+
+// Close stops reaping, first killing any remaining orphans
+// if KillOnClose is set
+func (s *Subreaper) Close() error {
+	var err error
+	s.once.Do(func() {
+		close(s.stop)
+		<-s.done
+		err = s.disable()
+	})
+	return err
+}
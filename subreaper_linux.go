@@ -0,0 +1,122 @@
+//go:build linux
+
+package launcher
+
+import (
+	"os"
+	"os/signal"
+	"sync"
+	"syscall"
+	"time"
+
+	"golang.org/x/sys/unix"
+)
+
+// subreaperInterval is the pause between sweeps for orphans,
+// which are also swept whenever SIGCHLD is received
+const subreaperInterval = time.Second
+
+// subreaperKillTimeout limits the wait for killed orphans to exit
+const subreaperKillTimeout = 5 * time.Second
+
+var subreaper struct {
+	sync.Mutex
+	enabled bool
+}
+
+// EnableSubreaper marks the calling process as a child subreaper, so that
+// orphaned descendants are re-parented to it rather than to init, and
+// starts reaping them.  Only one Subreaper may be enabled at a time
+func EnableSubreaper() (*Subreaper, error) {
+	subreaper.Lock()
+	defer subreaper.Unlock()
+
+	if subreaper.enabled {
+		return nil, errSubreaperEnabled
+	}
+	if err := unix.Prctl(unix.PR_SET_CHILD_SUBREAPER, 1, 0, 0, 0); err != nil {
+		return nil, os.NewSyscallError("prctl", err)
+	}
+	subreaper.enabled = true
+
+	s := &Subreaper{
+		stop: make(chan struct{}),
+		done: make(chan struct{}),
+	}
+	go s.run()
+	return s, nil
+}
+
+// run sweeps for orphans until the Subreaper is closed
+func (s *Subreaper) run() {
+	defer close(s.done)
+
+	sigs := make(chan os.Signal, 1)
+	signal.Notify(sigs, syscall.SIGCHLD)
+	defer signal.Stop(sigs)
+
+	ticker := time.NewTicker(subreaperInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-s.stop:
+			return
+		case <-sigs:
+		case <-ticker.C:
+		}
+		s.reap(false)
+	}
+}
+
+// reap waits upon the orphans which have exited, first killing
+// those still running if kill is set, and returns the number
+// which remain running
+func (s *Subreaper) reap(kill bool) int {
+	spawnMu.Lock()
+	defer spawnMu.Unlock()
+
+	procs, err := listProcs()
+	if err != nil {
+		return 0
+	}
+
+	self := os.Getpid()
+	running := 0
+	for _, p := range procs {
+		if p.ppid != self || isOwned(p.pid) {
+			continue
+		}
+		if p.state != 'Z' {
+			running++
+			if kill {
+				unix.Kill(p.pid, unix.SIGKILL)
+			}
+			continue
+		}
+		var ws unix.WaitStatus
+		unix.Wait4(p.pid, &ws, unix.WNOHANG, nil)
+	}
+	return running
+}
+
+// disable kills any remaining orphans if required, and then
+// stops the process being a child subreaper
+func (s *Subreaper) disable() error {
+	if s.KillOnClose {
+		deadline := time.Now().Add(subreaperKillTimeout)
+		for s.reap(true) > 0 && time.Now().Before(deadline) {
+			time.Sleep(10 * time.Millisecond)
+		}
+		s.reap(false)
+	}
+
+	subreaper.Lock()
+	defer subreaper.Unlock()
+
+	subreaper.enabled = false
+	if err := unix.Prctl(unix.PR_SET_CHILD_SUBREAPER, 0, 0, 0, 0); err != nil {
+		return os.NewSyscallError("prctl", err)
+	}
+	return nil
+}
@@ -0,0 +1,89 @@
+package launcher
+
+import (
+	"context"
+	"os"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+)
+
+// startOrphan runs a shell which leaves a background sleep running
+// after it exits, returning the pid of the sleep
+func startOrphan(t *testing.T, seconds string) int {
+	l, err := New(context.Background(), "sh", nil, "-c", "sleep "+seconds+" >/dev/null 2>&1 & echo $!")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer l.Close()
+
+	var out strings.Builder
+	if err := l.Start(); err != nil {
+		t.Fatal(err)
+	}
+	l.copyOutput(&out, nil)
+	if err := l.Wait(); err != nil {
+		t.Fatal(err)
+	}
+
+	pid, err := strconv.Atoi(strings.TrimSpace(out.String()))
+	if err != nil {
+		t.Fatal(err)
+	}
+	return pid
+}
+
+func waitGone(t *testing.T, pid int) {
+	deadline := time.Now().Add(5 * time.Second)
+	for {
+		if _, err := os.Stat("/proc/" + strconv.Itoa(pid)); os.IsNotExist(err) {
+			return
+		}
+		if time.Now().After(deadline) {
+			t.Fatalf("expected process %v to have been reaped\n", pid)
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+}
+
+func TestSubreaper(t *testing.T) {
+
+	s, err := EnableSubreaper()
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer s.Close()
+
+	if _, err := EnableSubreaper(); err != errSubreaperEnabled {
+		t.Fatalf("expected errSubreaperEnabled, got %v\n", err)
+	}
+
+	pid := startOrphan(t, "0.3")
+
+	stat, err := readProcStat(pid)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if stat.ppid != os.Getpid() {
+		t.Fatalf("expected orphan to be re-parented to us, got ppid %v\n", stat.ppid)
+	}
+
+	waitGone(t, pid)
+}
+
+func TestSubreaperKillOnClose(t *testing.T) {
+
+	s, err := EnableSubreaper()
+	if err != nil {
+		t.Fatal(err)
+	}
+	s.KillOnClose = true
+
+	pid := startOrphan(t, "10")
+
+	if err := s.Close(); err != nil {
+		t.Fatal(err)
+	}
+	waitGone(t, pid)
+}
@@ -0,0 +1,16 @@
+//go:build !linux
+
+package launcher
+
+import "errors"
+
+var errSubreaperUnsupported = errors.New("subreaper is only supported on linux")
+
+// EnableSubreaper is only supported on linux
+func EnableSubreaper() (*Subreaper, error) {
+	return nil, errSubreaperUnsupported
+}
+
+func (s *Subreaper) disable() error {
+	return nil
+}
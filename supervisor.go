@@ -0,0 +1,520 @@
+package launcher
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"sync"
+	"time"
+)
+
+var errSupervisorStarted = errors.New("supervisor has already been started")
+
+var errInvalidRestartPolicy = errors.New("invalid restart policy")
+
+// ErrLivenessFailed is the cause recorded for a process terminated
+// by a Supervisor after repeatedly failing its liveness check
+var ErrLivenessFailed = errors.New("process repeatedly failed its liveness check")
+
+const (
+	// defaultLivenessInterval is used if LivenessInterval is not set
+	defaultLivenessInterval = 10 * time.Second
+	// defaultLivenessFailures is used if LivenessFailures is not set
+	defaultLivenessFailures = 3
+)
+
+// RestartPolicy determines whether a Supervisor relaunches
+// its process after it exits
+type RestartPolicy int
+
+const (
+	// RestartNever leaves the process stopped once it exits
+	RestartNever RestartPolicy = iota
+	// RestartOnFailure relaunches the process if it exits unsuccessfully,
+	// unless its RetryClassifier deems the failure permanent
+	RestartOnFailure
+	// RestartAlways relaunches the process whenever it exits
+	RestartAlways
+)
+
+// restartPolicyNames are the names of the RestartPolicy values,
+// as used in their text encoding
+var restartPolicyNames = map[RestartPolicy]string{
+	RestartNever:     "never",
+	RestartOnFailure: "on-failure",
+	RestartAlways:    "always",
+}
+
+func (p RestartPolicy) String() string {
+	if name, ok := restartPolicyNames[p]; ok {
+		return name
+	}
+	return fmt.Sprintf("RestartPolicy(%d)", int(p))
+}
+
+// MarshalText encodes the policy as "never", "on-failure" or "always"
+func (p RestartPolicy) MarshalText() ([]byte, error) {
+	if name, ok := restartPolicyNames[p]; ok {
+		return []byte(name), nil
+	}
+	return nil, fmt.Errorf("%w: %d", errInvalidRestartPolicy, int(p))
+}
+
+// UnmarshalText decodes a policy encoded by MarshalText, with
+// an empty value decoded as RestartNever
+func (p *RestartPolicy) UnmarshalText(b []byte) error {
+	if len(b) == 0 {
+		*p = RestartNever
+		return nil
+	}
+	for policy, name := range restartPolicyNames {
+		if name == string(b) {
+			*p = policy
+			return nil
+		}
+	}
+	return fmt.Errorf("%w: %q", errInvalidRestartPolicy, b)
+}
+
+// EventType identifies the kind of an Event
+type EventType string
+
+const (
+	// EventStarted is sent when a process has been launched
+	EventStarted EventType = "started"
+	// EventExited is sent when a process has exited, with Err set
+	// if it was unsuccessful
+	EventExited EventType = "exited"
+	// EventLivenessFailed is sent when a liveness check fails, with Err
+	// set to the reason
+	EventLivenessFailed EventType = "liveness-failed"
+	// EventLivenessRestart is sent when a process is terminated
+	// after repeatedly failing its liveness check
+	EventLivenessRestart EventType = "liveness-restart"
+	// EventBinaryChanged is sent when a process is terminated because
+	// its executable changed, as set by WithRestartOnBinaryChange
+	EventBinaryChanged EventType = "binary-changed"
+	// EventSwapped is sent when a replacement process started by SwapWith
+	// takes over from the process it replaced
+	EventSwapped EventType = "swapped"
+)
+
+// Event describes a change in a supervised process
+type Event struct {
+	Type EventType
+	Pid  int
+	Time time.Time
+	Err  error
+	// CorrelationID is that of the supervised launches, if any
+	CorrelationID string
+}
+
+// Supervisor keeps a process created from a Spec running, relaunching
+// it according to its RestartPolicy.  The exported fields may be set
+// after NewSupervisor but must not be changed once Start is called
+type Supervisor struct {
+	// Restart determines whether the process is relaunched when it exits
+	Restart RestartPolicy
+	// RestartDelay is the pause before a relaunch
+	RestartDelay time.Duration
+	// MaxRestarts limits the number of relaunches, with 0 being unlimited
+	MaxRestarts int
+	// Stdout and Stderr receive the output of each process,
+	// which is discarded if nil
+	Stdout io.Writer
+	Stderr io.Writer
+	// Liveness, if set, is checked every LivenessInterval while the
+	// process is running.  After LivenessFailures consecutive failures,
+	// the process is terminated and relaunched, regardless of Restart
+	Liveness         Probe
+	LivenessInterval time.Duration
+	LivenessFailures int
+	// Events, if set, receives an Event for each change in the process.
+	// Events are dropped if the channel is not ready to receive them
+	Events chan<- Event
+
+	spec          Spec
+	correlationID string
+	ctx           context.Context
+	cancel        context.CancelFunc
+	mu            sync.Mutex
+	l             *Launcher
+	launching     *Launcher
+	starting      bool
+	started       bool
+	stopping      bool
+	restarts      int
+	unhealthy     bool
+	binaryChanged bool
+	next          *Launcher
+	ended         bool
+	err           error
+	stop          chan struct{}
+	done          chan struct{}
+}
+
+// NewSupervisor creates a new Supervisor for the Spec, which will
+// stop supervising when the context is cancelled
+func NewSupervisor(ctx context.Context, spec Spec) (*Supervisor, error) {
+	if ctx == nil {
+		return nil, ErrMissingContext
+	}
+	myCtx, cancel := context.WithCancel(ctx)
+
+	// Invalid options are reported when the process is launched
+	o, _ := spec.options()
+	o.correlate(ctx)
+
+	return &Supervisor{
+		spec:          spec,
+		correlationID: o.correlationID,
+		ctx:           myCtx,
+		cancel:        cancel,
+		stop:          make(chan struct{}),
+		done:          make(chan struct{}),
+	}, nil
+}
+
+// Start launches the process and begins supervising it
+func (s *Supervisor) Start() error {
+	s.mu.Lock()
+	if s.started || s.starting {
+		s.mu.Unlock()
+		return errSupervisorStarted
+	}
+	s.starting = true
+	s.mu.Unlock()
+
+	l, err := s.launch()
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.starting = false
+	if err == nil && s.stopping {
+		l.Close()
+		err = fmt.Errorf("%s: %w", s.spec.File, ErrCancelled)
+	}
+	if err != nil {
+		return err
+	}
+	s.l = l
+	s.started = true
+
+	s.emit(EventStarted, l.Pid(), nil)
+	s.goLabelled("supervise", func() { s.supervise(l) })
+	s.watch(l)
+
+	return nil
+}
+
+// Restarts returns the number of times the process has been relaunched
+func (s *Supervisor) Restarts() int {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	return s.restarts
+}
+
+// Pid returns the process id of the current process
+func (s *Supervisor) Pid() int {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.l == nil {
+		return 0
+	}
+	return s.l.Pid()
+}
+
+// Signal sends the signal to the current process
+func (s *Supervisor) Signal(sig os.Signal) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.l == nil {
+		return fmt.Errorf("%s: %w", s.spec.File, ErrNotStarted)
+	}
+	return s.l.Signal(sig)
+}
+
+// Reload asks the current process to reload its configuration, waiting
+// for its readiness Probe, if any, to pass again, as ReloadAndWaitReady
+func (s *Supervisor) Reload(ctx context.Context) error {
+	s.mu.Lock()
+	l := s.l
+	s.mu.Unlock()
+
+	if l == nil {
+		return fmt.Errorf("%s: %w", s.spec.File, ErrNotStarted)
+	}
+	return l.ReloadAndWaitReady(ctx)
+}
+
+// Stop ends supervision, asking the current process to terminate and
+// killing it if it has not exited within the grace period
+func (s *Supervisor) Stop(grace time.Duration) error {
+	s.mu.Lock()
+	if !s.stopping {
+		s.stopping = true
+		close(s.stop)
+	}
+	l := s.l
+	// A process still waiting for readiness is abandoned
+	if s.launching != nil {
+		s.launching.CancelWithCause(ErrCancelled)
+	}
+	s.mu.Unlock()
+
+	if l == nil {
+		s.cancel()
+		return nil
+	}
+
+	l.terminate(s.done, grace, ErrCancelled)
+	return nil
+}
+
+// Wait blocks until supervision has ended, returning the error from
+// the final process, or nil if supervision was ended by Stop
+func (s *Supervisor) Wait() error {
+	s.mu.Lock()
+	started := s.started
+	s.mu.Unlock()
+
+	if !started {
+		return fmt.Errorf("%s: %w", s.spec.File, ErrNotStarted)
+	}
+
+	<-s.done
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	return s.err
+}
+
+// launch creates and starts a new process from the Spec.  It must be
+// called without the lock held, as it waits for readiness, during
+// which Stop abandons the process
+func (s *Supervisor) launch() (*Launcher, error) {
+	s.mu.Lock()
+	l, err := s.spec.New(s.ctx)
+	if err != nil {
+		s.mu.Unlock()
+		return nil, err
+	}
+	s.launching = l
+	s.mu.Unlock()
+
+	err = l.Start()
+
+	s.mu.Lock()
+	s.launching = nil
+	s.mu.Unlock()
+
+	if err != nil {
+		l.Close()
+		return nil, err
+	}
+	return l, nil
+}
+
+// supervise waits for each process to exit, relaunching
+// it as required by the RestartPolicy
+func (s *Supervisor) supervise(l *Launcher) {
+	defer close(s.done)
+	defer s.cancel()
+	defer s.discardReplacement()
+
+	for {
+		l.copyOutput(s.Stdout, s.Stderr)
+		err := l.Wait()
+		l.Close()
+		s.emit(EventExited, l.Pid(), err)
+
+		s.mu.Lock()
+		if next := s.takeReplacement(); next != nil {
+			l = next
+			s.mu.Unlock()
+
+			s.emit(EventSwapped, l.Pid(), nil)
+			s.watch(l)
+			continue
+		}
+		s.err = err
+		if s.stopping {
+			s.err = nil
+		}
+		relaunch := s.shouldRestart(l, err)
+		s.ended = !relaunch
+		s.mu.Unlock()
+
+		if !relaunch {
+			return
+		}
+
+		select {
+		case <-s.ctx.Done():
+			return
+		case <-s.stop:
+			return
+		case <-time.After(s.RestartDelay):
+		}
+
+		s.mu.Lock()
+		if s.stopping {
+			s.err = nil
+			s.ended = true
+			s.mu.Unlock()
+			return
+		}
+		if next := s.takeReplacement(); next != nil {
+			l = next
+			s.mu.Unlock()
+
+			s.emit(EventSwapped, l.Pid(), nil)
+			s.watch(l)
+			continue
+		}
+		s.mu.Unlock()
+
+		launched, err := s.launch()
+
+		s.mu.Lock()
+		if s.stopping {
+			s.err = nil
+			s.ended = true
+			s.mu.Unlock()
+			if launched != nil {
+				launched.Close()
+			}
+			return
+		}
+		if err != nil {
+			s.err = err
+			s.ended = true
+			s.mu.Unlock()
+			return
+		}
+		// A replacement made by SwapWith during the launch is preferred
+		if next := s.takeReplacement(); next != nil {
+			l = next
+			s.mu.Unlock()
+
+			launched.Close()
+			s.emit(EventSwapped, l.Pid(), nil)
+			s.watch(l)
+			continue
+		}
+		l = launched
+		s.l = l
+		s.restarts++
+		s.mu.Unlock()
+
+		s.emit(EventStarted, l.Pid(), nil)
+		s.watch(l)
+	}
+}
+
+// watch starts watching the liveness and executable of the process,
+// as required
+func (s *Supervisor) watch(l *Launcher) {
+	s.goLabelled("liveness", func() { s.monitor(l) })
+	if l.opts.binaryRestart {
+		// The executable is examined before watch returns, so that
+		// a change immediately after the launch is not missed
+		if initial, err := os.Stat(l.GetPath()); err == nil {
+			s.goLabelled("binary-watch", func() { s.watchBinary(l, initial) })
+		}
+	}
+}
+
+// monitor checks the liveness of the process until it exits,
+// terminating it if it repeatedly fails the check
+func (s *Supervisor) monitor(l *Launcher) {
+	if s.Liveness == nil {
+		return
+	}
+
+	interval := s.LivenessInterval
+	if interval <= 0 {
+		interval = defaultLivenessInterval
+	}
+	threshold := s.LivenessFailures
+	if threshold <= 0 {
+		threshold = defaultLivenessFailures
+	}
+
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	failures := 0
+	for {
+		select {
+		case <-l.done:
+			return
+		case <-ticker.C:
+		}
+
+		ctx, cancel := context.WithTimeout(s.ctx, interval)
+		err := s.Liveness.check(ctx, l)
+		cancel()
+
+		if err == nil {
+			failures = 0
+			continue
+		}
+
+		failures++
+		s.emit(EventLivenessFailed, l.Pid(), err)
+
+		if failures >= threshold {
+			s.mu.Lock()
+			s.unhealthy = true
+			s.mu.Unlock()
+
+			s.emit(EventLivenessRestart, l.Pid(), err)
+			l.terminate(l.done, defaultStopGrace, ErrLivenessFailed)
+			return
+		}
+	}
+}
+
+// emit sends an Event, if there is a receiver ready for it
+func (s *Supervisor) emit(typ EventType, pid int, err error) {
+	if s.Events == nil {
+		return
+	}
+	select {
+	case s.Events <- Event{Type: typ, Pid: pid, Time: time.Now(), Err: err, CorrelationID: s.correlationID}:
+	default:
+	}
+}
+
+// shouldRestart returns true if the process should be relaunched after
+// exiting with the error, and must be called with the lock held
+func (s *Supervisor) shouldRestart(l *Launcher, err error) bool {
+	if s.stopping || s.ctx.Err() != nil {
+		return false
+	}
+	if s.MaxRestarts > 0 && s.restarts >= s.MaxRestarts {
+		return false
+	}
+	if s.unhealthy || s.binaryChanged || errors.Is(l.Cause(), ErrHeartbeatMissed) {
+		s.unhealthy = false
+		s.binaryChanged = false
+		return true
+	}
+
+	switch s.Restart {
+	case RestartAlways:
+		return true
+	case RestartOnFailure:
+		return l.opts.isRetryable(l.ExitCode(), err)
+	default:
+		return false
+	}
+}
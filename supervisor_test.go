@@ -0,0 +1,235 @@
+package launcher
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestSupervisorWithNilCtx(t *testing.T) {
+
+	_, err := NewSupervisor(nil, Spec{File: "echo"})
+	if err != ErrMissingContext {
+		t.Fatal(err)
+	}
+}
+
+func TestSupervisorRestartOnFailure(t *testing.T) {
+
+	s, err := NewSupervisor(context.Background(), Spec{File: "sh", Args: []string{"-c", "echo foo; exit 1"}})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var out bytes.Buffer
+	s.Restart = RestartOnFailure
+	s.MaxRestarts = 2
+	s.Stdout = &out
+
+	if err := s.Start(); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := s.Wait(); err == nil {
+		t.Fatal("expected error from failing process")
+	}
+
+	if s.Restarts() != 2 {
+		t.Fatalf("expected 2 restarts, got %v\n", s.Restarts())
+	}
+
+	if strings.Count(out.String(), "foo") != 3 {
+		t.Fatalf("expected output from 3 runs, got %q\n", out.String())
+	}
+}
+
+func TestSupervisorPermanentFailure(t *testing.T) {
+
+	spec := Spec{
+		File:    "sh",
+		Args:    []string{"-c", "exit 2"},
+		Options: []Option{WithRetryableExitCodes(75)},
+	}
+	s, err := NewSupervisor(context.Background(), spec)
+	if err != nil {
+		t.Fatal(err)
+	}
+	s.Restart = RestartOnFailure
+	s.MaxRestarts = 2
+
+	if err := s.Start(); err != nil {
+		t.Fatal(err)
+	}
+	if err := s.Wait(); err == nil {
+		t.Fatal("expected error from failing process")
+	}
+	if s.Restarts() != 0 {
+		t.Fatalf("expected no restarts after permanent failure, got %v\n", s.Restarts())
+	}
+}
+
+func TestSupervisorNoRestartOnSuccess(t *testing.T) {
+
+	s, err := NewSupervisor(context.Background(), Spec{File: "echo", Args: []string{"foo"}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	s.Restart = RestartOnFailure
+
+	if err := s.Start(); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := s.Wait(); err != nil {
+		t.Fatal(err)
+	}
+
+	if s.Restarts() != 0 {
+		t.Fatalf("expected no restarts, got %v\n", s.Restarts())
+	}
+}
+
+func TestSupervisorStop(t *testing.T) {
+
+	s, err := NewSupervisor(context.Background(), Spec{File: "sleep", Args: []string{"10"}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	s.Restart = RestartAlways
+
+	if err := s.Start(); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := s.Start(); err != errSupervisorStarted {
+		t.Fatal(err)
+	}
+
+	start := time.Now()
+	if err := s.Stop(time.Second); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := s.Wait(); err != nil {
+		t.Fatal(err)
+	}
+
+	if time.Since(start) > 5*time.Second {
+		t.Fatal("stop took too long")
+	}
+
+	if s.Restarts() != 0 {
+		t.Fatalf("expected no restarts, got %v\n", s.Restarts())
+	}
+}
+
+func TestSupervisorLiveness(t *testing.T) {
+
+	s, err := NewSupervisor(context.Background(), Spec{File: "sleep", Args: []string{"10"}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer s.Stop(time.Second)
+
+	events := make(chan Event, 100)
+	s.Liveness = ExecProbe("false")
+	s.LivenessInterval = 50 * time.Millisecond
+	s.LivenessFailures = 2
+	s.Events = events
+
+	if err := s.Start(); err != nil {
+		t.Fatal(err)
+	}
+
+	seen := map[EventType]int{}
+	timeout := time.After(5 * time.Second)
+	for seen[EventStarted] < 2 {
+		select {
+		case e := <-events:
+			seen[e.Type]++
+		case <-timeout:
+			t.Fatalf("timed out waiting for restart: %v\n", seen)
+		}
+	}
+
+	if seen[EventLivenessFailed] < 2 || seen[EventLivenessRestart] < 1 || seen[EventExited] < 1 {
+		t.Fatalf("unexpected events: %v\n", seen)
+	}
+
+	if s.Restarts() < 1 {
+		t.Fatal("expected restart after liveness failure")
+	}
+}
+
+func TestSupervisorLivenessHealthy(t *testing.T) {
+
+	s, err := NewSupervisor(context.Background(), Spec{File: "sleep", Args: []string{"10"}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer s.Stop(time.Second)
+
+	s.Liveness = ProbeFunc(func(ctx context.Context) error { return nil })
+	s.LivenessInterval = 20 * time.Millisecond
+
+	if err := s.Start(); err != nil {
+		t.Fatal(err)
+	}
+
+	time.Sleep(200 * time.Millisecond)
+
+	if s.Restarts() != 0 {
+		t.Fatalf("expected no restarts, got %v\n", s.Restarts())
+	}
+}
+
+func TestSupervisorStopDuringRelaunch(t *testing.T) {
+
+	dir := t.TempDir()
+	ready, relaunched := filepath.Join(dir, "ready"), filepath.Join(dir, "relaunched")
+
+	// The first run becomes ready and fails, and the relaunch never becomes ready
+	script := fmt.Sprintf("if [ -f %[2]s ]; then exec sleep 10; fi; touch %[1]s; sleep 0.5; rm %[1]s; touch %[2]s; exit 1", ready, relaunched)
+	spec := Spec{
+		File:    "sh",
+		Args:    []string{"-c", script},
+		Options: []Option{WithReadiness(FileProbe(ready))},
+	}
+	s, err := NewSupervisor(context.Background(), spec)
+	if err != nil {
+		t.Fatal(err)
+	}
+	s.Restart = RestartOnFailure
+
+	if err := s.Start(); err != nil {
+		t.Fatal(err)
+	}
+
+	for {
+		if _, err := os.Stat(relaunched); err == nil {
+			break
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+	time.Sleep(200 * time.Millisecond)
+
+	stopped := make(chan struct{})
+	go func() {
+		s.Pid()
+		s.Stop(time.Second)
+		close(stopped)
+	}()
+	select {
+	case <-stopped:
+	case <-time.After(5 * time.Second):
+		t.Fatal("expected Stop to abandon a relaunch awaiting readiness")
+	}
+	if err := s.Wait(); err != nil {
+		t.Fatalf("expected nil after Stop, got %v\n", err)
+	}
+}
@@ -0,0 +1,105 @@
+package launcher
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+)
+
+// ErrSwapped is the cause recorded for a process terminated
+// by a Supervisor after being replaced by SwapWith
+var ErrSwapped = errors.New("process was replaced")
+
+var errSupervisorEnded = errors.New("supervision has ended")
+
+var errSwapInProgress = errors.New("a swap is already in progress")
+
+// SwapWith replaces the supervised process with one created from the
+// Spec, without interrupting service.  The replacement is started and
+// must pass its readiness Probe, giving up when the context ends, before
+// the current process is asked to terminate, and is killed if it has not
+// exited within the grace period.  If the replacement fails to become
+// ready it is discarded, and the current process is left running.  The
+// Spec is used for any subsequent restarts
+func (s *Supervisor) SwapWith(ctx context.Context, spec Spec, grace time.Duration) error {
+	if ctx == nil {
+		return ErrMissingContext
+	}
+	if err := s.swappable(); err != nil {
+		return err
+	}
+
+	next, err := spec.New(s.ctx)
+	if err != nil {
+		return err
+	}
+	if err := next.StartAndWaitReady(ctx); err != nil {
+		next.Close()
+		return fmt.Errorf("%s: replacement not ready: %w", spec.File, err)
+	}
+
+	s.mu.Lock()
+	if err := s.swappableLocked(); err != nil {
+		s.mu.Unlock()
+		next.Close()
+		return err
+	}
+	o, _ := spec.options()
+	o.correlate(s.ctx)
+	s.spec = spec
+	s.correlationID = o.correlationID
+	s.next = next
+	current := s.l
+	s.mu.Unlock()
+
+	current.terminate(current.done, grace, ErrSwapped)
+	return nil
+}
+
+// swappable returns an error if a swap cannot be made
+func (s *Supervisor) swappable() error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	return s.swappableLocked()
+}
+
+// swappableLocked returns an error if a swap cannot be made,
+// and must be called with the lock held
+func (s *Supervisor) swappableLocked() error {
+	switch {
+	case !s.started || s.l == nil:
+		return fmt.Errorf("%s: %w", s.spec.File, ErrNotStarted)
+	case s.ended || s.stopping || s.ctx.Err() != nil:
+		return fmt.Errorf("%s: %w", s.spec.File, errSupervisorEnded)
+	case s.next != nil:
+		return fmt.Errorf("%s: %w", s.spec.File, errSwapInProgress)
+	}
+	return nil
+}
+
+// takeReplacement returns any replacement set by SwapWith, making it
+// the current process, and must be called with the lock held
+func (s *Supervisor) takeReplacement() *Launcher {
+	next := s.next
+	if next != nil {
+		s.next = nil
+		s.l = next
+	}
+	return next
+}
+
+// discardReplacement closes any replacement which
+// was not taken before supervision ended
+func (s *Supervisor) discardReplacement() {
+	s.mu.Lock()
+	s.ended = true
+	next := s.next
+	s.next = nil
+	s.mu.Unlock()
+
+	if next != nil {
+		next.Close()
+	}
+}
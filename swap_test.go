@@ -0,0 +1,103 @@
+package launcher
+
+import (
+	"context"
+	"regexp"
+	"testing"
+	"time"
+)
+
+func TestSupervisorSwapWith(t *testing.T) {
+
+	s, err := NewSupervisor(context.Background(), Spec{File: "sleep", Args: []string{"10"}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	events := make(chan Event, 10)
+	s.Restart = RestartAlways
+	s.Events = events
+
+	if err := s.Start(); err != nil {
+		t.Fatal(err)
+	}
+	defer s.Stop(time.Second)
+
+	old := s.Pid()
+
+	spec := Spec{
+		File:    "sh",
+		Args:    []string{"-c", "echo ready; exec sleep 10"},
+		Options: []Option{WithReadiness(StdOutProbe(regexp.MustCompile("ready")))},
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	if err := s.SwapWith(ctx, spec, time.Second); err != nil {
+		t.Fatal(err)
+	}
+
+	for {
+		select {
+		case e := <-events:
+			if e.Type != EventSwapped {
+				continue
+			}
+			if e.Pid == old || e.Pid != s.Pid() {
+				t.Fatalf("expected swap to new process, got %v (old %v, current %v)\n", e.Pid, old, s.Pid())
+			}
+			if s.Restarts() != 0 {
+				t.Fatalf("expected no restarts, got %v\n", s.Restarts())
+			}
+			return
+		case <-time.After(5 * time.Second):
+			t.Fatal("timed out waiting for swap")
+		}
+	}
+}
+
+func TestSupervisorSwapWithRollback(t *testing.T) {
+
+	s, err := NewSupervisor(context.Background(), Spec{File: "sleep", Args: []string{"10"}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := s.Start(); err != nil {
+		t.Fatal(err)
+	}
+	defer s.Stop(time.Second)
+
+	old := s.Pid()
+
+	spec := Spec{
+		File:    "sh",
+		Args:    []string{"-c", "exit 1"},
+		Options: []Option{WithReadiness(StdOutProbe(regexp.MustCompile("ready")))},
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	if err := s.SwapWith(ctx, spec, time.Second); err == nil {
+		t.Fatal("expected error from replacement failing readiness")
+	}
+
+	time.Sleep(100 * time.Millisecond)
+	if s.Pid() != old {
+		t.Fatalf("expected original process %v to remain, got %v\n", old, s.Pid())
+	}
+	select {
+	case <-s.done:
+		t.Fatal("expected supervision to continue after rollback")
+	default:
+	}
+}
+
+func TestSupervisorSwapWithNotStarted(t *testing.T) {
+
+	s, err := NewSupervisor(context.Background(), Spec{File: "sleep", Args: []string{"10"}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := s.SwapWith(context.Background(), Spec{File: "sleep", Args: []string{"10"}}, time.Second); err == nil {
+		t.Fatal("expected error swapping before Start")
+	}
+}
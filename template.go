@@ -0,0 +1,79 @@
+package launcher
+
+import (
+	"context"
+	"strings"
+	"text/template"
+)
+
+// Template is a Spec whose arguments and environment may contain
+// text/template placeholders, such as {{.Input}}, which are rendered
+// from the data supplied for each Launcher created from it
+type Template struct {
+	spec Spec
+	args []*template.Template
+	env  []*template.Template
+}
+
+// NewTemplate parses the placeholders in the arguments and
+// environment of the Spec
+func NewTemplate(spec Spec) (*Template, error) {
+	t := &Template{spec: spec}
+
+	parse := func(values []string) ([]*template.Template, error) {
+		parsed := make([]*template.Template, len(values))
+		for i, v := range values {
+			tmpl, err := template.New("").Option("missingkey=error").Parse(v)
+			if err != nil {
+				return nil, err
+			}
+			parsed[i] = tmpl
+		}
+		return parsed, nil
+	}
+
+	var err error
+	if t.args, err = parse(spec.Args); err != nil {
+		return nil, err
+	}
+	if t.env, err = parse(spec.Env); err != nil {
+		return nil, err
+	}
+	return t, nil
+}
+
+// Instantiate renders the placeholders from the data, which is typically
+// a struct or map, and creates a new, unstarted Launcher from the result.
+// A placeholder referring to a missing map key is an error
+func (t *Template) Instantiate(ctx context.Context, data any) (*Launcher, error) {
+	spec, err := t.Render(data)
+	if err != nil {
+		return nil, err
+	}
+	return spec.New(ctx)
+}
+
+// Render returns the Spec with its placeholders rendered from the data
+func (t *Template) Render(data any) (Spec, error) {
+	render := func(parsed []*template.Template) ([]string, error) {
+		values := make([]string, len(parsed))
+		for i, tmpl := range parsed {
+			var b strings.Builder
+			if err := tmpl.Execute(&b, data); err != nil {
+				return nil, err
+			}
+			values[i] = b.String()
+		}
+		return values, nil
+	}
+
+	spec := t.spec
+	var err error
+	if spec.Args, err = render(t.args); err != nil {
+		return Spec{}, err
+	}
+	if spec.Env, err = render(t.env); err != nil {
+		return Spec{}, err
+	}
+	return spec, nil
+}
@@ -0,0 +1,58 @@
+package launcher
+
+import (
+	"context"
+	"io"
+	"testing"
+)
+
+func TestTemplateInstantiate(t *testing.T) {
+
+	tmpl, err := NewTemplate(Spec{
+		File: "sh",
+		Env:  []string{"OUT={{.OutputDir}}"},
+		Args: []string{"-c", "echo $0 $OUT", "{{.Input}}"},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	inputs := []string{"a.txt", "b c.txt"}
+	for _, input := range inputs {
+		data := struct{ Input, OutputDir string }{input, "/tmp/out"}
+
+		l, err := tmpl.Instantiate(context.Background(), data)
+		if err != nil {
+			t.Fatal(err)
+		}
+		defer l.Close()
+
+		if err := l.Run(); err != nil {
+			t.Fatal(err)
+		}
+		b, err := io.ReadAll(l.StdOutReader())
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		expected := input + " /tmp/out\n"
+		if string(b) != expected {
+			t.Fatalf("invalid response - expected %q, got %q\n", expected, string(b))
+		}
+	}
+}
+
+func TestTemplateErrors(t *testing.T) {
+
+	if _, err := NewTemplate(Spec{File: "echo", Args: []string{"{{.Input"}}); err == nil {
+		t.Fatal("expected invalid placeholder to fail")
+	}
+
+	tmpl, err := NewTemplate(Spec{File: "echo", Args: []string{"{{.Input}}"}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := tmpl.Instantiate(context.Background(), map[string]string{"Other": "x"}); err == nil {
+		t.Fatal("expected missing key to fail")
+	}
+}
@@ -0,0 +1,51 @@
+package launcher
+
+import "time"
+
+// StartedAt returns the time at which the process was launched, or was
+// adopted, which is the zero time if it has not been started
+func (l *Launcher) StartedAt() time.Time {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	return l.startedAt
+}
+
+// FinishedAt returns the time at which the process was found to have
+// exited, which is the zero time until it has been reaped
+func (l *Launcher) FinishedAt() time.Time {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	return l.finishedAt
+}
+
+// Uptime returns how long the process has been running, which is zero
+// if it has not been started or has exited
+func (l *Launcher) Uptime() time.Duration {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	if l.startedAt.IsZero() || !l.finishedAt.IsZero() {
+		return 0
+	}
+	return time.Since(l.startedAt)
+}
+
+// Duration returns how long the process ran, measured until now if it
+// is still running, and which is zero if it has not been started.  The
+// monotonic clock is used, so the result is unaffected by changes to
+// the wall clock
+func (l *Launcher) Duration() time.Duration {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	switch {
+	case l.startedAt.IsZero():
+		return 0
+	case l.finishedAt.IsZero():
+		return time.Since(l.startedAt)
+	default:
+		return l.finishedAt.Sub(l.startedAt)
+	}
+}
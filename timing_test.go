@@ -0,0 +1,44 @@
+package launcher
+
+import (
+	"context"
+	"testing"
+	"time"
+)
+
+func TestLauncherTiming(t *testing.T) {
+
+	l, err := New(context.Background(), "sleep", nil, "0.2")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer l.Close()
+
+	if !l.StartedAt().IsZero() || l.Uptime() != 0 || l.Duration() != 0 {
+		t.Fatal("expected no timings before start")
+	}
+
+	before := time.Now()
+	if err := l.Start(); err != nil {
+		t.Fatal(err)
+	}
+	if l.StartedAt().Before(before) || !l.FinishedAt().IsZero() {
+		t.Fatalf("unexpected start %v and finish %v\n", l.StartedAt(), l.FinishedAt())
+	}
+	if l.Uptime() <= 0 || l.Duration() <= 0 {
+		t.Fatal("expected positive uptime and duration while running")
+	}
+
+	if err := l.Wait(); err != nil {
+		t.Fatal(err)
+	}
+
+	d := l.Duration()
+	if d < 200*time.Millisecond || d != l.FinishedAt().Sub(l.StartedAt()) || l.Uptime() != 0 {
+		t.Fatalf("unexpected duration %v and uptime %v\n", d, l.Uptime())
+	}
+	time.Sleep(10 * time.Millisecond)
+	if l.Duration() != d {
+		t.Fatal("expected duration to be fixed once finished")
+	}
+}
@@ -0,0 +1,230 @@
+package launcher
+
+import (
+	"encoding/json"
+	"errors"
+	"io"
+	"sync"
+	"time"
+	"unicode/utf8"
+)
+
+var errMissingWriter = errors.New("writer must be provided")
+var errInvalidFormat = errors.New("transcript format is not supported")
+
+// TranscriptFormat is the format in which WithTranscript records a session
+type TranscriptFormat int
+
+const (
+	// TranscriptJSONLines records each event as a JSON object on its own
+	// line, such as {"time":0.25,"stream":"stdout","data":"ok\n"}, where
+	// time is in seconds since the process was started
+	TranscriptJSONLines TranscriptFormat = iota
+	// TranscriptAsciicast records the session as an asciinema v2 file,
+	// which may be replayed by asciinema.  Since the format has no
+	// separate stderr, it is recorded with stdout as output events
+	TranscriptAsciicast
+)
+
+// asciicast terminal size, recorded in the header
+const (
+	asciicastWidth  = 80
+	asciicastHeight = 24
+)
+
+// WithTranscript records the stdin, stdout and stderr of the process to
+// w as they are written, with their times, so that a session driven
+// through the Launcher can be replayed.  Stdin is recorded as it is
+// written by SendStdIn or the stdin options, and is not recorded if
+// stdin is inherited.  Writes to w are made from the goroutines pumping
+// the output, and any error ends the transcript
+func WithTranscript(w io.Writer, format TranscriptFormat) Option {
+	return func(o *options) error {
+		if w == nil {
+			return errMissingWriter
+		}
+		if format != TranscriptJSONLines && format != TranscriptAsciicast {
+			return errInvalidFormat
+		}
+		o.transcript = w
+		o.transcriptFormat = format
+		return nil
+	}
+}
+
+// prepareTranscript taps stdin and the output of the process
+// into the transcript, if required
+func (l *Launcher) prepareTranscript() {
+	if l.opts.transcript == nil {
+		return
+	}
+	l.transcript = &transcript{w: l.opts.transcript, format: l.opts.transcriptFormat}
+	l.tapStdOut(&transcriptWriter{t: l.transcript, stream: "stdout"})
+	l.tapStdErr(&transcriptWriter{t: l.transcript, stream: "stderr"})
+	if _, ok := l.cmdWriter.(inheritedStdin); !ok {
+		l.cmdWriter = &transcriptStdin{WriteCloser: l.cmdWriter, t: l.transcript}
+	}
+}
+
+// transcript records events to w, holding those recorded
+// before the process is started until it has begun
+type transcript struct {
+	mu      sync.Mutex
+	w       io.Writer
+	format  TranscriptFormat
+	start   time.Time
+	pending []transcriptEvent
+	partial map[string][]byte
+	err     error
+}
+
+// transcriptEvent is data written to a stream
+type transcriptEvent struct {
+	stream string
+	data   []byte
+}
+
+// begin records the start of the process, writing any header
+// followed by the events held until now
+func (t *transcript) begin(start time.Time) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	t.start = start
+	if t.format == TranscriptAsciicast {
+		t.writeJSON(map[string]any{
+			"version":   2,
+			"width":     asciicastWidth,
+			"height":    asciicastHeight,
+			"timestamp": start.Unix(),
+		})
+	}
+	for _, e := range t.pending {
+		t.write(0, e.stream, e.data)
+	}
+	t.pending = nil
+}
+
+// record writes an event for the data written to the stream, holding
+// back any incomplete character at its end until the rest is written,
+// so that a character split across writes is recorded whole
+func (t *transcript) record(stream string, b []byte) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	if held := t.partial[stream]; len(held) > 0 {
+		b = append(held, b...)
+		delete(t.partial, stream)
+	}
+	if n := incompleteRune(b); n > 0 {
+		if t.partial == nil {
+			t.partial = map[string][]byte{}
+		}
+		t.partial[stream] = append([]byte{}, b[len(b)-n:]...)
+		b = b[:len(b)-n]
+	}
+	t.event(stream, b)
+}
+
+// flush writes an event for any incomplete character
+// held at the end of the data written to the stream
+func (t *transcript) flush(stream string) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	t.event(stream, t.partial[stream])
+	delete(t.partial, stream)
+}
+
+// event writes an event for the data, or holds it if the process has
+// not yet begun, and must be called with the lock held
+func (t *transcript) event(stream string, b []byte) {
+	if len(b) == 0 {
+		return
+	}
+	if t.start.IsZero() {
+		t.pending = append(t.pending, transcriptEvent{stream, append([]byte{}, b...)})
+		return
+	}
+	t.write(time.Since(t.start).Seconds(), stream, b)
+}
+
+// incompleteRune returns the length of the incomplete UTF-8 encoding
+// of a character at the end of b, if any
+func incompleteRune(b []byte) int {
+	for i := 1; i < utf8.UTFMax && i <= len(b); i++ {
+		if utf8.RuneStart(b[len(b)-i]) {
+			if utf8.FullRune(b[len(b)-i:]) {
+				return 0
+			}
+			return i
+		}
+	}
+	return 0
+}
+
+// write writes an event, and must be called with the lock held
+func (t *transcript) write(elapsed float64, stream string, b []byte) {
+	switch t.format {
+	case TranscriptAsciicast:
+		code := "o"
+		if stream == "stdin" {
+			code = "i"
+		}
+		t.writeJSON([]any{elapsed, code, string(b)})
+	default:
+		t.writeJSON(struct {
+			Time   float64 `json:"time"`
+			Stream string  `json:"stream"`
+			Data   string  `json:"data"`
+		}{elapsed, stream, string(b)})
+	}
+}
+
+// writeJSON writes v as a line of JSON, and must be called with the lock held
+func (t *transcript) writeJSON(v any) {
+	if t.err != nil {
+		return
+	}
+	b, err := json.Marshal(v)
+	if err == nil {
+		_, err = t.w.Write(append(b, '\n'))
+	}
+	t.err = err
+}
+
+// transcriptWriter records the output written to it
+type transcriptWriter struct {
+	t      *transcript
+	stream string
+}
+
+func (w *transcriptWriter) Write(b []byte) (int, error) {
+	w.t.record(w.stream, b)
+	return len(b), nil
+}
+
+// Flush records any incomplete character held at the end of the output
+func (w *transcriptWriter) Flush() {
+	w.t.flush(w.stream)
+}
+
+// transcriptStdin records the bytes written to stdin
+type transcriptStdin struct {
+	io.WriteCloser
+	t *transcript
+}
+
+func (w *transcriptStdin) Write(b []byte) (int, error) {
+	n, err := w.WriteCloser.Write(b)
+	if n > 0 {
+		w.t.record("stdin", b[:n])
+	}
+	return n, err
+}
+
+// Close records any incomplete character held at the end of stdin
+func (w *transcriptStdin) Close() error {
+	w.t.flush("stdin")
+	return w.WriteCloser.Close()
+}
@@ -0,0 +1,145 @@
+package launcher
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/json"
+	"testing"
+	"time"
+)
+
+func TestTranscriptJSONLines(t *testing.T) {
+
+	var b bytes.Buffer
+	l, err := NewWithOptions(context.Background(), "sh", nil, []string{"-c", "read line; echo got $line; echo err >&2"},
+		WithTranscript(&b, TranscriptJSONLines))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer l.Close()
+
+	// Input written before the process starts is recorded at time 0
+	if err := l.SendStdIn([]byte("hello\n")); err != nil {
+		t.Fatal(err)
+	}
+	if err := l.Start(); err != nil {
+		t.Fatal(err)
+	}
+	l.copyOutput(nil, nil)
+	if err := l.Wait(); err != nil {
+		t.Fatal(err)
+	}
+
+	type event struct {
+		Time   float64 `json:"time"`
+		Stream string  `json:"stream"`
+		Data   string  `json:"data"`
+	}
+	found := map[string]string{}
+	scanner := bufio.NewScanner(&b)
+	for scanner.Scan() {
+		var e event
+		if err := json.Unmarshal(scanner.Bytes(), &e); err != nil {
+			t.Fatalf("invalid event %q: %v\n", scanner.Text(), err)
+		}
+		if e.Stream == "stdin" && e.Time != 0 {
+			t.Fatalf("expected stdin at time 0, got %v\n", e.Time)
+		}
+		found[e.Stream] += e.Data
+	}
+
+	expected := map[string]string{"stdin": "hello\n", "stdout": "got hello\n", "stderr": "err\n"}
+	for stream, data := range expected {
+		if found[stream] != data {
+			t.Fatalf("expected %q on %v, got %q\n", data, stream, found[stream])
+		}
+	}
+}
+
+func TestTranscriptAsciicast(t *testing.T) {
+
+	var b bytes.Buffer
+	l, err := NewWithOptions(context.Background(), "cat", nil, nil, WithTranscript(&b, TranscriptAsciicast), WithStdinBytes([]byte("hi\n")))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer l.Close()
+
+	if err := l.Start(); err != nil {
+		t.Fatal(err)
+	}
+	l.copyOutput(nil, nil)
+	l.Wait()
+
+	scanner := bufio.NewScanner(&b)
+	if !scanner.Scan() {
+		t.Fatal("expected a header\n")
+	}
+	var header struct {
+		Version int `json:"version"`
+		Width   int `json:"width"`
+	}
+	if err := json.Unmarshal(scanner.Bytes(), &header); err != nil || header.Version != 2 || header.Width == 0 {
+		t.Fatalf("unexpected header %q\n", scanner.Text())
+	}
+
+	codes := ""
+	for scanner.Scan() {
+		var e []any
+		if err := json.Unmarshal(scanner.Bytes(), &e); err != nil || len(e) != 3 {
+			t.Fatalf("unexpected event %q\n", scanner.Text())
+		}
+		if e[2] != "hi\n" {
+			t.Fatalf("unexpected data in %q\n", scanner.Text())
+		}
+		codes += e[1].(string)
+	}
+	if codes != "io" {
+		t.Fatalf("expected input then output events, got %q\n", codes)
+	}
+}
+
+func TestTranscriptSplitCharacter(t *testing.T) {
+
+	var b bytes.Buffer
+	tr := &transcript{w: &b, format: TranscriptJSONLines}
+	tr.begin(time.Now())
+
+	// The characters are split across writes, with an incomplete
+	// character left at the end of the output
+	w := &transcriptWriter{t: tr, stream: "stdout"}
+	data := []byte("h\u00e9llo \u4e16\u754c \U0001f600")
+	for _, chunk := range [][]byte{data[:2], data[2:9], data[9:11], data[11:15], data[15:], {0xe4, 0xb8}} {
+		w.Write(chunk)
+	}
+	w.Flush()
+
+	var found []byte
+	scanner := bufio.NewScanner(&b)
+	for scanner.Scan() {
+		var e struct {
+			Data string `json:"data"`
+		}
+		if err := json.Unmarshal(scanner.Bytes(), &e); err != nil {
+			t.Fatalf("invalid event %q: %v\n", scanner.Text(), err)
+		}
+		found = append(found, e.Data...)
+	}
+
+	// Only the bytes left incomplete at the end are replaced
+	expected := string(data) + "\ufffd\ufffd"
+	if string(found) != expected {
+		t.Fatalf("expected %q, got %q\n", expected, found)
+	}
+}
+
+func TestTranscriptInvalid(t *testing.T) {
+
+	if _, err := NewWithOptions(context.Background(), "cat", nil, nil, WithTranscript(nil, TranscriptJSONLines)); err != errMissingWriter {
+		t.Fatalf("expected errMissingWriter, got %v\n", err)
+	}
+	if _, err := NewWithOptions(context.Background(), "cat", nil, nil, WithTranscript(&bytes.Buffer{}, TranscriptFormat(9))); err != errInvalidFormat {
+		t.Fatalf("expected errInvalidFormat, got %v\n", err)
+	}
+}
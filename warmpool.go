@@ -0,0 +1,126 @@
+package launcher
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+)
+
+// warmPoolRetryDelay is the pause before relaunching
+// a process which failed to start
+const warmPoolRetryDelay = time.Second
+
+// WarmPool keeps a number of processes created from a Spec started and
+// idle, handing them out on request, so that workloads which launch the
+// same tool many times do not wait for it to be started and made ready
+// each time.  Each process is replaced as soon as it is handed out
+type WarmPool struct {
+	spec   Spec
+	parent context.Context
+	ctx    context.Context
+	cancel context.CancelFunc
+	idle   chan *Launcher
+	wg     sync.WaitGroup
+	mu     sync.Mutex
+	err    error
+}
+
+// NewWarmPool creates a WarmPool keeping size processes started, which
+// are terminated when the context is cancelled, unless handed out
+func NewWarmPool(ctx context.Context, spec Spec, size int) (*WarmPool, error) {
+	if ctx == nil {
+		return nil, ErrMissingContext
+	}
+	if size <= 0 {
+		return nil, errInvalidSize
+	}
+	myCtx, cancel := context.WithCancel(ctx)
+
+	p := &WarmPool{
+		spec:   spec,
+		parent: ctx,
+		ctx:    myCtx,
+		cancel: cancel,
+		idle:   make(chan *Launcher),
+	}
+	p.wg.Add(size)
+	for i := 0; i < size; i++ {
+		goLabelled(spec.File, "", "warm-pool", p.keepWarm)
+	}
+	return p, nil
+}
+
+// Get returns a started process, waiting for one to become idle if
+// necessary.  The process belongs to the caller, who must Close it.  If
+// the context ends first, its error is returned, joined with the error
+// from the most recent failure to start a process, if any
+func (p *WarmPool) Get(ctx context.Context) (*Launcher, error) {
+	if ctx == nil {
+		return nil, ErrMissingContext
+	}
+
+	select {
+	case l := <-p.idle:
+		return l, nil
+	case <-p.ctx.Done():
+		return nil, fmt.Errorf("%s: %w", p.spec.File, ErrAlreadyClosed)
+	case <-ctx.Done():
+		p.mu.Lock()
+		defer p.mu.Unlock()
+
+		return nil, errors.Join(ctx.Err(), p.err)
+	}
+}
+
+// Close terminates the idle processes and stops replacing them.
+// Processes already handed out are unaffected
+func (p *WarmPool) Close() error {
+	p.cancel()
+	p.wg.Wait()
+	return nil
+}
+
+// keepWarm keeps a single idle process, replacing it once it is handed
+// out, or if it exits before then
+func (p *WarmPool) keepWarm() {
+	defer p.wg.Done()
+
+	for p.ctx.Err() == nil {
+		l, err := p.launch()
+		if err != nil {
+			p.mu.Lock()
+			p.err = err
+			p.mu.Unlock()
+
+			select {
+			case <-p.ctx.Done():
+			case <-time.After(warmPoolRetryDelay):
+			}
+			continue
+		}
+
+		select {
+		case p.idle <- l:
+		case <-l.done:
+			l.Close()
+		case <-p.ctx.Done():
+			l.Close()
+		}
+	}
+}
+
+// launch creates and starts a new process from the Spec, which outlives
+// the pool so that it is not terminated once handed out
+func (p *WarmPool) launch() (*Launcher, error) {
+	l, err := p.spec.New(p.parent)
+	if err != nil {
+		return nil, err
+	}
+	if err := l.Start(); err != nil {
+		l.Close()
+		return nil, err
+	}
+	return l, nil
+}
@@ -0,0 +1,73 @@
+package launcher
+
+import (
+	"context"
+	"errors"
+	"io"
+	"testing"
+	"time"
+)
+
+func TestWarmPool(t *testing.T) {
+
+	p, err := NewWarmPool(context.Background(), Spec{File: "cat"}, 2)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	pids := map[int]bool{}
+	for i := 0; i < 5; i++ {
+		l, err := p.Get(context.Background())
+		if err != nil {
+			t.Fatal(err)
+		}
+		if !l.IsRunning() || pids[l.Pid()] {
+			t.Fatalf("expected a new running process, got pid %v\n", l.Pid())
+		}
+		pids[l.Pid()] = true
+
+		w := l.StdinWriter()
+		w.Write([]byte("hello"))
+		w.Close()
+		b, err := io.ReadAll(l)
+		if err != nil || string(b) != "hello" {
+			t.Fatalf("unexpected output %q (%v)\n", b, err)
+		}
+		l.Close()
+	}
+
+	// A process handed out is unaffected by closing the pool
+	l, err := p.Get(context.Background())
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer l.Close()
+
+	p.Close()
+	if !l.IsRunning() {
+		t.Fatal("expected process to outlive the pool")
+	}
+	if _, err := p.Get(context.Background()); !errors.Is(err, ErrAlreadyClosed) {
+		t.Fatalf("expected ErrAlreadyClosed, got %v\n", err)
+	}
+}
+
+func TestWarmPoolLaunchFailure(t *testing.T) {
+
+	p, err := NewWarmPool(context.Background(), Spec{File: "sh", Options: []Option{WithStdinBytes(nil), WithInheritStdio()}}, 1)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer p.Close()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 100*time.Millisecond)
+	defer cancel()
+
+	if _, err := p.Get(ctx); !errors.Is(err, context.DeadlineExceeded) || !errors.Is(err, errStdinInherited) {
+		t.Fatalf("expected deadline and launch errors, got %v\n", err)
+	}
+
+	if _, err := NewWarmPool(context.Background(), Spec{File: "cat"}, 0); err == nil {
+		t.Fatal("expected error for zero size")
+	}
+}
@@ -0,0 +1,122 @@
+package launcher
+
+import (
+	"os"
+	"runtime"
+	"strings"
+	"time"
+)
+
+// tempDirAttempts is the number of attempts made to remove a temporary
+// working directory, whose files may briefly remain in use on exit
+const tempDirAttempts = 5
+
+// tempDirRetryDelay is the delay before the first retry of a removal,
+// which doubles for each subsequent retry
+const tempDirRetryDelay = 50 * time.Millisecond
+
+// tmpdirVars are the environment variables naming the temporary directory
+var tmpdirVars = func() []string {
+	if runtime.GOOS == "windows" {
+		return []string{"TMPDIR", "TEMP", "TMP"}
+	}
+	return []string{"TMPDIR"}
+}()
+
+// tempWorkdir describes the temporary working directory of a process
+type tempWorkdir struct {
+	pattern string
+	tmpdir  bool
+}
+
+// WithDir runs the process in the directory, in place of
+// the working directory of the current process
+func WithDir(dir string) Option {
+	return func(o *options) error {
+		o.dir = dir
+		return nil
+	}
+}
+
+// WithTempWorkdir runs the process in a new temporary directory, named
+// from the pattern as for os.MkdirTemp, which is removed with its
+// contents by Close.  If setTMPDIR is true, the directory is also set as
+// TMPDIR (and TEMP and TMP on Windows) in the environment of the process
+func WithTempWorkdir(pattern string, setTMPDIR bool) Option {
+	return func(o *options) error {
+		o.tempWorkdir = &tempWorkdir{pattern: pattern, tmpdir: setTMPDIR}
+		return nil
+	}
+}
+
+// Workdir returns the working directory of the process, or
+// an empty string if it runs in that of the current process
+func (l *Launcher) Workdir() string {
+	if l.cmd == nil {
+		return ""
+	}
+	return l.cmd.Dir
+}
+
+// makeWorkdir sets the working directory, creating a temporary
+// directory if required
+func (l *Launcher) makeWorkdir() error {
+	w := l.opts.tempWorkdir
+	if w == nil {
+		l.cmd.Dir = l.opts.dir
+		return nil
+	}
+
+	dir, err := os.MkdirTemp("", w.pattern)
+	if err != nil {
+		return err
+	}
+	l.tempDir = dir
+	l.cmd.Dir = dir
+
+	if w.tmpdir {
+		l.cmd.Env = setEnv(l.cmd.Env, tmpdirVars, dir)
+	}
+	return nil
+}
+
+// removeTempWorkdir removes the temporary working directory, retrying
+// whilst files within it cannot be removed
+func (l *Launcher) removeTempWorkdir() error {
+	if l.tempDir == "" {
+		return nil
+	}
+
+	var err error
+	delay := tempDirRetryDelay
+	for i := 0; i < tempDirAttempts; i++ {
+		if err = os.RemoveAll(l.tempDir); err == nil {
+			return nil
+		}
+		time.Sleep(delay)
+		delay *= 2
+	}
+	return err
+}
+
+// setEnv sets each of the variables to the value, replacing any
+// existing values in the environment
+func setEnv(env []string, names []string, value string) []string {
+	out := make([]string, 0, len(env)+len(names))
+	for _, kv := range env {
+		k, _, _ := strings.Cut(kv, "=")
+		keep := true
+		for _, n := range names {
+			if strings.EqualFold(k, n) {
+				keep = false
+			}
+		}
+		if keep {
+			out = append(out, kv)
+		}
+	}
+	for _, n := range names {
+		out = append(out, n+"="+value)
+	}
+	return out
+}
@@ -0,0 +1,74 @@
+package launcher
+
+import (
+	"context"
+	"errors"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestTempWorkdir(t *testing.T) {
+
+	l, err := NewWithOptions(context.Background(), "sh", []string{"TMPDIR=/zzz"}, []string{"-c", "pwd; echo $TMPDIR; touch file; mkdir sub; touch sub/file"}, WithTempWorkdir("launcher-*", true))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	dir := l.Workdir()
+	if !strings.HasPrefix(filepath.Base(dir), "launcher-") {
+		t.Fatalf("unexpected working directory %q\n", dir)
+	}
+
+	if err := l.Run(); err != nil {
+		t.Fatal(err)
+	}
+	b, err := io.ReadAll(l.StdOutReader())
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// The working directory may be reported through symlinks
+	resolved, err := filepath.EvalSymlinks(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	lines := strings.Split(strings.TrimSpace(string(b)), "\n")
+	if len(lines) != 2 || (lines[0] != dir && lines[0] != resolved) || lines[1] != dir {
+		t.Fatalf("expected process to run in %v, got %q\n", dir, lines)
+	}
+
+	if err := l.Close(); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := os.Stat(dir); !errors.Is(err, os.ErrNotExist) {
+		t.Fatalf("expected working directory to be removed, got %v\n", err)
+	}
+}
+
+func TestTempWorkdirUnstarted(t *testing.T) {
+
+	l, err := NewWithOptions(context.Background(), "echo", nil, nil, WithTempWorkdir("", false))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	dir := l.Workdir()
+	if _, err := os.Stat(dir); err != nil {
+		t.Fatal(err)
+	}
+	for _, kv := range l.GetEnv() {
+		if strings.HasPrefix(kv, "TMPDIR=") {
+			t.Fatalf("expected TMPDIR not to be set, got %v\n", l.GetEnv())
+		}
+	}
+
+	if err := l.Close(); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := os.Stat(dir); !errors.Is(err, os.ErrNotExist) {
+		t.Fatalf("expected working directory to be removed, got %v\n", err)
+	}
+}
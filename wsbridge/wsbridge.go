@@ -0,0 +1,123 @@
+// Package wsbridge connects the stdin and output of a launcher.Launcher
+// to a WebSocket, allowing web terminals to drive launched processes.
+//
+// Binary frames received from the client are written to the stdin of
+// the process, and its stdout and stderr are sent to the client as
+// binary frames.  Text frames carry JSON encoded Messages: the server
+// sends an "exit" message carrying the exit code before closing the
+// connection once the process has exited, and text frames from the
+// client are ignored.
+//
+// Resizing the terminal is not supported, as a Launcher connects its
+// process to pipes rather than a pseudo-terminal.
+//
+// The process is cancelled if the client disconnects.
+package wsbridge
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"sync"
+
+	"github.com/coder/websocket"
+	"github.com/gford1000-go/launcher"
+)
+
+// TypeExit reports that the process has exited
+const TypeExit = "exit"
+
+// Message is a control message, sent as a JSON encoded text frame
+type Message struct {
+	Type string `json:"type"`
+	Code int    `json:"code"`
+}
+
+// Handler is an http.Handler that upgrades each request to a
+// WebSocket and bridges it to a newly created Launcher
+type Handler struct {
+	// New creates the Launcher for the request, which is started by the Handler
+	New func(r *http.Request) (*launcher.Launcher, error)
+	// AcceptOptions are used when upgrading the request, and may be nil
+	AcceptOptions *websocket.AcceptOptions
+}
+
+// ServeHTTP creates the Launcher and bridges it to the WebSocket
+func (h *Handler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	l, err := h.New(r)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	defer l.Close()
+
+	c, err := websocket.Accept(w, r, h.AcceptOptions)
+	if err != nil {
+		return
+	}
+
+	Serve(r.Context(), c, l)
+}
+
+// Serve bridges the WebSocket to the Launcher, starting the Launcher if
+// required, and returns once the process has exited or the client has
+// disconnected.  The connection is closed on return
+func Serve(ctx context.Context, c *websocket.Conn, l *launcher.Launcher) error {
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	if !l.IsStarted() {
+		if err := l.Start(); err != nil {
+			c.Close(websocket.StatusInternalError, "failed to start process")
+			return err
+		}
+	}
+
+	go func() {
+		defer l.Cancel()
+		for {
+			typ, b, err := c.Read(ctx)
+			if err != nil {
+				return
+			}
+			if typ != websocket.MessageBinary {
+				continue
+			}
+			if err := l.SendStdIn(b); err != nil {
+				return
+			}
+		}
+	}()
+
+	var wg sync.WaitGroup
+	forward := func(r io.Reader) {
+		defer wg.Done()
+		b := make([]byte, 32*1024)
+		for {
+			n, err := r.Read(b)
+			if n > 0 {
+				if werr := c.Write(ctx, websocket.MessageBinary, b[:n]); werr != nil {
+					return
+				}
+			}
+			if err != nil {
+				return
+			}
+		}
+	}
+
+	wg.Add(2)
+	go forward(l.StdOutReader())
+	go forward(l.StdErrReader())
+	wg.Wait()
+
+	err := l.Wait()
+
+	if b, merr := json.Marshal(Message{Type: TypeExit, Code: l.ExitCode()}); merr == nil {
+		c.Write(ctx, websocket.MessageText, b)
+	}
+	c.Close(websocket.StatusNormalClosure, "")
+
+	return err
+}
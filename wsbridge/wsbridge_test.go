@@ -0,0 +1,86 @@
+package wsbridge
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/coder/websocket"
+	"github.com/gford1000-go/launcher"
+)
+
+func dial(t *testing.T, spec launcher.Spec) *websocket.Conn {
+	srv := httptest.NewServer(&Handler{
+		New: func(r *http.Request) (*launcher.Launcher, error) {
+			return spec.New(context.Background())
+		},
+	})
+	t.Cleanup(srv.Close)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	t.Cleanup(cancel)
+
+	c, _, err := websocket.Dial(ctx, "ws"+strings.TrimPrefix(srv.URL, "http"), nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { c.CloseNow() })
+	return c
+}
+
+func TestServeEcho(t *testing.T) {
+
+	c := dial(t, launcher.Spec{File: "cat"})
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	if err := c.Write(ctx, websocket.MessageBinary, []byte("hello\n")); err != nil {
+		t.Fatal(err)
+	}
+
+	typ, b, err := c.Read(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if typ != websocket.MessageBinary || string(b) != "hello\n" {
+		t.Fatalf("unexpected message: %v %q\n", typ, b)
+	}
+}
+
+func TestServeExit(t *testing.T) {
+
+	c := dial(t, launcher.Spec{File: "sh", Args: []string{"-c", "echo hi; exit 3"}})
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	var out []byte
+	for {
+		typ, b, err := c.Read(ctx)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if typ == websocket.MessageBinary {
+			out = append(out, b...)
+			continue
+		}
+
+		var m Message
+		if err := json.Unmarshal(b, &m); err != nil {
+			t.Fatal(err)
+		}
+		if m.Type != TypeExit || m.Code != 3 {
+			t.Fatalf("unexpected message: %+v\n", m)
+		}
+		break
+	}
+
+	if string(out) != "hi\n" {
+		t.Fatalf("unexpected output: %q\n", out)
+	}
+}